@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"arena/config"
+	"arena/lesion"
+	"arena/rng"
+	"arena/study"
+	"paragon"
+)
+
+const lesionUsage = `usage: arena lesion -model FILE -data NAME [-type float32|float64] [-n 2000] [-keep-scale]
+                    [-seed N [-step NAME]] [-run 0] [-group neuron|block:N] [-layers 1,2] [-top 20] [-o FILE]`
+
+// lesionCmd lesions a saved model's neurons one group at a time on a
+// registered dataset and writes the per-layer salience maps.
+func lesionCmd(args []string) error {
+	fs := flag.NewFlagSet("lesion", flag.ContinueOnError)
+	model := fs.String("model", "", "saved model (SaveJSON); a replay manifest next to it is applied")
+	typ := fs.String("type", "float32", "numeric type the model was saved with: float32 or float64")
+	data := fs.String("data", "", "dataset: "+strings.Join(datasetNames(), ", "))
+	n := fs.Int("n", 2000, "samples to score on, drawn from the test split (0 for all)")
+	keepScale := fs.Bool("keep-scale", false, "the model was trained on unscaled inputs (study.Protocol.KeepScale)")
+	seed := fs.Int64("seed", 0, "master seed of the study run that trained the model (default: from seeds.jsonl next to the model)")
+	step := fs.String("step", "", "step whose streams that run's study drew from, e.g. crossDataset")
+	run := fs.Int("run", 0, "the study's run number the model was trained in")
+	group := fs.String("group", "neuron", "lesion one neuron at a time, or block:N for NxN tiles")
+	layers := fs.String("layers", "", "comma-separated layers to lesion (default: all after the input)")
+	top := fs.Int("top", 20, "lesions to list, largest drop first (0 for all)")
+	out := fs.String("o", "", "salience file (default <model>.salience.json)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *model == "" || *data == "" || fs.NArg() > 0 {
+		return errors.New(lesionUsage)
+	}
+	ls, err := parseLayers(*layers)
+	if err != nil {
+		return err
+	}
+	size := 0
+	if *group != "neuron" {
+		v, ok := strings.CutPrefix(*group, "block:")
+		if size, err = strconv.Atoi(v); !ok || err != nil || size < 1 {
+			return fmt.Errorf("-group %q: want neuron or block:N", *group)
+		}
+	}
+	var master *int64
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			master = seed
+		}
+	})
+	seeds, err := lesionSeeds(*model, *data, *run, master, *step)
+	if err != nil {
+		return err
+	}
+	x, y, err := lesionData(*data, *n, *keepScale, seeds, *run)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = strings.TrimSuffix(*model, filepath.Ext(*model)) + ".salience.json"
+	}
+
+	switch *typ {
+	case "float32":
+		return runLesion[float32](*model, x, y, size, ls, *top, *out)
+	case "float64":
+		return runLesion[float64](*model, x, y, size, ls, *top, *out)
+	}
+	return fmt.Errorf("-type %q: want float32 or float64", *typ)
+}
+
+func runLesion[T paragon.Numeric](path string, x, y [][][]float64, size int, layers []int, top int, out string) error {
+	net, _, err := config.LoadModel[T](path)
+	if err != nil {
+		return err
+	}
+	groups, err := lesion.Singles(net, layers...)
+	if size > 0 {
+		groups, err = lesion.Blocks(net, size, layers...)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🧠 %s: %d lesions on %d samples\n", path, len(groups), len(x))
+	step := max(1, len(groups)/20)
+	rep, err := lesion.Analyze(net, x, y, groups, lesion.Options{OnEffect: func(i int, e lesion.Effect) {
+		if (i+1)%step == 0 || i == len(groups)-1 {
+			fmt.Printf("  %d/%d lesions\n", i+1, len(groups))
+		}
+	}})
+	if err != nil {
+		return err
+	}
+	if err := lesion.WriteEffects(os.Stdout, "LESIONS", rep, top); err != nil {
+		return err
+	}
+	if err := lesion.WriteMaps(os.Stdout, rep.Maps); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, append(b, '\n'), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("📄 Salience maps written to %s\n", out)
+	return nil
+}
+
+// lesionSeeds returns the seeds study.Run split the model's dataset
+// from: -seed and -step when -seed is given, else those of the latest
+// manifest in the model's seeds.jsonl that derived the dataset's run.
+// It returns nil when there is neither.
+func lesionSeeds(model, name string, run int, seed *int64, step string) (*rng.Seeds, error) {
+	under := func(master int64, step string) *rng.Seeds {
+		if step == "" {
+			return rng.New(master)
+		}
+		return rng.New(master).Sub(step)
+	}
+	if seed != nil {
+		return under(*seed, step), nil
+	}
+
+	path := filepath.Join(filepath.Dir(model), rng.ManifestFile)
+	ms, err := rng.ReadManifests(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	stream := fmt.Sprintf("dataset=%s/run=%d", name, run)
+	for i := len(ms) - 1; i >= 0; i-- {
+		m := ms[i]
+		for _, step := range []string{m.Step, ""} {
+			key := stream
+			if step != "" {
+				key = step + "/" + stream
+			}
+			if _, ok := m.Streams[key]; ok {
+				fmt.Printf("🎲 %s run %d: seed %d, step %q from %s\n", name, run, m.Master, step, path)
+				return under(m.Master, step), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// lesionData prepares a registered dataset the way study trains on it,
+// split, balanced and min-max scaled by the training split's range unless
+// keepScale, and returns up to n rows of its test split. With seeds, the
+// split is run's of study.Run under those seeds; without, only datasets
+// with a fixed test split are accepted, since any other split would mix
+// the model's training rows into the test rows.
+func lesionData(name string, n int, keepScale bool, seeds *rng.Seeds, run int) (x, y [][][]float64, err error) {
+	d, ok := study.Lookup(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown dataset %q (have %s)", name, strings.Join(datasetNames(), ", "))
+	}
+	fixed := seeds == nil
+	if fixed {
+		seeds = rng.FromEnv()
+	}
+	rows, err := d.Load(seeds.Subf("dataset=%s", name).Rand(rng.Noise))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if fixed && rows.TestX == nil {
+		return nil, nil, fmt.Errorf("%s has no fixed test split: pass -seed (and -step, -run) of the study run that trained the model", name)
+	}
+	p := study.Protocol{KeepScale: keepScale, MaxTest: n}
+	if n <= 0 {
+		p.MaxTest = math.MaxInt
+	}
+	data, err := study.Prepare(name, rows, p, seeds.Subf("dataset=%s/run=%d", name, run))
+	if err != nil {
+		return nil, nil, err
+	}
+	return data.TestX, data.TestY, nil
+}
+
+func datasetNames() []string {
+	var names []string
+	for _, d := range study.Datasets() {
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+func parseLayers(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var ls []int
+	for _, f := range strings.Split(s, ",") {
+		l, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, fmt.Errorf("-layers %q: %w", s, err)
+		}
+		ls = append(ls, l)
+	}
+	return ls, nil
+}
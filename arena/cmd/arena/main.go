@@ -0,0 +1,184 @@
+// Command arena lists and runs the experiments in this repository from one
+// place.
+//
+//	arena list [experiment]
+//	arena run <experiment>[/<step>] [-seed N] [args...]
+//	arena report <run-id> [-baseline <run-id>] [-o file.html]
+//	arena store <import|runs|best|regressions> [flags]
+//	arena lesion -model FILE -data NAME [flags]
+//
+// Experiments that register steps with arena/experiment can be run one step
+// at a time; the rest run as a whole. Each run is `go run .` inside the
+// experiment directory, so the experiment's own go.mod still applies.
+//
+// report looks the run up in every experiment's results file and writes a
+// self-contained HTML page of its records. store copies every run into one
+// file-based store (runstore/ by default) and answers leaderboard and
+// regression queries across runs and commits. lesion knocks out a saved
+// model's neurons one at a time or in blocks on a registered dataset and
+// writes per-layer salience maps of the accuracy lost.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  arena list [experiment]
+  arena run <experiment>[/<step>] [-seed N] [args...]
+  arena report <run-id> [-baseline <run-id>] [-o file.html]
+  arena store <import|runs|best|regressions> [flags]
+  arena lesion -model FILE -data NAME [flags]
+
+flags:`)
+	flag.PrintDefaults()
+}
+
+func main() {
+	root := flag.String("root", "", "repository root (default: found from the working directory)")
+	flag.Usage = usage
+	flag.Parse()
+
+	if *root == "" {
+		dir, ok := findRoot(".")
+		if !ok {
+			fatal(errors.New("cannot find the repository root; pass -root"))
+		}
+		*root = dir
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	exps, err := scan(*root)
+	if err != nil {
+		fatal(err)
+	}
+
+	switch args[0] {
+	case "list":
+		filter := ""
+		if len(args) > 1 {
+			filter = args[1]
+		}
+		list(exps, filter)
+	case "run":
+		if len(args) < 2 {
+			usage()
+			os.Exit(2)
+		}
+		err = run(exps, args[1], args[2:])
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.ExitCode())
+		}
+		if err != nil {
+			fatal(err)
+		}
+	case "report":
+		if err := reportRun(*root, args[1:]); err != nil {
+			fatal(err)
+		}
+	case "store":
+		if err := storeCmd(*root, args[1:]); err != nil {
+			fatal(err)
+		}
+	case "lesion":
+		if err := lesionCmd(args[1:]); err != nil {
+			fatal(err)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func list(exps []Experiment, filter string) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer tw.Flush()
+	for _, e := range exps {
+		if filter != "" && e.Name != filter {
+			continue
+		}
+		if e.Steps == nil {
+			fmt.Fprintf(tw, "%s\t\t(whole program)\n", e.Name)
+			continue
+		}
+		for _, s := range e.Steps {
+			mark := ""
+			if s.Optional {
+				mark = "(optional)"
+			}
+			fmt.Fprintf(tw, "%s/%s\t%s\t%s\n", e.Name, s.Name, mark, s.Doc)
+		}
+	}
+}
+
+// run starts target, which is "experiment" or "experiment/step". args are
+// passed through to the experiment's registry (-seed and anything else).
+func run(exps []Experiment, target string, args []string) error {
+	name, step, _ := strings.Cut(target, "/")
+	var exp *Experiment
+	for i := range exps {
+		if exps[i].Name == name {
+			exp = &exps[i]
+		}
+	}
+	if exp == nil {
+		return fmt.Errorf("unknown experiment %q (try arena list)", name)
+	}
+
+	goArgs := []string{"run", "."}
+	switch {
+	case step != "":
+		if exp.Steps == nil {
+			return fmt.Errorf("%s does not register steps; run it as a whole with arena run %s", name, name)
+		}
+		if !hasStep(exp.Steps, step) {
+			return fmt.Errorf("%s has no step %q (try arena list %s)", name, step, name)
+		}
+		goArgs = append(goArgs, "run", step)
+	case exp.Steps == nil && hasFlag(args, "seed"):
+		fmt.Fprintf(os.Stderr, "warning: %s does not register steps and ignores -seed\n", name)
+	}
+	goArgs = append(goArgs, args...)
+
+	cmd := exec.Command("go", goArgs...)
+	cmd.Dir = exp.Dir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+func hasStep(steps []Step, name string) bool {
+	for _, s := range steps {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFlag(args []string, name string) bool {
+	for _, a := range args {
+		a = strings.TrimLeft(a, "-")
+		if a == name || strings.HasPrefix(a, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "arena:", err)
+	os.Exit(1)
+}
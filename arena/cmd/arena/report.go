@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"arena/report"
+)
+
+// reportRun writes the HTML report of one run, found in the results files
+// of every experiment under root.
+func reportRun(root string, args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	baseline := fs.String("baseline", "", "run ID to compare against")
+	out := fs.String("o", "", "output file (default report-<run-id>.html)")
+	var ids []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		ids = append(ids, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(ids) != 1 {
+		return errors.New("usage: arena report <run-id> [-baseline <run-id>] [-o file.html]")
+	}
+
+	paths := report.ResultsFiles(root)
+	run, err := report.Find(paths, ids[0])
+	if err != nil {
+		return err
+	}
+	var base *report.Run
+	if *baseline != "" {
+		if base, err = report.Find(paths, *baseline); err != nil {
+			return err
+		}
+	}
+
+	path := *out
+	if path == "" {
+		path = fmt.Sprintf("report-%s.html", run.ID)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := report.Write(f, run, base); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("📄 %s: %d records of %s → %s\n", run.ID, len(run.Records), run.Experiment, path)
+	return nil
+}
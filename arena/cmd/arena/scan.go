@@ -0,0 +1,141 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const registryImport = "arena/experiment"
+
+// Experiment is one directory with its own go.mod next to arena.
+type Experiment struct {
+	Name  string
+	Dir   string
+	Steps []Step // nil when the experiment does not use the registry
+}
+
+// Step is a step found in an experiment's source.
+type Step struct {
+	Name     string
+	Doc      string
+	Optional bool
+}
+
+// findRoot walks up from dir to the directory holding arena/go.mod.
+func findRoot(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "arena", "go.mod")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// scan lists the experiments under root. Steps are read from the source
+// rather than by running each binary, so listing needs neither a build nor
+// the datasets.
+func scan(root string) ([]Experiment, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var exps []Experiment
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == "arena" || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+			continue
+		}
+		steps, err := scanSteps(dir)
+		if err != nil {
+			return nil, err
+		}
+		exps = append(exps, Experiment{Name: e.Name(), Dir: dir, Steps: steps})
+	}
+	sort.Slice(exps, func(i, j int) bool { return exps[i].Name < exps[j].Name })
+	return exps, nil
+}
+
+// scanSteps collects experiment.Register and experiment.Optional calls
+// whose name and doc are string literals.
+func scanSteps(dir string) ([]Step, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var steps []Step
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		pkg := importName(f)
+		if pkg == "" {
+			continue
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) < 2 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if id, ok := sel.X.(*ast.Ident); !ok || id.Name != pkg {
+				return true
+			}
+			if sel.Sel.Name != "Register" && sel.Sel.Name != "Optional" {
+				return true
+			}
+			name, ok1 := stringLit(call.Args[0])
+			doc, ok2 := stringLit(call.Args[1])
+			if ok1 && ok2 {
+				steps = append(steps, Step{Name: name, Doc: doc, Optional: sel.Sel.Name == "Optional"})
+			}
+			return true
+		})
+	}
+	return steps, nil
+}
+
+func importName(f *ast.File) string {
+	for _, imp := range f.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == registryImport {
+			if imp.Name != nil {
+				return imp.Name.Name
+			}
+			return filepath.Base(registryImport)
+		}
+	}
+	return ""
+}
+
+func stringLit(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"arena/report"
+	"arena/store"
+)
+
+const storeUsage = `usage:
+  arena store import
+  arena store runs [-experiment NAME]
+  arena store best [-experiment NAME] [-step STEP] [-metric accuracy] [-where k=v]... [-by PARAM] [-top N]
+  arena store regressions -experiment NAME [-metric accuracy] [-base COMMIT] [-head COMMIT] [-alpha 0.05] [-all]`
+
+// where collects repeated -where k=v flags.
+type where map[string]string
+
+func (w where) String() string { return fmt.Sprint(map[string]string(w)) }
+
+func (w where) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("want param=value, got %q", s)
+	}
+	w[k] = v
+	return nil
+}
+
+// storeCmd runs one of the run store commands. Every command first
+// imports new runs from the experiments' results files.
+func storeCmd(root string, args []string) error {
+	if len(args) == 0 {
+		return errors.New(storeUsage)
+	}
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet("store "+cmd, flag.ContinueOnError)
+	dir := fs.String("store", filepath.Join(root, "runstore"), "run store directory")
+	experiment := fs.String("experiment", "", "only runs of this experiment")
+	step := fs.String("step", "", "only records of this step")
+	metric := fs.String("metric", "accuracy", `metric to rank or compare ("adhd" for the ADHD score)`)
+	by := fs.String("by", "", "param to group the leaderboard by, e.g. hidden")
+	top := fs.Int("top", 0, "configurations per group (default 1 with -by, else 10)")
+	filter := where{}
+	fs.Var(filter, "where", "param=value the records must have (repeatable)")
+	base := fs.String("base", "", "baseline commit (default: the one before -head)")
+	head := fs.String("head", "", "commit to check (default: the latest run's)")
+	alpha := fs.Float64("alpha", 0.05, "significance level")
+	all := fs.Bool("all", false, "list every change, not only those for the worse")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := store.Open(*dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range report.ResultsFiles(root) {
+		added, err := s.Import(path)
+		if err != nil {
+			return err
+		}
+		if len(added) > 0 {
+			fmt.Printf("📥 %d new runs from %s\n", len(added), path)
+		}
+	}
+
+	out := os.Stdout
+	switch cmd {
+	case "import":
+		fmt.Printf("🗄️  %d runs in %s\n", len(s.Runs("")), s.Dir())
+		return nil
+
+	case "runs":
+		return store.WriteRuns(out, "RUNS", s.Runs(*experiment))
+
+	case "best":
+		recs, err := s.Query(store.Query{Experiment: *experiment, Step: *step, Where: filter})
+		if err != nil {
+			return err
+		}
+		n := *top
+		if n == 0 {
+			n = 10
+			if *by != "" {
+				n = 1
+			}
+		}
+		title := "BEST BY " + strings.ToUpper(*metric)
+		if *by != "" {
+			title += " PER " + strings.ToUpper(*by)
+		}
+		return store.WriteBest(out, title, *by, store.Best(recs, *metric, *by, n))
+
+	case "regressions":
+		if *experiment == "" {
+			return errors.New("regressions needs -experiment")
+		}
+		h := *head
+		if h == "" {
+			var ok bool
+			if h, ok = s.LatestCommit(*experiment); !ok {
+				return fmt.Errorf("no stored runs of %s", *experiment)
+			}
+		}
+		b := *base
+		if b == "" {
+			var ok bool
+			if b, ok = s.PreviousCommit(*experiment, h); !ok {
+				return fmt.Errorf("no run of %s before commit %s", *experiment, h)
+			}
+		}
+		recs, err := s.Query(store.Query{Experiment: *experiment, Step: *step, Where: filter})
+		if err != nil {
+			return err
+		}
+		title := fmt.Sprintf("%s: %s AT %s vs %s", *experiment, strings.ToUpper(*metric), h, b)
+		return store.WriteChanges(out, title, store.Compare(recs, *metric, b, h, *alpha), *all)
+	}
+	return errors.New(storeUsage)
+}
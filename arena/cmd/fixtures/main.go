@@ -0,0 +1,38 @@
+// Command fixtures fills a dataset cache with synthetic stand-ins so the
+// experiments can run with ARENA_OFFLINE=1, e.g. on CI:
+//
+//	go run ./cmd/fixtures -dir /tmp/arena-cache -symbols AAPL
+//	ARENA_CACHE=/tmp/arena-cache ARENA_OFFLINE=1 go run .
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"arena/datasets/cache"
+	"arena/datasets/fixtures"
+)
+
+func main() {
+	dir := flag.String("dir", "", "cache directory (default: $ARENA_CACHE or the user cache dir)")
+	seed := flag.Int64("seed", 1, "generator seed")
+	symbols := flag.String("symbols", "AAPL", "comma-separated Alpha Vantage tickers to stub")
+	flag.Parse()
+
+	c := cache.Default()
+	if *dir != "" {
+		c = cache.New(*dir, true)
+	}
+	var syms []string
+	for _, s := range strings.Split(*symbols, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			syms = append(syms, s)
+		}
+	}
+	if err := fixtures.Populate(c, *seed, syms...); err != nil {
+		log.Fatalf("populate %s: %v", c.Dir, err)
+	}
+	fmt.Printf("Wrote fixtures to %s\n", c.Dir)
+}
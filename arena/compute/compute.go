@@ -0,0 +1,239 @@
+// Package compute counts the work a network does, so replay can be judged
+// against the extra compute it spends. Work is counted in neuron
+// evaluations and multiply-accumulates (one per input connection).
+//
+//	c := compute.Attach(net)
+//	c.Train(len(trainX), epochs)
+//	net.Train(trainX, trainY, epochs, lr, true, 5, -5)
+//	c.Infer(len(testX))
+//	res := eval.Evaluate(net, testX, testY)
+//	metrics := c.Metrics(res.Accuracy)
+//
+// The accounting follows paragon's replay model:
+//   - a replay of layer l re-runs layers l+ReplayOffset (at least 1)
+//     through l once more, before or after the normal pass;
+//   - a static layer (MaxReplay > 0, no ReplayGateToReps) replays MaxReplay
+//     times on every forward pass;
+//   - a dynamic layer replays what its policy returns, capped at
+//     ReplayBudget, and is counted by wrapping the policy;
+//   - a backward pass costs BackwardFactor times the forward pass it
+//     follows, replays included.
+//
+// Train and Infer assume every sample is passed once per epoch; Train's
+// early stop on negative loss makes the training count an upper bound.
+package compute
+
+import (
+	"fmt"
+	"sync"
+
+	"paragon"
+)
+
+// BackwardFactor is the cost of a backward pass relative to its forward
+// pass: gradients with respect to inputs and to weights each cost about
+// one forward pass.
+const BackwardFactor = 2.0
+
+// Cost is an amount of work.
+type Cost struct {
+	Neurons float64 // neuron evaluations
+	MACs    float64 // multiply-accumulates
+}
+
+// Add returns c + o.
+func (c Cost) Add(o Cost) Cost { return Cost{c.Neurons + o.Neurons, c.MACs + o.MACs} }
+
+// Scale returns k·c.
+func (c Cost) Scale(k float64) Cost { return Cost{k * c.Neurons, k * c.MACs} }
+
+// String prints the MACs with a unit.
+func (c Cost) String() string { return Format(c.MACs) }
+
+// Layers returns the cost of one pass through each layer. Layer 0 is the
+// input and costs nothing.
+func Layers[T paragon.Numeric](net *paragon.Network[T]) []Cost {
+	costs := make([]Cost, len(net.Layers))
+	for l := 1; l < len(net.Layers); l++ {
+		layer := &net.Layers[l]
+		for _, row := range layer.Neurons {
+			for _, n := range row {
+				costs[l].Neurons++
+				costs[l].MACs += float64(len(n.Inputs))
+			}
+		}
+	}
+	return costs
+}
+
+// Span returns the first and last layer a replay of layer l re-runs.
+func Span(l, offset int) (from, to int) {
+	return max(1, min(l+offset, l)), l
+}
+
+// Plain returns the cost of one forward pass without any replay.
+func Plain[T paragon.Numeric](net *paragon.Network[T]) Cost {
+	var c Cost
+	for _, lc := range Layers(net) {
+		c = c.Add(lc)
+	}
+	return c
+}
+
+// Forward returns the cost of one forward pass with every static layer
+// replaying MaxReplay times and dynamic layers not replaying; dynamic
+// replays are counted as they happen by a Counter.
+func Forward[T paragon.Numeric](net *paragon.Network[T]) Cost {
+	layers := Layers(net)
+	c := Plain(net)
+	for l := 1; l < len(net.Layers); l++ {
+		layer := &net.Layers[l]
+		if layer.MaxReplay > 0 && layer.ReplayGateToReps == nil {
+			c = c.Add(spanCost(layers, l, layer.ReplayOffset).Scale(float64(layer.MaxReplay)))
+		}
+	}
+	return c
+}
+
+func spanCost(layers []Cost, l, offset int) Cost {
+	from, to := Span(l, offset)
+	var c Cost
+	for i := from; i <= to; i++ {
+		c = c.Add(layers[i])
+	}
+	return c
+}
+
+// Counter accumulates the work of one network as it trains and infers.
+// It is safe for concurrent use.
+type Counter struct {
+	mu       sync.Mutex
+	layers   []Cost
+	forward  Cost // static part of one forward pass
+	training bool
+	train    Cost
+	infer    Cost
+	samples  int // inference passes
+	restore  []func()
+}
+
+// Attach measures net from here on, wrapping the policy of every dynamic
+// layer to count its replays. The network's layout must not change while
+// attached.
+func Attach[T paragon.Numeric](net *paragon.Network[T]) *Counter {
+	c := &Counter{layers: Layers(net), forward: Forward(net)}
+	for l := range net.Layers {
+		layer := &net.Layers[l]
+		policy := layer.ReplayGateToReps
+		if policy == nil {
+			continue
+		}
+		layer.ReplayGateToReps = func(score float64) int {
+			reps := policy(score)
+			n := reps
+			if layer.ReplayBudget > 0 {
+				n = min(n, layer.ReplayBudget)
+			}
+			c.replayed(spanCost(c.layers, l, layer.ReplayOffset).Scale(float64(max(n, 0))))
+			return reps
+		}
+		c.restore = append(c.restore, func() { layer.ReplayGateToReps = policy })
+	}
+	return c
+}
+
+// Detach puts back the layers' own policies.
+func (c *Counter) Detach() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range c.restore {
+		r()
+	}
+	c.restore = nil
+}
+
+// Train charges samples·epochs forward and backward passes; dynamic
+// replays until the next Infer are charged as training.
+func (c *Counter) Train(samples, epochs int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.training = true
+	c.train = c.train.Add(c.forward.Scale(float64(samples*epochs) * (1 + BackwardFactor)))
+}
+
+// Infer charges samples forward passes; dynamic replays until the next
+// Train are charged as inference.
+func (c *Counter) Infer(samples int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.training = false
+	c.samples += samples
+	c.infer = c.infer.Add(c.forward.Scale(float64(samples)))
+}
+
+func (c *Counter) replayed(cost Cost) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.training {
+		c.train = c.train.Add(cost.Scale(1 + BackwardFactor))
+	} else {
+		c.infer = c.infer.Add(cost)
+	}
+}
+
+// Training returns the work charged to training.
+func (c *Counter) Training() Cost {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.train
+}
+
+// Inference returns the work charged to inference.
+func (c *Counter) Inference() Cost {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.infer
+}
+
+// PerSample returns the mean inference work per sample.
+func (c *Counter) PerSample() Cost {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.samples == 0 {
+		return Cost{}
+	}
+	return c.infer.Scale(1 / float64(c.samples))
+}
+
+// Metrics returns the counts for a results record, and score per GMAC of
+// training (score is usually accuracy).
+func (c *Counter) Metrics(score float64) map[string]float64 {
+	train, per := c.Training(), c.PerSample()
+	return map[string]float64{
+		"train_macs":      train.MACs,
+		"infer_macs":      per.MACs,
+		"infer_neurons":   per.Neurons,
+		"score_per_gmacs": PerCompute(score, train),
+	}
+}
+
+// PerCompute is score per 10⁹ MACs of cost; 0 for free work.
+func PerCompute(score float64, cost Cost) float64 {
+	if cost.MACs == 0 {
+		return 0
+	}
+	return score / (cost.MACs / 1e9)
+}
+
+// Format prints a MAC count as 1.23K, 4.56M, 7.89G or 1.23T.
+func Format(macs float64) string {
+	for _, u := range []struct {
+		scale float64
+		unit  string
+	}{{1e12, "T"}, {1e9, "G"}, {1e6, "M"}, {1e3, "K"}} {
+		if macs >= u.scale {
+			return fmt.Sprintf("%.2f%s", macs/u.scale, u.unit)
+		}
+	}
+	return fmt.Sprintf("%.0f", macs)
+}
@@ -0,0 +1,30 @@
+package compute
+
+// Dense returns the cost of one forward pass through fully connected
+// layers of the given sizes, as paragon.NewNetwork takes them, without
+// building the network.
+func Dense(layers []struct{ Width, Height int }) Cost {
+	var c Cost
+	for l := 1; l < len(layers); l++ {
+		in := float64(layers[l-1].Width * layers[l-1].Height)
+		n := float64(layers[l].Width * layers[l].Height)
+		c = c.Add(Cost{Neurons: n, MACs: n * in})
+	}
+	return c
+}
+
+// Widen returns a copy of a fully connected layout with layer l grown,
+// a column or a row at a time to stay near square, until a plain forward
+// pass costs at least macs. It is the equal-compute baseline for a replay
+// network: as wide as the replays are expensive.
+func Widen(layers []struct{ Width, Height int }, l int, macs float64) []struct{ Width, Height int } {
+	out := append([]struct{ Width, Height int }(nil), layers...)
+	for Dense(out).MACs < macs {
+		if out[l].Width <= out[l].Height {
+			out[l].Width++
+		} else {
+			out[l].Height++
+		}
+	}
+	return out
+}
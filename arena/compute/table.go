@@ -0,0 +1,37 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"arena/results"
+)
+
+// WriteTable renders score against work for every summary that recorded
+// Metrics: training MACs, inference MACs per sample, cost relative to the
+// first row, and metric per GMAC of training.
+func WriteTable(w io.Writer, title, metric string, sums []results.Summary) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintf(tw, " Model\t N\t %s\t Train MACs\t MACs/sample\t Cost ×\t %s/GMAC\t\n", metric, metric)
+	base := 0.0
+	for _, s := range sums {
+		train, ok := s.Metrics["train_macs"]
+		if !ok {
+			continue
+		}
+		if base == 0 {
+			base = train.Mean
+		}
+		score := s.Metrics[metric].Mean
+		rel := "-"
+		if base > 0 {
+			rel = fmt.Sprintf("%.2f", train.Mean/base)
+		}
+		fmt.Fprintf(tw, " %s\t %d\t %.2f\t %s\t %s\t %s\t %.3f\t\n", s.Model, s.N, score,
+			Format(train.Mean), Format(s.Metrics["infer_macs"].Mean), rel,
+			PerCompute(score, Cost{MACs: train.Mean}))
+	}
+	return tw.Flush()
+}
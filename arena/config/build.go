@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+
+	"arena/gates"
+	"paragon"
+)
+
+// Build creates the network described by c and applies its replay
+// settings.
+func Build[T paragon.Numeric](c *Config) (*paragon.Network[T], error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	sizes, acts, fc := c.Shape()
+	net := paragon.NewNetwork[T](sizes, acts, fc, c.Seed)
+	for idx, r := range c.replays() {
+		if net.Layers[idx].ReplayPhase == "" {
+			net.Layers[idx].ReplayPhase = "after"
+		}
+		if err := applyReplay(&net.Layers[idx], r); err != nil {
+			return nil, fmt.Errorf("layer %d: %w", idx, err)
+		}
+	}
+	return net, nil
+}
+
+// Train runs Network.Train with the config's training settings.
+func Train[T paragon.Numeric](net *paragon.Network[T], t Training, inputs, targets [][][]float64) {
+	net.Train(inputs, targets, t.Epochs, t.LearningRate, t.EarlyStop, T(t.ClipUpper), T(t.ClipLower))
+}
+
+// applyReplay sets r on layer. An empty Phase and a zero Offset leave the
+// layer's own, so a manifest applied to a loaded model keeps what the model
+// was saved with.
+func applyReplay[T paragon.Numeric](layer *paragon.Grid[T], r *Replay) error {
+	if r.Phase != "" {
+		layer.ReplayPhase = r.Phase
+	}
+	if r.Offset != 0 {
+		layer.ReplayOffset = r.Offset
+	}
+
+	switch r.Mode {
+	case ReplayStatic:
+		layer.MaxReplay = r.Max
+	case ReplayDynamic:
+		policy, err := r.policy()
+		if err != nil {
+			return err
+		}
+		return gates.Apply(layer, r.Gate, policy, r.Budget)
+	}
+	return nil
+}
+
+// policy builds the dynamic replay policy r names.
+func (r *Replay) policy() (func(float64) int, error) {
+	name := r.Policy
+	if name == "" {
+		name = "threshold"
+		if r.Scaled {
+			name = "scaled"
+		}
+	}
+	return gates.NewPolicy(name, gates.PolicyParams{
+		Threshold: r.Threshold, Budget: r.Budget, Exp: r.Exp, Steps: r.Steps, Floor: r.Floor,
+	})
+}
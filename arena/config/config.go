@@ -0,0 +1,238 @@
+// Package config describes a network and its training run as data. A YAML
+// or JSON file lists the layers, their activations and connectivity, replay
+// settings per layer and the training hyperparameters; Build turns it into
+// a paragon.Network[T] for any numeric T.
+//
+//	name: replay-small
+//	seed: 42
+//	layers:
+//	  - {width: 28, height: 28}
+//	  - width: 3
+//	    height: 3
+//	    replay: {mode: static, phase: after, offset: -1, max: 3}
+//	  - {width: 10, height: 1, activation: softmax}
+//	training: {epochs: 3, learning_rate: 0.001, early_stop: true, clip_upper: 5, clip_lower: -5}
+//	sweep:
+//	  layers.1.replay.max: [1, 2, 3]
+//
+// The optional sweep maps a dotted path into the config to the values it
+// should take; Expand returns one config per combination.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"arena/gates"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultActivation is used for layers that do not name one.
+const DefaultActivation = "leaky_relu"
+
+// Config is one network plus the settings to train it.
+type Config struct {
+	Name     string           `json:"name,omitempty" yaml:"name,omitempty"`
+	Seed     int64            `json:"seed,omitempty" yaml:"seed,omitempty"`
+	Layers   []Layer          `json:"layers" yaml:"layers"`
+	Training Training         `json:"training" yaml:"training"`
+	Sweep    map[string][]any `json:"sweep,omitempty" yaml:"sweep,omitempty"`
+}
+
+// Layer is one grid of neurons.
+type Layer struct {
+	Width          int     `json:"width" yaml:"width"`
+	Height         int     `json:"height" yaml:"height"`
+	Activation     string  `json:"activation,omitempty" yaml:"activation,omitempty"` // default DefaultActivation
+	FullyConnected *bool   `json:"fully_connected,omitempty" yaml:"fully_connected,omitempty"`
+	Repeat         int     `json:"repeat,omitempty" yaml:"repeat,omitempty"` // identical copies, default 1
+	Replay         *Replay `json:"replay,omitempty" yaml:"replay,omitempty"`
+}
+
+// Replay modes.
+const (
+	ReplayStatic  = "static"  // replay MaxReplay times on every pass
+	ReplayDynamic = "dynamic" // a gate decides how many replays, up to Budget
+)
+
+// Replay configures paragon's layer replay.
+type Replay struct {
+	Mode      string  `json:"mode" yaml:"mode"`
+	Phase     string  `json:"phase,omitempty" yaml:"phase,omitempty"`   // before or after, default after
+	Offset    int     `json:"offset,omitempty" yaml:"offset,omitempty"` // layer to replay, relative
+	Max       int     `json:"max,omitempty" yaml:"max,omitempty"`       // static: replays per pass
+	Budget    int     `json:"budget,omitempty" yaml:"budget,omitempty"` // dynamic: most replays per pass
+	Gate      string  `json:"gate,omitempty" yaml:"gate,omitempty"`     // dynamic: gate name, see arena/gates
+	Threshold float64 `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	Scaled    bool    `json:"scaled,omitempty" yaml:"scaled,omitempty"` // replays grow with the gate score
+	// Policy names the dynamic score-to-replays policy, see
+	// gates.PolicyNames; default threshold, or scaled when Scaled is set.
+	// Exp, Steps and Floor are the power and steps policies' settings.
+	Policy string       `json:"policy,omitempty" yaml:"policy,omitempty"`
+	Exp    float64      `json:"exp,omitempty" yaml:"exp,omitempty"`
+	Steps  []gates.Step `json:"steps,omitempty" yaml:"steps,omitempty"`
+	Floor  int          `json:"floor,omitempty" yaml:"floor,omitempty"`
+}
+
+// Training holds the arguments to Network.Train.
+type Training struct {
+	Epochs       int     `json:"epochs" yaml:"epochs"`
+	LearningRate float64 `json:"learning_rate" yaml:"learning_rate"`
+	EarlyStop    bool    `json:"early_stop,omitempty" yaml:"early_stop,omitempty"`
+	ClipUpper    float64 `json:"clip_upper,omitempty" yaml:"clip_upper,omitempty"`
+	ClipLower    float64 `json:"clip_lower,omitempty" yaml:"clip_lower,omitempty"`
+}
+
+// Load reads a config from a .yaml, .yml or .json file.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	case ".json":
+		format = "json"
+	default:
+		return nil, fmt.Errorf("%s: unknown config extension (want .yaml, .yml or .json)", path)
+	}
+	cfg, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse decodes and validates a config. format is "yaml" or "json";
+// unknown fields are rejected so typos do not silently fall back to
+// defaults.
+func Parse(data []byte, format string) (*Config, error) {
+	cfg := &Config{}
+	switch format {
+	case "yaml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil {
+			return nil, err
+		}
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(cfg); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown config format %q", format)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks shapes, replay settings and training values.
+func (c *Config) Validate() error {
+	if len(c.Layers) < 2 {
+		return errors.New("need at least an input and an output layer")
+	}
+	for i, l := range c.Layers {
+		if l.Width <= 0 || l.Height <= 0 {
+			return fmt.Errorf("layers[%d]: size %dx%d", i, l.Width, l.Height)
+		}
+		if l.Repeat < 0 || (l.Repeat > 1 && (i == 0 || i == len(c.Layers)-1)) {
+			return fmt.Errorf("layers[%d]: repeat %d (only hidden layers repeat)", i, l.Repeat)
+		}
+		if l.Replay != nil {
+			if i == 0 {
+				return fmt.Errorf("layers[%d]: the input layer cannot replay", i)
+			}
+			if err := l.Replay.validate(); err != nil {
+				return fmt.Errorf("layers[%d].replay: %w", i, err)
+			}
+		}
+	}
+	if c.Training.Epochs < 0 || c.Training.LearningRate < 0 {
+		return fmt.Errorf("training: epochs %d, learning_rate %g", c.Training.Epochs, c.Training.LearningRate)
+	}
+	if c.Training.ClipUpper < c.Training.ClipLower {
+		return fmt.Errorf("training: clip_upper %g below clip_lower %g", c.Training.ClipUpper, c.Training.ClipLower)
+	}
+	return nil
+}
+
+func (r *Replay) validate() error {
+	switch r.Phase {
+	case "", "before", "after":
+	default:
+		return fmt.Errorf("phase %q (want before or after)", r.Phase)
+	}
+	switch r.Mode {
+	case ReplayStatic:
+		if r.Max < 0 {
+			return fmt.Errorf("max %d", r.Max)
+		}
+	case ReplayDynamic:
+		if r.Budget <= 0 {
+			return fmt.Errorf("budget %d (dynamic replay needs a positive budget)", r.Budget)
+		}
+		if !gates.Known(r.Gate) {
+			return fmt.Errorf("unknown gate %q (have %v)", r.Gate, gates.Names())
+		}
+		if r.Scaled && r.Policy != "" && r.Policy != "scaled" {
+			return fmt.Errorf("scaled with policy %q", r.Policy)
+		}
+		if _, err := r.policy(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("mode %q (want %s or %s)", r.Mode, ReplayStatic, ReplayDynamic)
+	}
+	return nil
+}
+
+// Shape expands Repeat and defaults into the three slices NewNetwork takes.
+func (c *Config) Shape() (sizes []struct{ Width, Height int }, acts []string, fc []bool) {
+	for _, l := range c.Layers {
+		n := l.Repeat
+		if n == 0 {
+			n = 1
+		}
+		for j := 0; j < n; j++ {
+			sizes = append(sizes, struct{ Width, Height int }{l.Width, l.Height})
+			act := l.Activation
+			if act == "" {
+				act = DefaultActivation
+			}
+			acts = append(acts, act)
+			fc = append(fc, l.FullyConnected == nil || *l.FullyConnected)
+		}
+	}
+	return sizes, acts, fc
+}
+
+// replays returns the replay settings per built layer index.
+func (c *Config) replays() map[int]*Replay {
+	out := map[int]*Replay{}
+	idx := 0
+	for _, l := range c.Layers {
+		n := l.Repeat
+		if n == 0 {
+			n = 1
+		}
+		for j := 0; j < n; j++ {
+			if l.Replay != nil {
+				out[idx] = l.Replay
+			}
+			idx++
+		}
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"paragon"
+)
+
+// Manifest is the replay sidecar saved next to a model. paragon's JSON
+// keeps the weights but not ReplayGateFunc and ReplayGateToReps, which are
+// closures; the manifest keeps each replaying layer's settings by name so
+// LoadModel can rebuild them.
+//
+//	m := cfg.Manifest()            // or built by hand
+//	config.SaveModel(net, "best_dynamic_model.json", m)
+//	...
+//	net, m, err := config.LoadModel[float32]("best_dynamic_model.json")
+type Manifest struct {
+	Layers []LayerReplay `json:"layers"`
+}
+
+// LayerReplay is the replay setting of one built layer.
+type LayerReplay struct {
+	Layer  int    `json:"layer"`
+	Replay Replay `json:"replay"`
+}
+
+// Manifest returns the replay settings of the network c builds.
+func (c *Config) Manifest() Manifest {
+	var m Manifest
+	for idx, r := range c.replays() {
+		m.Layers = append(m.Layers, LayerReplay{Layer: idx, Replay: *r})
+	}
+	sort.Slice(m.Layers, func(i, j int) bool { return m.Layers[i].Layer < m.Layers[j].Layer })
+	return m
+}
+
+// ManifestPath is where SaveModel puts the manifest of the model at path:
+// model.json gets model.replay.json.
+func ManifestPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".replay.json"
+}
+
+// Apply sets the replay settings of m on net. Settings a layer's entry
+// leaves unset (Phase, Offset) keep the network's.
+func Apply[T paragon.Numeric](net *paragon.Network[T], m Manifest) error {
+	for _, l := range m.Layers {
+		if l.Layer <= 0 || l.Layer >= len(net.Layers) {
+			return fmt.Errorf("manifest: layer %d of a %d-layer network", l.Layer, len(net.Layers))
+		}
+		r := l.Replay
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("manifest: layer %d: %w", l.Layer, err)
+		}
+		if err := applyReplay(&net.Layers[l.Layer], &r); err != nil {
+			return fmt.Errorf("manifest: layer %d: %w", l.Layer, err)
+		}
+	}
+	return nil
+}
+
+// SaveModel writes net with SaveJSON and m to ManifestPath(path).
+func SaveModel[T paragon.Numeric](net *paragon.Network[T], path string, m Manifest) error {
+	if err := net.SaveJSON(path); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ManifestPath(path), append(data, '\n'), 0644)
+}
+
+// LoadModel reads a model with LoadJSON and restores its replay gates and
+// policies from the manifest next to it. A model without a manifest, such
+// as one saved before manifests existed, has no replay config: it loads as
+// it is and comes back with an empty Manifest, for the caller to set up.
+func LoadModel[T paragon.Numeric](path string) (*paragon.Network[T], Manifest, error) {
+	net := &paragon.Network[T]{}
+	if err := net.LoadJSON(path); err != nil {
+		return nil, Manifest{}, err
+	}
+	m, err := ReadManifest(ManifestPath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return net, Manifest{}, nil
+	}
+	if err != nil {
+		return nil, Manifest{}, err
+	}
+	if err := Apply(net, m); err != nil {
+		return nil, Manifest{}, fmt.Errorf("%s: %w", ManifestPath(path), err)
+	}
+	for i, l := range net.Layers {
+		if l.ReplayEnabled && l.ReplayBudget > 0 && l.ReplayGateFunc == nil {
+			return nil, Manifest{}, fmt.Errorf("%s: layer %d replays dynamically but has no gate in %s",
+				path, i, ManifestPath(path))
+		}
+	}
+	return net, m, nil
+}
+
+// ReadManifest reads and validates a manifest file.
+func ReadManifest(path string) (Manifest, error) {
+	var m Manifest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return Manifest{}, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, l := range m.Layers {
+		if err := l.Replay.validate(); err != nil {
+			return Manifest{}, fmt.Errorf("%s: layer %d: %w", path, l.Layer, err)
+		}
+	}
+	return m, nil
+}
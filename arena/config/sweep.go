@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Variant is one point of a sweep.
+type Variant struct {
+	Params map[string]any // sweep path -> value used
+	Config *Config        // full config with the values applied, Sweep cleared
+}
+
+// Label is a short "path=value,..." description of the variant.
+func (v Variant) Label() string {
+	keys := make([]string, 0, len(v.Params))
+	for k := range v.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, v.Params[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+// Expand returns every combination of the sweep values, in a stable order
+// (paths sorted, values in file order). A config without a sweep expands to
+// itself.
+func (c *Config) Expand() ([]Variant, error) {
+	paths := make([]string, 0, len(c.Sweep))
+	for p := range c.Sweep {
+		if len(c.Sweep[p]) == 0 {
+			return nil, fmt.Errorf("sweep %s: no values", p)
+		}
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	base := *c
+	base.Sweep = nil
+	raw, err := json.Marshal(&base)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Variant
+	idx := make([]int, len(paths))
+	for {
+		var tree any
+		if err := json.Unmarshal(raw, &tree); err != nil {
+			return nil, err
+		}
+		params := map[string]any{}
+		for i, p := range paths {
+			val := c.Sweep[p][idx[i]]
+			if err := set(tree, strings.Split(p, "."), val); err != nil {
+				return nil, fmt.Errorf("sweep %s: %w", p, err)
+			}
+			params[p] = val
+		}
+		data, err := json.Marshal(tree)
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := Parse(data, "json")
+		if err != nil {
+			return nil, fmt.Errorf("sweep %v: %w", params, err)
+		}
+		out = append(out, Variant{Params: params, Config: cfg})
+
+		// Odometer increment, last path fastest.
+		i := len(idx) - 1
+		for ; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < len(c.Sweep[paths[i]]) {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			return out, nil
+		}
+	}
+}
+
+// set assigns val at path inside a decoded JSON tree. Missing objects along
+// the way are created, so a sweep can switch replay on for a layer that has
+// none.
+func set(node any, path []string, val any) error {
+	key := path[0]
+	last := len(path) == 1
+	switch n := node.(type) {
+	case map[string]any:
+		if last {
+			n[key] = val
+			return nil
+		}
+		child, ok := n[key]
+		if !ok || child == nil {
+			child = map[string]any{}
+			n[key] = child
+		}
+		return set(child, path[1:], val)
+	case []any:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(n) {
+			return fmt.Errorf("index %q out of range (len %d)", key, len(n))
+		}
+		if last {
+			n[i] = val
+			return nil
+		}
+		return set(n[i], path[1:], val)
+	default:
+		return fmt.Errorf("cannot descend into %q", key)
+	}
+}
@@ -0,0 +1,312 @@
+// Package cache keeps downloaded datasets in one local directory, verifies
+// them by SHA-256 and can refuse to touch the network at all.
+//
+// Two environment variables control the default cache:
+//
+//	ARENA_CACHE    directory to use (default: <user cache dir>/neuralarena)
+//	ARENA_OFFLINE  when set to a true value, a missing file is an error
+//	               instead of a download
+//
+// Digests are checked against Source.SHA256 when it is pinned, otherwise
+// against the SHA256SUMS file in the cache directory, which records the
+// digest of every file the first time it is stored. A cache holding
+// fixtures (see MarkFixtures) ignores the pins.
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SumsFile is the digest manifest kept at the root of the cache directory.
+const SumsFile = "SHA256SUMS"
+
+// FixturesFile marks a cache directory whose files are generated stand-ins
+// rather than the upstream datasets.
+const FixturesFile = "FIXTURES"
+
+var (
+	// ErrMissing is returned by Fetch in offline mode when a file is absent.
+	ErrMissing = errors.New("dataset not in cache")
+	// ErrChecksum is returned when a file does not match its digest.
+	ErrChecksum = errors.New("dataset checksum mismatch")
+	// ErrRejected is returned when Source.Check refuses a file.
+	ErrRejected = errors.New("dataset rejected")
+)
+
+// Source describes one remote file.
+type Source struct {
+	Name   string // path inside the cache directory
+	URL    string
+	SHA256 string // optional pinned digest, hex encoded
+
+	// Check, when set, inspects a file's contents before it is stored and
+	// again when it is served, for endpoints that answer errors with a 200.
+	Check func(data []byte) error
+}
+
+// Cache is a directory of verified dataset files.
+type Cache struct {
+	Dir     string
+	Offline bool
+
+	mu sync.Mutex
+}
+
+// New returns a cache rooted at dir.
+func New(dir string, offline bool) *Cache {
+	return &Cache{Dir: dir, Offline: offline}
+}
+
+var (
+	defaultOnce  sync.Once
+	defaultCache *Cache
+)
+
+// Default returns the process-wide cache configured from ARENA_CACHE and
+// ARENA_OFFLINE.
+func Default() *Cache {
+	defaultOnce.Do(func() {
+		dir := os.Getenv("ARENA_CACHE")
+		if dir == "" {
+			base, err := os.UserCacheDir()
+			if err != nil {
+				base = os.TempDir()
+			}
+			dir = filepath.Join(base, "neuralarena")
+		}
+		defaultCache = New(dir, offlineFromEnv())
+	})
+	return defaultCache
+}
+
+func offlineFromEnv() bool {
+	v := strings.TrimSpace(os.Getenv("ARENA_OFFLINE"))
+	if v == "" {
+		return false
+	}
+	on, err := strconv.ParseBool(v)
+	return err != nil || on
+}
+
+// Path returns where src lives inside the cache, whether or not it exists.
+func (c *Cache) Path(src Source) string {
+	return filepath.Join(c.Dir, filepath.FromSlash(src.Name))
+}
+
+// MarkFixtures records that c holds fixtures, so pinned digests, which
+// only the upstream files can match, are not checked.
+func (c *Cache) MarkFixtures() error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.Dir, FixturesFile), []byte("generated by arena/datasets/fixtures\n"), 0644)
+}
+
+// pinned is the digest src must have in c, "" when it has none.
+func (c *Cache) pinned(src Source) string {
+	if src.SHA256 == "" {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(c.Dir, FixturesFile)); err == nil {
+		return ""
+	}
+	return src.SHA256
+}
+
+// Fetch returns the local path of src, downloading it first unless the
+// cache is offline. The file is verified on every call; a cached file that
+// Source.Check refuses is dropped and downloaded again.
+func (c *Cache) Fetch(src Source) (string, error) {
+	path := c.Path(src)
+	if _, err := os.Stat(path); err == nil {
+		err := c.check(src, path)
+		if err == nil {
+			return path, c.verify(src, path)
+		}
+		if c.Offline {
+			return "", err
+		}
+		fmt.Printf("⚠️ %v; downloading it again\n", err)
+		if err := os.Remove(path); err != nil {
+			return "", err
+		}
+	}
+	if c.Offline {
+		return "", fmt.Errorf("%w: %s should be at %s (ARENA_OFFLINE is set; populate the cache or unset it)",
+			ErrMissing, src.Name, path)
+	}
+	if src.URL == "" {
+		return "", fmt.Errorf("%w: %s has no download URL", ErrMissing, src.Name)
+	}
+
+	fmt.Printf("Downloading %s...\n", src.URL)
+	resp, err := http.Get(src.URL)
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", src.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download %s: %s", src.Name, resp.Status)
+	}
+	return c.Put(src, resp.Body)
+}
+
+// Put stores r as src, checking it against a pinned digest if there is one
+// and against Source.Check, and recording its digest in SumsFile.
+func (c *Cache) Put(src Source, r io.Reader) (string, error) {
+	path := c.Path(src)
+	if src.Check != nil {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return "", fmt.Errorf("read %s: %w", src.Name, err)
+		}
+		if err := src.Check(data); err != nil {
+			return "", fmt.Errorf("%w: %s: %v", ErrRejected, src.Name, err)
+		}
+		r = bytes.NewReader(data)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	tmp := path + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return "", fmt.Errorf("write %s: %w", src.Name, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	got := hex.EncodeToString(h.Sum(nil))
+	if want := c.pinned(src); want != "" && !strings.EqualFold(got, want) {
+		os.Remove(tmp)
+		return "", fmt.Errorf("%w: %s is %s, want %s", ErrChecksum, src.Name, got, want)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", err
+	}
+	return path, c.record(src.Name, got)
+}
+
+// verify checks path against the pinned or recorded digest, recording one
+// if the file was placed in the cache by hand.
+func (c *Cache) verify(src Source, path string) error {
+	got, err := fileDigest(path)
+	if err != nil {
+		return err
+	}
+	want := c.pinned(src)
+	if want == "" {
+		sums, err := c.sums()
+		if err != nil {
+			return err
+		}
+		want = sums[src.Name]
+	}
+	if want == "" {
+		return c.record(src.Name, got)
+	}
+	if !strings.EqualFold(got, want) {
+		return fmt.Errorf("%w: %s is %s, want %s", ErrChecksum, path, got, want)
+	}
+	return nil
+}
+
+// check runs Source.Check on the cached file at path.
+func (c *Cache) check(src Source, path string) error {
+	if src.Check == nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := src.Check(data); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrRejected, path, err)
+	}
+	return nil
+}
+
+func (c *Cache) sums() (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.readSums()
+}
+
+func (c *Cache) readSums() (map[string]string, error) {
+	sums := map[string]string{}
+	f, err := os.Open(filepath.Join(c.Dir, SumsFile))
+	if os.IsNotExist(err) {
+		return sums, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		digest, name, ok := strings.Cut(sc.Text(), "  ")
+		if ok {
+			sums[name] = digest
+		}
+	}
+	return sums, sc.Err()
+}
+
+func (c *Cache) record(name, digest string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sums, err := c.readSums()
+	if err != nil {
+		return err
+	}
+	if sums[name] == digest {
+		return nil
+	}
+	sums[name] = digest
+	names := make([]string, 0, len(sums))
+	for n := range sums {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, n := range names {
+		fmt.Fprintf(&b, "%s  %s\n", sums[n], n)
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.Dir, SumsFile), []byte(b.String()), 0644)
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPutFetch(t *testing.T) {
+	c := New(t.TempDir(), true)
+	src := Source{Name: "a/b.txt"}
+	if _, err := c.Fetch(src); !errors.Is(err, ErrMissing) {
+		t.Fatalf("offline fetch of a missing file: %v, want ErrMissing", err)
+	}
+
+	path, err := c.Put(src, strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.Fetch(src); err != nil || got != path {
+		t.Fatalf("fetch = %q, %v, want %q", got, err, path)
+	}
+	sums, err := c.sums()
+	if err != nil || sums[src.Name] == "" {
+		t.Fatalf("%s not recorded in %s: %v", src.Name, SumsFile, err)
+	}
+
+	if err := os.WriteFile(path, []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Fetch(src); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("fetch of a changed file: %v, want ErrChecksum", err)
+	}
+}
+
+func TestPinned(t *testing.T) {
+	c := New(t.TempDir(), true)
+	src := Source{Name: "pinned.bin", SHA256: strings.Repeat("0", 64)}
+	if _, err := c.Put(src, strings.NewReader("not the upstream file")); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("put against a pin: %v, want ErrChecksum", err)
+	}
+	if _, err := os.Stat(c.Path(src)); !os.IsNotExist(err) {
+		t.Fatalf("a refused file was left in the cache: %v", err)
+	}
+
+	if err := c.MarkFixtures(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Put(src, strings.NewReader("a fixture")); err != nil {
+		t.Fatalf("put into a fixture cache: %v", err)
+	}
+	if _, err := c.Fetch(src); err != nil {
+		t.Fatalf("fetch from a fixture cache: %v", err)
+	}
+}
+
+func TestAlphaVantageCheck(t *testing.T) {
+	src := AlphaVantageDaily("IBM", "demo")
+	for _, body := range []string{
+		`{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute."}`,
+		`{"Error Message": "Invalid API call."}`,
+		`{"Information": "The demo API key is for demo purposes only."}`,
+		"",
+	} {
+		c := New(t.TempDir(), false)
+		if _, err := c.Put(src, strings.NewReader(body)); !errors.Is(err, ErrRejected) {
+			t.Errorf("put %q: %v, want ErrRejected", body, err)
+		}
+		if _, err := os.Stat(c.Path(src)); !os.IsNotExist(err) {
+			t.Errorf("put %q left the body in the cache", body)
+		}
+	}
+
+	c := New(t.TempDir(), true)
+	csv := "timestamp,open,high,low,close,volume\n2024-01-02,1,2,0.5,1.5,100\n"
+	if _, err := c.Put(src, strings.NewReader(csv)); err != nil {
+		t.Fatalf("put a CSV: %v", err)
+	}
+	if err := os.WriteFile(c.Path(src), []byte(`{"Note": "rate limited"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Fetch(src); !errors.Is(err, ErrRejected) {
+		t.Fatalf("fetch of a cached note: %v, want ErrRejected", err)
+	}
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+const mnistBase = "https://storage.googleapis.com/cvdf-datasets/mnist/"
+
+// Datasets the experiments download. The MNIST archives never change and
+// are pinned to the digests TensorFlow Datasets publishes for the same
+// URLs. The rest are living files (VIX gains a row a day, Gutenberg and
+// UCI re-encode now and then), so they are verified against SHA256SUMS
+// after the first fetch.
+var (
+	MNISTTrainImages = Source{Name: "mnist/train-images-idx3-ubyte.gz", URL: mnistBase + "train-images-idx3-ubyte.gz",
+		SHA256: "440fcabf73cc546fa21475e81ea370265605f56be210a4024d2ca8f203523609"}
+	MNISTTrainLabels = Source{Name: "mnist/train-labels-idx1-ubyte.gz", URL: mnistBase + "train-labels-idx1-ubyte.gz",
+		SHA256: "3552534a0a558bbed6aed32b30c495cca23d567ec52cac8be1a0730e8010255c"}
+	MNISTTestImages = Source{Name: "mnist/t10k-images-idx3-ubyte.gz", URL: mnistBase + "t10k-images-idx3-ubyte.gz",
+		SHA256: "8d422c7b0a1c1c79245a5bcf07fe86e33eeafee792b84584aec276f5a2dbc4e6"}
+	MNISTTestLabels = Source{Name: "mnist/t10k-labels-idx1-ubyte.gz", URL: mnistBase + "t10k-labels-idx1-ubyte.gz",
+		SHA256: "f7ae60f92e00ec6debd23a6088c31dbd2371eca3ffa0defaefb259924204aec6"}
+
+	// VIXDaily is the CBOE volatility index history used by time2-time4.
+	VIXDaily = Source{Name: "finance/vix-daily.csv", URL: "https://raw.githubusercontent.com/datasets/finance-vix/main/data/vix-daily.csv"}
+
+	// BankMarketing is the UCI bank marketing archive used by fin1.
+	BankMarketing = Source{Name: "uci/bank.zip", URL: "https://archive.ics.uci.edu/ml/machine-learning-databases/00222/bank.zip"}
+
+	// EEGEyeState is the UCI EEG eye state recording used by replayEyeState.
+	EEGEyeState = Source{Name: "uci/eeg-eye-state.arff", URL: "https://archive.ics.uci.edu/ml/machine-learning-databases/00264/EEG%20Eye%20State.arff"}
+
+	// Project Gutenberg texts used by the language experiments.
+	GutenbergAlice    = Source{Name: "gutenberg/11-0.txt", URL: "https://www.gutenberg.org/files/11/11-0.txt"}
+	GutenbergSherlock = Source{Name: "gutenberg/pg1661.txt", URL: "https://www.gutenberg.org/cache/epub/1661/pg1661.txt"}
+	GutenbergBook28   = Source{Name: "gutenberg/28-0.txt", URL: "https://www.gutenberg.org/files/28/28-0.txt"}
+)
+
+// MNIST lists the four MNIST archives in train, test order.
+func MNIST() []Source {
+	return []Source{MNISTTrainImages, MNISTTrainLabels, MNISTTestImages, MNISTTestLabels}
+}
+
+// Known lists every fixed source, for tools that prefetch or stub them all.
+func Known() []Source {
+	return append(MNIST(), VIXDaily, BankMarketing, EEGEyeState, GutenbergAlice, GutenbergSherlock, GutenbergBook28)
+}
+
+// AlphaVantageDaily is the daily OHLC CSV for one ticker. The key only
+// shapes the URL; cached copies are shared across keys.
+func AlphaVantageDaily(symbol, apiKey string) Source {
+	return Source{
+		Name: fmt.Sprintf("alphavantage/%s-daily.csv", symbol),
+		URL: fmt.Sprintf("https://www.alphavantage.co/query?function=TIME_SERIES_DAILY&symbol=%s&apikey=%s&datatype=csv",
+			symbol, apiKey),
+		Check: alphaVantageCSV,
+	}
+}
+
+// alphaVantageCSV refuses the JSON notes Alpha Vantage sends with a 200
+// instead of the CSV when the key is rate limited or the query is bad.
+func alphaVantageCSV(data []byte) error {
+	head := data[:min(len(data), 512)]
+	for _, key := range []string{`"Note"`, `"Error Message"`, `"Information"`} {
+		if bytes.Contains(head, []byte(key)) {
+			return fmt.Errorf("alpha vantage answered %s", bytes.TrimSpace(head))
+		}
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return errors.New("alpha vantage answered nothing")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package fixtures
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+var (
+	bankJobs    = []string{"admin.", "blue-collar", "technician", "services", "management", "retired", "student"}
+	bankMarital = []string{"married", "single", "divorced"}
+	bankEdu     = []string{"primary", "secondary", "tertiary", "unknown"}
+	bankMonths  = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	bankYesNo   = []string{"no", "yes"}
+)
+
+// BankCSV writes rows in the semicolon-separated, quoted layout of the UCI
+// bank.csv. Longer calls make a "yes" more likely, as in the real data.
+func BankCSV(w io.Writer, rows int, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	pick := func(xs []string) string { return xs[rng.Intn(len(xs))] }
+
+	header := `"age";"job";"marital";"education";"default";"balance";"housing";"loan";"contact";"day";"month";"duration";"campaign";"pdays";"previous";"poutcome";"y"`
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		return err
+	}
+	for i := 0; i < rows; i++ {
+		age := 18 + rng.Intn(70)
+		balance := int(rng.NormFloat64()*2000) + 1000
+		duration := 10 + rng.Intn(900)
+		y := "no"
+		if float64(duration)/900+rng.NormFloat64()*0.15 > 0.6 {
+			y = "yes"
+		}
+		if _, err := fmt.Fprintf(w, "%d;%q;%q;%q;%q;%d;%q;%q;%q;%d;%q;%d;%d;%d;%d;%q;%q\n",
+			age, pick(bankJobs), pick(bankMarital), pick(bankEdu), pick(bankYesNo), balance,
+			pick(bankYesNo), pick(bankYesNo), "cellular", 1+rng.Intn(28), pick(bankMonths),
+			duration, 1+rng.Intn(5), -1, 0, "unknown", y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BankZip wraps BankCSV in a zip archive holding bank.csv, like bank.zip.
+func BankZip(w io.Writer, rows int, seed int64) error {
+	zw := zip.NewWriter(w)
+	f, err := zw.Create("bank.csv")
+	if err != nil {
+		return err
+	}
+	if err := BankCSV(f, rows, seed); err != nil {
+		return err
+	}
+	return zw.Close()
+}
@@ -0,0 +1,54 @@
+package fixtures
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+)
+
+var (
+	corpusAdj   = []string{"little", "curious", "old", "quiet", "bright", "strange", "tired", "clever"}
+	corpusNoun  = []string{"rabbit", "queen", "door", "garden", "detective", "letter", "clock", "river", "house", "cat"}
+	corpusVerb  = []string{"watched", "followed", "opened", "found", "remembered", "carried", "crossed", "answered"}
+	corpusAdv   = []string{"slowly", "at once", "again", "without a word", "in the morning", "before dark"}
+	corpusStart = []string{"Then", "Soon", "At last", "Once more", "Meanwhile"}
+)
+
+// Corpus writes paragraphs of plain English-like prose separated by blank
+// lines and wrapped near 70 columns, like a Project Gutenberg text.
+func Corpus(w io.Writer, paragraphs int, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	pick := func(xs []string) string { return xs[rng.Intn(len(xs))] }
+
+	for p := 0; p < paragraphs; p++ {
+		var words []string
+		for s, n := 0, 3+rng.Intn(4); s < n; s++ {
+			sentence := fmt.Sprintf("%s the %s %s %s the %s %s.",
+				pick(corpusStart), pick(corpusAdj), pick(corpusNoun), pick(corpusVerb), pick(corpusNoun), pick(corpusAdv))
+			words = append(words, strings.Fields(sentence)...)
+		}
+		line := 0
+		for i, word := range words {
+			if line > 0 && line+1+len(word) > 70 {
+				if _, err := io.WriteString(w, "\n"); err != nil {
+					return err
+				}
+				line = 0
+			} else if i > 0 {
+				if _, err := io.WriteString(w, " "); err != nil {
+					return err
+				}
+				line++
+			}
+			if _, err := io.WriteString(w, word); err != nil {
+				return err
+			}
+			line += len(word)
+		}
+		if _, err := io.WriteString(w, "\n\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package fixtures
+
+import "math/rand"
+
+// DigitSize is the side length of generated digit images, as in MNIST.
+const DigitSize = 28
+
+// Seven-segment layout: a top, b top-right, c bottom-right, d bottom,
+// e bottom-left, f top-left, g middle.
+var segments = [10]string{"abcdef", "bc", "abdeg", "abcdg", "bcfg", "acdfg", "acdefg", "abc", "abcdefg", "abcdfg"}
+
+// Digits returns n row-major 28×28 images of seven-segment digits, cycling
+// through the classes, each shifted by up to two pixels and sprinkled with
+// background noise.
+func Digits(n int, seed int64) (pixels [][]byte, labels []int) {
+	rng := rand.New(rand.NewSource(seed))
+	pixels = make([][]byte, n)
+	labels = make([]int, n)
+	for i := range pixels {
+		label := i % 10
+		labels[i] = label
+		img := make([]byte, DigitSize*DigitSize)
+		for p := range img {
+			if rng.Float64() < 0.05 {
+				img[p] = byte(rng.Intn(64))
+			}
+		}
+		dx, dy := rng.Intn(5)-2, rng.Intn(5)-2
+		for _, seg := range segments[label] {
+			drawSegment(img, seg, dx, dy, byte(192+rng.Intn(64)))
+		}
+		pixels[i] = img
+	}
+	return pixels, labels
+}
+
+// drawSegment paints one 2-pixel-thick segment of a glyph spanning
+// columns 9-18 and rows 4-23.
+func drawSegment(img []byte, seg rune, dx, dy int, v byte) {
+	const left, right, top, mid, bottom = 9, 17, 4, 13, 22
+	rect := func(x0, y0, x1, y1 int) {
+		for y := y0; y <= y1+1; y++ {
+			for x := x0; x <= x1+1; x++ {
+				px, py := x+dx, y+dy
+				if px >= 0 && px < DigitSize && py >= 0 && py < DigitSize {
+					img[py*DigitSize+px] = v
+				}
+			}
+		}
+	}
+	switch seg {
+	case 'a':
+		rect(left, top, right, top)
+	case 'b':
+		rect(right, top, right, mid)
+	case 'c':
+		rect(right, mid, right, bottom)
+	case 'd':
+		rect(left, bottom, right, bottom)
+	case 'e':
+		rect(left, mid, left, bottom)
+	case 'f':
+		rect(left, top, left, mid)
+	case 'g':
+		rect(left, mid, right, mid)
+	}
+}
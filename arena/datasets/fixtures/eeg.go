@@ -0,0 +1,48 @@
+package fixtures
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// EEGChannels are the electrode names of the UCI EEG eye state recording.
+var EEGChannels = []string{"AF3", "F7", "F3", "FC5", "T7", "P7", "O1", "O2", "P8", "T8", "FC6", "F4", "F8", "AF4"}
+
+// EEGArff writes rows in the ARFF layout of the UCI recording: 14 channels
+// around 4000-4600 µV and eyeDetection 0 (open) or 1 (closed). Closed eyes
+// raise O1 and O2.
+func EEGArff(w io.Writer, rows int, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	if _, err := fmt.Fprintln(w, "@RELATION 'EEG_DATA'"); err != nil {
+		return err
+	}
+	for _, ch := range EEGChannels {
+		if _, err := fmt.Fprintf(w, "@ATTRIBUTE %s NUMERIC\n", ch); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(w, "@ATTRIBUTE eyeDetection {0,1}\n\n@DATA"); err != nil {
+		return err
+	}
+	for i := 0; i < rows; i++ {
+		closed := rng.Intn(2)
+		for c, ch := range EEGChannels {
+			v := 4300 + rng.NormFloat64()*40
+			if closed == 1 && (ch == "O1" || ch == "O2") {
+				v += 60
+			}
+			sep := ","
+			if c == 0 {
+				sep = ""
+			}
+			if _, err := fmt.Fprintf(w, "%s%.2f", sep, v); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, ",%d\n", closed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
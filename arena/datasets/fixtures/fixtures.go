@@ -0,0 +1,126 @@
+// Package fixtures generates small deterministic stand-ins for every dataset
+// the experiments download: IDX digit files, OHLC price CSVs, the bank
+// marketing archive, the EEG eye state recording and plain-text corpora.
+// Populate writes them into a dataset cache so that experiments run end to
+// end with ARENA_OFFLINE set.
+//
+// The data is synthetic but learnable: digits are jittered seven-segment
+// glyphs, prices follow a random walk, bank subscriptions depend on call
+// duration, closed eyes raise two of the EEG channels. Scores on fixtures
+// say nothing about the real datasets.
+package fixtures
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"math/rand"
+
+	"arena/datasets/cache"
+	"arena/datasets/mnist"
+)
+
+// Sizes of the generated stand-ins.
+const (
+	MNISTTrain      = 200
+	MNISTTest       = 50
+	OHLCDays        = 400
+	BankRows        = 300
+	EEGRows         = 300
+	CorpusParagraph = 150
+)
+
+type file struct {
+	src  cache.Source
+	data []byte
+}
+
+// Populate writes a fixture for every cache.Known source, plus Alpha Vantage
+// CSVs for the given tickers, into c. Existing files are replaced and c is
+// marked as a fixture cache, so the upstream digests are no longer pinned.
+func Populate(c *cache.Cache, seed int64, symbols ...string) error {
+	if err := c.MarkFixtures(); err != nil {
+		return err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	next := func() int64 { return rng.Int63() }
+
+	var files []file
+	add := func(src cache.Source, data []byte) { files = append(files, file{src, data}) }
+
+	trainImg, trainLbl, err := mnistArchives(MNISTTrain, next())
+	if err != nil {
+		return err
+	}
+	testImg, testLbl, err := mnistArchives(MNISTTest, next())
+	if err != nil {
+		return err
+	}
+	add(cache.MNISTTrainImages, trainImg)
+	add(cache.MNISTTrainLabels, trainLbl)
+	add(cache.MNISTTestImages, testImg)
+	add(cache.MNISTTestLabels, testLbl)
+
+	var buf bytes.Buffer
+	if err := OHLC(&buf, VIX, OHLCDays, next()); err != nil {
+		return err
+	}
+	add(cache.VIXDaily, bytes.Clone(buf.Bytes()))
+
+	buf.Reset()
+	if err := BankZip(&buf, BankRows, next()); err != nil {
+		return err
+	}
+	add(cache.BankMarketing, bytes.Clone(buf.Bytes()))
+
+	buf.Reset()
+	if err := EEGArff(&buf, EEGRows, next()); err != nil {
+		return err
+	}
+	add(cache.EEGEyeState, bytes.Clone(buf.Bytes()))
+
+	for _, src := range []cache.Source{cache.GutenbergAlice, cache.GutenbergSherlock, cache.GutenbergBook28} {
+		buf.Reset()
+		if err := Corpus(&buf, CorpusParagraph, next()); err != nil {
+			return err
+		}
+		add(src, bytes.Clone(buf.Bytes()))
+	}
+
+	for _, sym := range symbols {
+		buf.Reset()
+		if err := OHLC(&buf, AlphaVantage, OHLCDays, next()); err != nil {
+			return err
+		}
+		add(cache.AlphaVantageDaily(sym, ""), bytes.Clone(buf.Bytes()))
+	}
+
+	for _, f := range files {
+		if _, err := c.Put(f.src, bytes.NewReader(f.data)); err != nil {
+			return fmt.Errorf("fixture %s: %w", f.src.Name, err)
+		}
+	}
+	return nil
+}
+
+// mnistArchives returns gzipped IDX image and label files with n samples.
+func mnistArchives(n int, seed int64) (images, labels []byte, err error) {
+	pixels, lbls := Digits(n, seed)
+
+	var img, lbl bytes.Buffer
+	zw := gzip.NewWriter(&img)
+	if err := mnist.WriteImages(zw, DigitSize, DigitSize, pixels); err != nil {
+		return nil, nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, err
+	}
+	zw = gzip.NewWriter(&lbl)
+	if err := mnist.WriteLabels(zw, lbls); err != nil {
+		return nil, nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, err
+	}
+	return img.Bytes(), lbl.Bytes(), nil
+}
@@ -0,0 +1,59 @@
+package fixtures
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"arena/datasets/cache"
+	"arena/datasets/mnist"
+	"arena/datasets/uci"
+)
+
+// TestPopulate loads every fixture through the same offline cache and
+// loaders the experiments use.
+func TestPopulate(t *testing.T) {
+	dir := t.TempDir()
+	c := cache.New(filepath.Join(dir, "cache"), true)
+	if err := Populate(c, 1, "AAPL"); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := filepath.Join(dir, "mnist")
+	if err := mnist.EnsureFrom(c, idx); err != nil {
+		t.Fatal(err)
+	}
+	for split, n := range map[mnist.Split]int{mnist.Train: MNISTTrain, mnist.Test: MNISTTest} {
+		x, y, err := mnist.Load(idx, split, mnist.OneHot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(x) != n || len(y) != n || len(x[0]) != DigitSize || len(y[0][0]) != mnist.NumClasses {
+			t.Errorf("mnist %s: %d inputs, %d targets, want %d %dx%d digits", split, len(x), len(y), n, DigitSize, DigitSize)
+		}
+	}
+
+	if x, _, err := uci.EEGEyeState(c); err != nil || len(x) != EEGRows {
+		t.Errorf("eeg: %d rows, %v, want %d", len(x), err, EEGRows)
+	}
+	if x, _, err := uci.Bank(c); err != nil || len(x) != BankRows {
+		t.Errorf("bank: %d rows, %v, want %d", len(x), err, BankRows)
+	}
+
+	for _, src := range []cache.Source{cache.VIXDaily, cache.AlphaVantageDaily("AAPL", "key"),
+		cache.GutenbergAlice, cache.GutenbergSherlock, cache.GutenbergBook28} {
+		path, err := c.Fetch(src)
+		if err != nil {
+			t.Errorf("%s: %v", src.Name, err)
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Count(string(data), "\n"); lines < 10 {
+			t.Errorf("%s: %d lines", src.Name, lines)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package fixtures
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// OHLCFormat picks the CSV layout of a price fixture.
+type OHLCFormat int
+
+const (
+	// VIX matches datasets/finance-vix: DATE,OPEN,HIGH,LOW,CLOSE, oldest
+	// first, MM/DD/YYYY dates.
+	VIX OHLCFormat = iota
+	// AlphaVantage matches TIME_SERIES_DAILY with datatype=csv:
+	// timestamp,open,high,low,close,volume, newest first.
+	AlphaVantage
+)
+
+// OHLC writes days of random-walk prices. Daily moves have a 3% standard
+// deviation so every up/flat/down bucket the experiments use is populated.
+func OHLC(w io.Writer, format OHLCFormat, days int, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	start := time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	type bar struct {
+		date                   time.Time
+		open, high, low, close float64
+		volume                 int
+	}
+	bars := make([]bar, days)
+	price := 20.0
+	for i := range bars {
+		open := price
+		price = math.Max(1, price*(1+rng.NormFloat64()*0.03))
+		hi := math.Max(open, price) * (1 + rng.Float64()*0.01)
+		lo := math.Min(open, price) * (1 - rng.Float64()*0.01)
+		bars[i] = bar{start.AddDate(0, 0, i), open, hi, lo, price, 1e5 + rng.Intn(9e5)}
+	}
+
+	switch format {
+	case VIX:
+		if _, err := fmt.Fprintln(w, "DATE,OPEN,HIGH,LOW,CLOSE"); err != nil {
+			return err
+		}
+		for _, b := range bars {
+			if _, err := fmt.Fprintf(w, "%s,%.2f,%.2f,%.2f,%.2f\n",
+				b.date.Format("01/02/2006"), b.open, b.high, b.low, b.close); err != nil {
+				return err
+			}
+		}
+	case AlphaVantage:
+		if _, err := fmt.Fprintln(w, "timestamp,open,high,low,close,volume"); err != nil {
+			return err
+		}
+		for i := len(bars) - 1; i >= 0; i-- {
+			b := bars[i]
+			if _, err := fmt.Fprintf(w, "%s,%.4f,%.4f,%.4f,%.4f,%d\n",
+				b.date.Format("2006-01-02"), b.open, b.high, b.low, b.close, b.volume); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown OHLC format %d", format)
+	}
+	return nil
+}
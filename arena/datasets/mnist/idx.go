@@ -0,0 +1,160 @@
+package mnist
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// IDX magic numbers: two zero bytes, the 0x08 (unsigned byte) type code and
+// the number of dimensions.
+const (
+	imageMagic = 0x00000803
+	labelMagic = 0x00000801
+)
+
+// Header counts are checked before anything is allocated from them: against
+// the bytes left in the file when the reader can tell, and against these
+// limits always, so a corrupt or truncated header fails instead of
+// exhausting memory.
+const (
+	maxItems  = 1 << 24 // images or labels per file
+	maxPixels = 1 << 20 // pixels per image
+)
+
+// remaining is how many bytes r holds after a header of n bytes, or -1
+// when r cannot tell. Files and in-memory readers can; call it before
+// anything is read from r.
+func remaining(r io.Reader, n int) int64 {
+	switch r := r.(type) {
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if fi, err := r.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size() - int64(n)
+		}
+	case interface{ Len() int }:
+		return int64(r.Len() - n)
+	}
+	return -1
+}
+
+// checkCount rejects a header claiming num items of size bytes each that
+// the limits or the left bytes after it cannot hold; left < 0 is unknown.
+func checkCount(left int64, num, size int) error {
+	if num > maxItems {
+		return fmt.Errorf("header claims %d items, more than %d", num, maxItems)
+	}
+	if left >= 0 && int64(num)*int64(size) > left {
+		return fmt.Errorf("header claims %d items of %d bytes, file holds %d", num, size, left)
+	}
+	return nil
+}
+
+// ReadImages streams an IDX3 image file one image at a time, scaling each
+// pixel to [0,1]. Only the current image's bytes are buffered.
+func ReadImages(r io.Reader) (images [][][]float64, rows, cols int, err error) {
+	left := remaining(r, 16)
+	br := bufio.NewReader(r)
+
+	var header [16]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return nil, 0, 0, fmt.Errorf("image header: %w", err)
+	}
+	if magic := binary.BigEndian.Uint32(header[0:4]); magic != imageMagic {
+		return nil, 0, 0, fmt.Errorf("image magic %#08x, want %#08x", magic, imageMagic)
+	}
+	num := int(binary.BigEndian.Uint32(header[4:8]))
+	rows = int(binary.BigEndian.Uint32(header[8:12]))
+	cols = int(binary.BigEndian.Uint32(header[12:16]))
+	if rows == 0 || cols == 0 || rows > maxPixels || cols > maxPixels || rows*cols > maxPixels {
+		return nil, 0, 0, fmt.Errorf("image shape %dx%d", rows, cols)
+	}
+	if err := checkCount(left, num, rows*cols); err != nil {
+		return nil, 0, 0, fmt.Errorf("image %w", err)
+	}
+
+	images = make([][][]float64, num)
+	buf := make([]byte, rows*cols)
+	for i := 0; i < num; i++ {
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, 0, 0, fmt.Errorf("image %d of %d: %w", i, num, err)
+		}
+		img := make([][]float64, rows)
+		for y := 0; y < rows; y++ {
+			img[y] = make([]float64, cols)
+			for x := 0; x < cols; x++ {
+				img[y][x] = float64(buf[y*cols+x]) / 255.0
+			}
+		}
+		images[i] = img
+	}
+	return images, rows, cols, nil
+}
+
+// ReadLabels streams an IDX1 label file and rejects labels outside 0-9.
+func ReadLabels(r io.Reader) ([]int, error) {
+	left := remaining(r, 8)
+	br := bufio.NewReader(r)
+
+	var header [8]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return nil, fmt.Errorf("label header: %w", err)
+	}
+	if magic := binary.BigEndian.Uint32(header[0:4]); magic != labelMagic {
+		return nil, fmt.Errorf("label magic %#08x, want %#08x", magic, labelMagic)
+	}
+	num := int(binary.BigEndian.Uint32(header[4:8]))
+	if err := checkCount(left, num, 1); err != nil {
+		return nil, fmt.Errorf("label %w", err)
+	}
+
+	labels := make([]int, num)
+	for i := 0; i < num; i++ {
+		b, err := br.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("label %d of %d: %w", i, num, err)
+		}
+		if int(b) >= NumClasses {
+			return nil, fmt.Errorf("label %d is %d, want < %d", i, b, NumClasses)
+		}
+		labels[i] = int(b)
+	}
+	return labels, nil
+}
+
+// WriteImages encodes row-major pixel buffers as an IDX3 image file.
+func WriteImages(w io.Writer, rows, cols int, pixels [][]byte) error {
+	header := []uint32{imageMagic, uint32(len(pixels)), uint32(rows), uint32(cols)}
+	if err := binary.Write(w, binary.BigEndian, header); err != nil {
+		return err
+	}
+	for i, p := range pixels {
+		if len(p) != rows*cols {
+			return fmt.Errorf("image %d has %d pixels, want %d", i, len(p), rows*cols)
+		}
+		if _, err := w.Write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteLabels encodes labels as an IDX1 label file.
+func WriteLabels(w io.Writer, labels []int) error {
+	if err := binary.Write(w, binary.BigEndian, []uint32{labelMagic, uint32(len(labels))}); err != nil {
+		return err
+	}
+	buf := make([]byte, len(labels))
+	for i, l := range labels {
+		if l < 0 || l >= NumClasses {
+			return fmt.Errorf("label %d is %d, want 0-%d", i, l, NumClasses-1)
+		}
+		buf[i] = byte(l)
+	}
+	_, err := w.Write(buf)
+	return err
+}
@@ -0,0 +1,188 @@
+// Package mnist downloads and loads the MNIST handwritten digits in the
+// [][][]float64 sample layout paragon networks train on.
+package mnist
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"arena/datasets/cache"
+)
+
+// NumClasses is the number of digit classes.
+const NumClasses = 10
+
+// Split names one half of the dataset by its IDX file prefix.
+type Split string
+
+const (
+	Train Split = "train"
+	Test  Split = "t10k"
+)
+
+// TargetMode selects how labels are turned into training targets.
+type TargetMode int
+
+const (
+	// OneHot encodes label k as {{0,…,1,…,0}} with NumClasses columns.
+	OneHot TargetMode = iota
+	// RawLabel encodes label k as {{k}}.
+	RawLabel
+)
+
+// Dataset is one loaded split.
+type Dataset struct {
+	Split  Split
+	Rows   int
+	Cols   int
+	Images [][][]float64 // Rows×Cols, scaled to [0,1]
+	Labels []int
+}
+
+// Len returns the number of samples.
+func (d *Dataset) Len() int { return len(d.Labels) }
+
+// Inputs returns the images in paragon's input layout.
+func (d *Dataset) Inputs() [][][]float64 { return d.Images }
+
+// Targets encodes every label with the given mode.
+func (d *Dataset) Targets(mode TargetMode) [][][]float64 {
+	targets := make([][][]float64, len(d.Labels))
+	for i, l := range d.Labels {
+		targets[i] = Target(l, mode)
+	}
+	return targets
+}
+
+// Target encodes a single label.
+func Target(label int, mode TargetMode) [][]float64 {
+	if mode == RawLabel {
+		return [][]float64{{float64(label)}}
+	}
+	row := make([]float64, NumClasses)
+	row[label] = 1.0
+	return [][]float64{row}
+}
+
+// Files returns the uncompressed image and label paths of a split.
+func Files(dir string, split Split) (images, labels string) {
+	return filepath.Join(dir, string(split)+"-images-idx3-ubyte"),
+		filepath.Join(dir, string(split)+"-labels-idx1-ubyte")
+}
+
+// Ensure unpacks any of the four IDX files missing from dir, fetching the
+// gzipped archives through the dataset cache.
+func Ensure(dir string) error {
+	return EnsureFrom(cache.Default(), dir)
+}
+
+// EnsureFrom is Ensure with an explicit cache.
+func EnsureFrom(c *cache.Cache, dir string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	srcs := cache.MNIST()
+	var dests []string
+	for _, split := range []Split{Train, Test} {
+		img, lbl := Files(dir, split)
+		dests = append(dests, img, lbl)
+	}
+	for i, uPath := range dests {
+		if _, err := os.Stat(uPath); err == nil {
+			continue
+		}
+		cPath, err := c.Fetch(srcs[i])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Unzipping %s...\n", filepath.Base(cPath))
+		if err := gunzip(cPath, uPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Open reads one split from dir and checks that images and labels agree.
+func Open(dir string, split Split) (*Dataset, error) {
+	imgPath, lblPath := Files(dir, split)
+
+	imgFile, err := os.Open(imgPath)
+	if err != nil {
+		return nil, err
+	}
+	defer imgFile.Close()
+	images, rows, cols, err := ReadImages(imgFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", imgPath, err)
+	}
+
+	lblFile, err := os.Open(lblPath)
+	if err != nil {
+		return nil, err
+	}
+	defer lblFile.Close()
+	labels, err := ReadLabels(lblFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", lblPath, err)
+	}
+
+	if len(images) != len(labels) {
+		return nil, fmt.Errorf("mnist %s: %d images but %d labels", split, len(images), len(labels))
+	}
+	return &Dataset{Split: split, Rows: rows, Cols: cols, Images: images, Labels: labels}, nil
+}
+
+// Load is Open followed by Inputs and Targets, the shape every experiment
+// feeds straight into Network.Train.
+func Load(dir string, split Split, mode TargetMode) ([][][]float64, [][][]float64, error) {
+	ds, err := Open(dir, split)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ds.Inputs(), ds.Targets(mode), nil
+}
+
+// LoadAll loads Train followed by Test as one sample set, for experiments
+// that make their own split.
+func LoadAll(dir string, mode TargetMode) ([][][]float64, [][][]float64, error) {
+	trainX, trainY, err := Load(dir, Train, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+	testX, testY, err := Load(dir, Test, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(trainX, testX...), append(trainY, testY...), nil
+}
+
+func gunzip(src, dest string) error {
+	fSrc, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer fSrc.Close()
+	gz, err := gzip.NewReader(fSrc)
+	if err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+	defer gz.Close()
+	tmp := dest + ".part"
+	fDest, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fDest, gz); err != nil {
+		fDest.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("%s: %w", src, err)
+	}
+	if err := fDest.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
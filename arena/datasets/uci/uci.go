@@ -0,0 +1,159 @@
+// Package uci loads the UCI tables the experiments use, through the dataset
+// cache. Rows come back in paragon's shape: inputs [1][features] with the
+// raw values, targets one-hot [1][classes].
+//
+//	x, y, err := uci.EEGEyeState(cache.Default())
+package uci
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"arena/datasets/cache"
+)
+
+// EEGFeatures is the number of EEG channels.
+const EEGFeatures = 14
+
+// BankFeatures are the numeric columns of bank.csv Bank keeps, in order.
+var BankFeatures = []string{"age", "balance", "day", "duration", "campaign", "pdays", "previous"}
+
+// EEGEyeState fetches and reads the EEG eye state recording: 14 channels,
+// class 1 when the eyes are closed.
+func EEGEyeState(c *cache.Cache) (inputs, targets [][][]float64, err error) {
+	path, err := c.Fetch(cache.EEGEyeState)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("uci: %w", err)
+	}
+	defer f.Close()
+	return ReadEEG(f)
+}
+
+// ReadEEG reads the recording as ARFF, or as the CSV replayEyeState uses
+// (labels written b'0' and b'1'); header and attribute lines are skipped.
+func ReadEEG(r io.Reader) (inputs, targets [][][]float64, err error) {
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || text[0] == '@' || text[0] == '%' {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		if len(fields) != EEGFeatures+1 {
+			return nil, nil, fmt.Errorf("uci: eeg line %d: %d fields, want %d", line, len(fields), EEGFeatures+1)
+		}
+		x := make([]float64, EEGFeatures)
+		ok := true
+		for i := range x {
+			v, err := strconv.ParseFloat(strings.TrimSpace(fields[i]), 64)
+			if err != nil {
+				ok = false // a CSV header
+				break
+			}
+			x[i] = v
+		}
+		if !ok {
+			continue
+		}
+		label, err := strconv.Atoi(strings.Trim(strings.TrimSpace(fields[EEGFeatures]), "b'\""))
+		if err != nil || label < 0 || label > 1 {
+			return nil, nil, fmt.Errorf("uci: eeg line %d: bad label %q", line, fields[EEGFeatures])
+		}
+		inputs = append(inputs, [][]float64{x})
+		targets = append(targets, oneHot(label, 2))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, fmt.Errorf("uci: %w", err)
+	}
+	return inputs, targets, nil
+}
+
+// Bank fetches the bank marketing archive and reads its bank.csv: the
+// BankFeatures columns, class 1 when the client subscribed.
+func Bank(c *cache.Cache) (inputs, targets [][][]float64, err error) {
+	path, err := c.Fetch(cache.BankMarketing)
+	if err != nil {
+		return nil, nil, err
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("uci: %w", err)
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		if f.Name != "bank.csv" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, nil, fmt.Errorf("uci: %w", err)
+		}
+		defer rc.Close()
+		return ReadBank(rc)
+	}
+	return nil, nil, fmt.Errorf("uci: bank.csv not in %s", path)
+}
+
+// ReadBank reads the semicolon-separated bank.csv.
+func ReadBank(r io.Reader) (inputs, targets [][][]float64, err error) {
+	cr := csv.NewReader(r)
+	cr.Comma = ';'
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("uci: bank header: %w", err)
+	}
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.TrimSpace(name)] = i
+	}
+	idx := make([]int, len(BankFeatures))
+	for i, name := range BankFeatures {
+		c, ok := col[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("uci: bank.csv has no %q column", name)
+		}
+		idx[i] = c
+	}
+	yCol, ok := col["y"]
+	if !ok {
+		return nil, nil, fmt.Errorf("uci: bank.csv has no \"y\" column")
+	}
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("uci: bank line %d: %w", line, err)
+		}
+		x := make([]float64, len(idx))
+		for i, c := range idx {
+			if x[i], err = strconv.ParseFloat(strings.TrimSpace(row[c]), 64); err != nil {
+				return nil, nil, fmt.Errorf("uci: bank line %d: %s: %w", line, BankFeatures[i], err)
+			}
+		}
+		label := 0
+		if strings.TrimSpace(row[yCol]) == "yes" {
+			label = 1
+		}
+		inputs = append(inputs, [][]float64{x})
+		targets = append(targets, oneHot(label, 2))
+	}
+	return inputs, targets, nil
+}
+
+func oneHot(label, classes int) [][]float64 {
+	t := make([]float64, classes)
+	t[label] = 1
+	return [][]float64{t}
+}
@@ -0,0 +1,413 @@
+package distill
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"arena/eval"
+	"arena/parallel"
+	"arena/results"
+	"arena/rng"
+	"arena/stats"
+	"paragon"
+)
+
+// probes are the probe generators of a query-budgeted run. Anchors are the
+// few real inputs the attacker is assumed to hold.
+var probes = map[string]string{
+	"real":        "unseen training inputs in a random order, the unlimited-data baseline",
+	"noise":       "uniform noise over the anchors' value range",
+	"interpolate": "(1-λ)·a + λ·b for two random anchors and a uniform λ",
+	"reverse":     "the student's ReverseInferFromOutput of a random peaked output, clipped to the anchors' range",
+}
+
+// ProbeNames returns the probe generators Budgeted accepts, sorted.
+func ProbeNames() []string {
+	names := make([]string, 0, len(probes))
+	for n := range probes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProbeDoc returns a one-line description of a probe generator.
+func ProbeDoc(name string) string { return probes[name] }
+
+// KnownProbe reports whether Budgeted accepts name.
+func KnownProbe(name string) bool {
+	_, ok := probes[name]
+	return ok
+}
+
+// BudgetOptions configures Budgeted. Zero fields take the defaults noted.
+type BudgetOptions[T paragon.Numeric] struct {
+	// Student returns a fresh student initialised from seed. Required.
+	Student func(seed int64) *paragon.Network[T]
+	// Budgets are the query counts at which the student is scored,
+	// ascending. Required.
+	Budgets []int
+	Rule    string // update rule; "upstream"
+	Params  Params
+	Anchors int     // real inputs held, the first of TrainX; 10
+	Epochs  int     // passes over every probe queried so far, after each budget; 3
+	Clip    float64 // the per-unit error is clipped to ±Clip; 0.1
+	Runs    int     // runs per probe generator; 3
+	Workers int     // concurrent students; 80% of the CPUs
+	// Seeds is required. Run r initialises the student, orders the
+	// samples and drives the rule from Seeds.Sub("run=<r>"), the same for
+	// every probe generator.
+	Seeds *rng.Seeds
+	// Recorder, when set, gets one final record per student and budget,
+	// model "distill-budget/<probe>" with params probe, rule, run and
+	// queries.
+	Recorder *results.Recorder
+}
+
+func (o *BudgetOptions[T]) defaults() {
+	if o.Rule == "" {
+		o.Rule = "upstream"
+	}
+	if o.Anchors == 0 {
+		o.Anchors = 10
+	}
+	if o.Epochs == 0 {
+		o.Epochs = 3
+	}
+	if o.Clip == 0 {
+		o.Clip = 0.1
+	}
+	if o.Runs == 0 {
+		o.Runs = parallel.Runs
+	}
+	o.Workers = parallel.Workers(o.Workers)
+}
+
+// Checkpoint is one student's fidelity after Queries teacher queries.
+type Checkpoint struct {
+	Probe     string
+	Run       int
+	Seed      int64
+	Queries   int
+	Agreement float64 // % of test inputs where student and teacher pick the same class
+	Result    eval.Result
+	Wall      time.Duration // training time up to this budget
+}
+
+// Budgeted distils students that may ask teacher about at most the last of
+// opts.Budgets inputs, generated by each named probe generator. A student
+// grows its probe set to each budget in turn, querying the teacher only on
+// the new probes, trains Epochs passes over everything queried so far and
+// is scored against the teacher on data's test set; those outputs are not
+// counted as queries. data.TrainX feeds the real probes and the anchors;
+// data.TrainTeacher is not used. Checkpoints come back ordered by probe,
+// run and budget.
+func Budgeted[T paragon.Numeric](teacher *paragon.Network[T], names []string, data Data, opts BudgetOptions[T]) ([]Checkpoint, error) {
+	if opts.Seeds == nil || opts.Student == nil || len(opts.Budgets) == 0 {
+		return nil, errors.New("distill: BudgetOptions.Seeds, Student and Budgets are required")
+	}
+	if len(data.TestX) != len(data.TestTeacher) || len(data.TestX) != len(data.TestY) {
+		return nil, errors.New("distill: test inputs and teacher outputs differ in length")
+	}
+	opts.defaults()
+	if !Known(opts.Rule) {
+		return nil, fmt.Errorf("distill: unknown rule %q (have %v)", opts.Rule, Names())
+	}
+	for i, b := range opts.Budgets {
+		if b <= 0 || i > 0 && b <= opts.Budgets[i-1] {
+			return nil, fmt.Errorf("distill: budgets %v must be positive and ascending", opts.Budgets)
+		}
+	}
+	opts.Anchors = min(opts.Anchors, len(data.TrainX))
+	if opts.Anchors == 0 {
+		return nil, errors.New("distill: no anchors in data.TrainX")
+	}
+	last := opts.Budgets[len(opts.Budgets)-1]
+	for _, name := range names {
+		switch {
+		case !KnownProbe(name):
+			return nil, fmt.Errorf("distill: unknown probe generator %q (have %v)", name, ProbeNames())
+		case name == "real" && last > len(data.TrainX):
+			return nil, fmt.Errorf("distill: budget %d exceeds the %d real inputs", last, len(data.TrainX))
+		case name == "interpolate" && opts.Anchors < 2:
+			return nil, errors.New("distill: interpolate needs at least two anchors")
+		}
+	}
+
+	// The teacher is one network shared by every student.
+	var mu sync.Mutex
+	ask := func(x [][]float64) []float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		teacher.Forward(x)
+		return teacher.ExtractOutput()
+	}
+	teacherLabels := make([]int, len(data.TestTeacher))
+	for i, out := range data.TestTeacher {
+		teacherLabels[i] = paragon.ArgMax(out)
+	}
+
+	runs := make([][]Checkpoint, len(names)*opts.Runs)
+	err := parallel.Do(len(runs), opts.Workers, func(i int) (err error) {
+		runs[i], err = budgeted(names[i/opts.Runs], i%opts.Runs, ask, data, teacherLabels, opts)
+		return err
+	})
+	var out []Checkpoint
+	for _, cps := range runs {
+		out = append(out, cps...)
+	}
+	return out, err
+}
+
+// budgeted trains and scores one student at every budget.
+func budgeted[T paragon.Numeric](name string, run int, ask func([][]float64) []float64, data Data, teacherLabels []int, opts BudgetOptions[T]) ([]Checkpoint, error) {
+	streams := opts.Seeds.Subf("run=%d", run)
+	rule, err := New[T](opts.Rule, opts.Params, streams.Rand("rule"))
+	if err != nil {
+		return nil, err
+	}
+	seed := streams.Seed(rng.Init)
+	student := opts.Student(seed)
+	next := newProber[T](name, data.TrainX, opts.Anchors, streams.Rand("probe"))
+	order := streams.Rand(rng.Shuffle)
+
+	var xs [][][]float64
+	var ys [][]float64
+	var wall time.Duration
+	var cps []Checkpoint
+	for _, budget := range opts.Budgets {
+		for len(xs) < budget {
+			x := next(student)
+			xs = append(xs, x)
+			ys = append(ys, ask(x))
+		}
+
+		start := time.Now()
+		for epoch := 0; epoch < opts.Epochs; epoch++ {
+			for _, i := range order.Perm(len(xs)) {
+				student.Forward(xs[i])
+				out := student.ExtractOutput()
+				for j, t := range ys[i] {
+					rule.Update(student, xs[i], max(-opts.Clip, min(opts.Clip, t-out[j])))
+				}
+			}
+		}
+		wall += time.Since(start)
+
+		res := eval.Evaluate(student, data.TestX, data.TestY)
+		cp := Checkpoint{
+			Probe:     name,
+			Run:       run,
+			Seed:      seed,
+			Queries:   budget,
+			Agreement: eval.FromOutputs(eval.Outputs(student, data.TestX), teacherLabels).Accuracy,
+			Result:    res,
+			Wall:      wall,
+		}
+		cps = append(cps, cp)
+		if opts.Recorder == nil {
+			continue
+		}
+		m := res.Metrics()
+		m["agreement"] = cp.Agreement
+		m["queries"] = float64(budget)
+		m["wall_seconds"] = wall.Seconds()
+		params := map[string]any{"probe": name, "rule": opts.Rule, "run": run, "queries": budget}
+		for k, v := range rule.Params() {
+			params[k] = v
+		}
+		err := opts.Recorder.Write(results.Record{
+			Kind:    results.KindFinal,
+			Model:   "distill-budget/" + name,
+			Params:  params,
+			Seed:    seed,
+			ADHD:    res.ADHD,
+			Metrics: m,
+		})
+		if err != nil {
+			return cps, fmt.Errorf("distill: %w", err)
+		}
+	}
+	return cps, nil
+}
+
+// newProber returns the named generator; each call makes one probe shaped
+// like the inputs. pool[:anchors] are the anchors.
+func newProber[T paragon.Numeric](name string, pool [][][]float64, anchors int, r *rand.Rand) func(*paragon.Network[T]) [][]float64 {
+	anchor := pool[:anchors]
+	lo, hi := anchor[0][0][0], anchor[0][0][0]
+	for _, in := range anchor {
+		for _, row := range in {
+			for _, v := range row {
+				lo, hi = min(lo, v), max(hi, v)
+			}
+		}
+	}
+	shaped := func(fill func(y, x int) float64) [][]float64 {
+		out := make([][]float64, len(anchor[0]))
+		for y := range out {
+			out[y] = make([]float64, len(anchor[0][y]))
+			for x := range out[y] {
+				out[y][x] = fill(y, x)
+			}
+		}
+		return out
+	}
+
+	switch name {
+	case "real":
+		order := r.Perm(len(pool))
+		return func(*paragon.Network[T]) [][]float64 {
+			x := pool[order[0]]
+			order = order[1:]
+			return x
+		}
+	case "noise":
+		return func(*paragon.Network[T]) [][]float64 {
+			return shaped(func(int, int) float64 { return lo + r.Float64()*(hi-lo) })
+		}
+	case "interpolate":
+		return func(*paragon.Network[T]) [][]float64 {
+			i := r.Intn(len(anchor))
+			j := (i + 1 + r.Intn(len(anchor)-1)) % len(anchor)
+			a, b, l := anchor[i], anchor[j], r.Float64()
+			return shaped(func(y, x int) float64 { return (1-l)*a[y][x] + l*b[y][x] })
+		}
+	case "reverse":
+		return func(student *paragon.Network[T]) [][]float64 {
+			rec := student.ReverseInferFromOutput(peaked(student, r))
+			return shaped(func(y, x int) float64 {
+				if y >= len(rec) || x >= len(rec[y]) {
+					return lo
+				}
+				return max(lo, min(hi, rec[y][x]))
+			})
+		}
+	}
+	panic("distill: probe generator " + name + " registered without a constructor")
+}
+
+// peaked is a random output of net's shape with 0.5–1 of the mass on one
+// unit and the rest spread at random.
+func peaked[T paragon.Numeric](net *paragon.Network[T], r *rand.Rand) [][]float64 {
+	layer := net.Layers[net.OutputLayer]
+	out := make([][]float64, layer.Height)
+	sum := 0.0
+	for y := range out {
+		out[y] = make([]float64, layer.Width)
+		for x := range out[y] {
+			out[y][x] = r.Float64()
+			sum += out[y][x]
+		}
+	}
+	peak := 0.5 + 0.5*r.Float64()
+	py, px := r.Intn(layer.Height), r.Intn(layer.Width)
+	sum -= out[py][px]
+	for y := range out {
+		for x := range out[y] {
+			if sum > 0 {
+				out[y][x] *= (1 - peak) / sum
+			}
+		}
+	}
+	out[py][px] = peak
+	return out
+}
+
+// CurvePoint is one probe generator's fidelity at one budget, averaged
+// over runs.
+type CurvePoint struct {
+	Probe                  string
+	Queries, Runs          int
+	Agreement, AgreementSD float64
+	Accuracy, ADHD         float64
+}
+
+// Curve averages checkpoints by probe generator and budget, in the order
+// the generators first appear and by ascending budget.
+func Curve(cps []Checkpoint) []CurvePoint {
+	type key struct {
+		probe   string
+		queries int
+	}
+	var order []key
+	by := map[key][]Checkpoint{}
+	for _, c := range cps {
+		k := key{c.Probe, c.Queries}
+		if _, ok := by[k]; !ok {
+			order = append(order, k)
+		}
+		by[k] = append(by[k], c)
+	}
+	rank := map[string]int{}
+	for _, k := range order {
+		if _, ok := rank[k.probe]; !ok {
+			rank[k.probe] = len(rank)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if a, b := rank[order[i].probe], rank[order[j].probe]; a != b {
+			return a < b
+		}
+		return order[i].queries < order[j].queries
+	})
+	out := make([]CurvePoint, 0, len(order))
+	for _, k := range order {
+		var agree, acc, adhd []float64
+		for _, c := range by[k] {
+			agree = append(agree, c.Agreement)
+			acc = append(acc, c.Result.Accuracy)
+			adhd = append(adhd, c.Result.Score())
+		}
+		p := CurvePoint{Probe: k.probe, Queries: k.queries, Runs: len(agree),
+			Agreement: stats.Mean(agree), Accuracy: stats.Mean(acc), ADHD: stats.Mean(adhd)}
+		if len(agree) > 1 {
+			p.AgreementSD = stats.StdDev(agree)
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// WriteCurve renders agreement with the teacher against query count, one
+// row per budget and one column per probe generator.
+func WriteCurve(w io.Writer, title string, points []CurvePoint) error {
+	var names []string
+	var budgets []int
+	cell := map[string]map[int]CurvePoint{}
+	for _, p := range points {
+		if cell[p.Probe] == nil {
+			names = append(names, p.Probe)
+			cell[p.Probe] = map[int]CurvePoint{}
+		}
+		if !slices.Contains(budgets, p.Queries) {
+			budgets = append(budgets, p.Queries)
+		}
+		cell[p.Probe][p.Queries] = p
+	}
+	sort.Ints(budgets)
+
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintf(tw, " Queries\t %s\t\n", strings.Join(names, "\t "))
+	for _, b := range budgets {
+		fmt.Fprintf(tw, " %d\t", b)
+		for _, n := range names {
+			if p, ok := cell[n][b]; ok {
+				fmt.Fprintf(tw, " %.2f ± %.2f\t", p.Agreement, p.AgreementSD)
+			} else {
+				fmt.Fprint(tw, " -\t")
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
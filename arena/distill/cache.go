@@ -0,0 +1,216 @@
+package distill
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"arena/datasets/cache"
+	"arena/eval"
+	"paragon"
+)
+
+// Cache keeps teacher outputs in memory and, when Dir is set, on disk, so a
+// teacher runs over a dataset once and every student after that pays only
+// for its own forward passes. Entries are keyed by dataset name and
+// ModelHash of the teacher; the inputs are fingerprinted as well, so a
+// dataset that changed under the same name is recomputed, not served stale.
+//
+//	c := distill.NewCache(distill.DefaultCacheDir())
+//	out, err := distill.TeacherOutputs(c, "mnist/train", teacher, trainX, 2, 4)
+//	...
+//	targets := out.At(4) // soft labels at temperature 4
+type Cache struct {
+	Dir string // "" keeps outputs in memory only
+
+	mu  sync.Mutex
+	mem map[string]*Outputs
+}
+
+// NewCache returns a cache that also stores outputs under dir.
+func NewCache(dir string) *Cache {
+	return &Cache{Dir: dir}
+}
+
+// DefaultCacheDir is the teachers directory of the dataset cache
+// (ARENA_CACHE).
+func DefaultCacheDir() string {
+	return filepath.Join(cache.Default().Dir, "teachers")
+}
+
+// Outputs are one teacher's outputs on one dataset. They are shared
+// between callers of the cache and must not be modified.
+type Outputs struct {
+	Dataset string      `json:"dataset"`
+	Model   string      `json:"model"`  // ModelHash of the teacher
+	Inputs  string      `json:"inputs"` // fingerprint of the inputs
+	Raw     [][]float64 `json:"raw"`    // the teacher's output per input
+	// Soft holds softmax(logits/t) per input, keyed by temperature t as
+	// printed by strconv.FormatFloat(t, 'g', -1, 64).
+	Soft map[string][][]float64 `json:"soft,omitempty"`
+}
+
+// At returns the soft labels at temperature t, computing them from Raw if
+// they were not stored.
+func (o *Outputs) At(t float64) [][]float64 {
+	if s, ok := o.Soft[tempKey(t)]; ok {
+		return s
+	}
+	return eval.Temperature(o.Raw, t)
+}
+
+func tempKey(t float64) string { return strconv.FormatFloat(t, 'g', -1, 64) }
+
+// ModelHash identifies a network by the SHA-256 of its JSON model.
+func ModelHash[T paragon.Numeric](net *paragon.Network[T]) (string, error) {
+	data, err := net.MarshalJSONModel()
+	if err != nil {
+		return "", fmt.Errorf("distill: hash model: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// fingerprint hashes the inputs' shape and values.
+func fingerprint(inputs [][][]float64) string {
+	h := sha256.New()
+	var buf [8]byte
+	for _, in := range inputs {
+		for _, row := range in {
+			binary.LittleEndian.PutUint64(buf[:], uint64(len(row)))
+			h.Write(buf[:])
+			for _, v := range row {
+				binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+				h.Write(buf[:])
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// TeacherOutputs returns teacher's outputs on inputs, which are known as
+// dataset, with soft labels stored at each of temps. It looks in memory,
+// then on disk, and runs the teacher only when neither has them; soft
+// labels missing from a hit are added and written back. A corrupt or
+// truncated file counts as a miss and is overwritten. The cache is locked
+// while the teacher runs.
+func TeacherOutputs[T paragon.Numeric](c *Cache, dataset string, teacher *paragon.Network[T], inputs [][][]float64, temps ...float64) (*Outputs, error) {
+	for _, t := range temps {
+		if !(t > 0) || math.IsInf(t, 0) {
+			return nil, fmt.Errorf("distill: temperature %g must be positive", t)
+		}
+	}
+	model, err := ModelHash(teacher)
+	if err != nil {
+		return nil, err
+	}
+	fp := fingerprint(inputs)
+	key := dataset + "@" + model
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := c.mem[key]
+	if out != nil && out.Inputs != fp {
+		out = nil
+	}
+	dirty := false
+	if out == nil && c.Dir != "" {
+		out, err = c.read(dataset, model)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if out != nil && (out.Inputs != fp || len(out.Raw) != len(inputs)) {
+			out = nil
+		}
+	}
+	if out == nil {
+		out = &Outputs{Dataset: dataset, Model: model, Inputs: fp, Raw: eval.Outputs(teacher, inputs)}
+		dirty = true
+	}
+	for _, t := range temps {
+		if _, ok := out.Soft[tempKey(t)]; ok {
+			continue
+		}
+		if !dirty {
+			// callers may be reading the shared copy
+			cp := *out
+			cp.Soft = maps.Clone(out.Soft)
+			out = &cp
+			dirty = true
+		}
+		if out.Soft == nil {
+			out.Soft = map[string][][]float64{}
+		}
+		out.Soft[tempKey(t)] = eval.Temperature(out.Raw, t)
+	}
+	if dirty && c.Dir != "" {
+		if err := c.write(out); err != nil {
+			return nil, err
+		}
+	}
+	if c.mem == nil {
+		c.mem = map[string]*Outputs{}
+	}
+	c.mem[key] = out
+	return out, nil
+}
+
+// CachedData is TeacherData through c: the training and test outputs are
+// cached as dataset+"/train" and dataset+"/test".
+func CachedData[T paragon.Numeric](c *Cache, dataset string, teacher *paragon.Network[T], trainX, testX, testY [][][]float64) (Data, error) {
+	train, err := TeacherOutputs(c, dataset+"/train", teacher, trainX)
+	if err != nil {
+		return Data{}, err
+	}
+	test, err := TeacherOutputs(c, dataset+"/test", teacher, testX)
+	if err != nil {
+		return Data{}, err
+	}
+	return Data{TrainX: trainX, TrainTeacher: train.Raw, TestX: testX, TestY: testY, TestTeacher: test.Raw}, nil
+}
+
+// path is where the outputs of model on dataset live under c.Dir.
+func (c *Cache) path(dataset, model string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(dataset)
+	return filepath.Join(c.Dir, name, model+".json")
+}
+
+// read returns the stored outputs, nil when the file does not decode
+// (a write cut short, say), so the caller recomputes and overwrites it.
+func (c *Cache) read(dataset, model string) (*Outputs, error) {
+	data, err := os.ReadFile(c.path(dataset, model))
+	if err != nil {
+		return nil, err
+	}
+	var out Outputs
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, nil
+	}
+	return &out, nil
+}
+
+func (c *Cache) write(out *Outputs) error {
+	path := c.path(out.Dataset, out.Model)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".part"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
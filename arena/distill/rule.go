@@ -0,0 +1,206 @@
+// Package distill is the shared library of gradient-free update rules for
+// black-box distillation, collected from invert2. A rule nudges a
+// student's biases and weights after a forward pass, given only the
+// signed difference between the teacher's and the student's output on one
+// unit; it never sees the teacher's weights or a gradient.
+//
+//	rule, err := distill.New[float64]("hebb-error", distill.Params{"lr": 0.02}, seeds.Rand("rule"))
+//	...
+//	student.Forward(input)
+//	out := student.ExtractOutput()
+//	for j := range teacherOut {
+//		rule.Update(student, input, teacherOut[j]-out[j])
+//	}
+//
+// Drivers pick rules by name, so sweeps and tournaments can list them;
+// Schema and Space describe each rule's parameters.
+package distill
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"sort"
+
+	"arena/search"
+	"paragon"
+)
+
+// LocalUpdateRule is one update rule bound to its parameters. A rule may
+// keep state between updates (momentum buffers, a phase clock), so build
+// one per student with New, or Reset it before reusing it.
+type LocalUpdateRule[T paragon.Numeric] interface {
+	Name() string
+	// Params returns the rule's parameters, defaults filled in.
+	Params() Params
+	// Update adjusts net after net.Forward(input); err is the teacher's
+	// output minus the student's on one output unit.
+	Update(net *paragon.Network[T], input [][]float64, err float64)
+	// Reset drops the state kept between updates.
+	Reset()
+}
+
+// Params are a rule's settings by name.
+type Params map[string]float64
+
+// Param describes one parameter of a rule.
+type Param struct {
+	Name     string
+	Doc      string
+	Default  float64
+	Min, Max float64
+	Log      bool // search on a log scale
+}
+
+// common are the parameters every rule takes, with invert2's defaults.
+var common = []Param{
+	{Name: "lr", Doc: "learning rate", Default: 0.01, Min: 1e-4, Max: 1, Log: true},
+	{Name: "max_update", Doc: "cap on any one bias or weight change", Default: 0.5, Min: 1e-3, Max: 5, Log: true},
+	{Name: "damping", Doc: "global scale on the error signal", Default: 0.3, Min: 1e-3, Max: 1, Log: true},
+}
+
+type ruleInfo struct {
+	doc    string
+	params []Param // beyond common
+	random bool    // needs a random source
+}
+
+var rules = map[string]ruleInfo{
+	"upstream": {doc: "scalar error pushed from the output inwards, weights scaled by the mean input (invert2's best)",
+		params: []Param{
+			{Name: "proxy_mod", Doc: "multiplier on the input proxy (adjustNetworkUpstreamModulated)", Default: 1, Min: -1, Max: 1},
+			{Name: "proxy_decay", Doc: "proxy kept per layer inwards; 1 is adjustNetworkUpstreamNoSignalAdjsutment", Default: 0.9, Min: 0.5, Max: 1},
+		}},
+	"upstream-smart":        {doc: "upstream with a mean |input| proxy and linear depth scaling"},
+	"upstream-depth-scaled": {doc: "upstream with an RMS proxy, 0.7^depth scaling, 1/(1+|w|) clipping and L2 decay"},
+	"behavioral-pulse":      {doc: "a pulse along each weight's sign, smaller for large weights, fading 0.8 per layer"},
+	"wave-prop":             {doc: "a wave of error energy with centre-weighted input proxy and spatial momentum"},
+	"pulse-flow":            {doc: "log-scaled error over fan-in, skipping 10% of weights at random", random: true},
+	"sparse-echo":           {doc: "sqrt-scaled error on active neurons and above-average weights only", random: true},
+	"feature-echo":          {doc: "error scaled by the input quadrant each neuron and source sits in"},
+	"stdp-direct":           {doc: "STDP-style: weights move with error times source activation"},
+	"hebb-error":            {doc: "Hebbian pre·post coincidence gated by the signed error"},
+	"momentum":              {doc: "error times source activation, smoothed by per-weight momentum (beta = 1 - damping)"},
+	"phase-tuned-contrast":  {doc: "error times an oscillating phase and the input's local contrast, contrastive weights"},
+}
+
+// Names returns the rule names New accepts, sorted.
+func Names() []string {
+	names := make([]string, 0, len(rules))
+	for n := range rules {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Doc returns a one-line description of a rule.
+func Doc(name string) string { return rules[name].doc }
+
+// Known reports whether New accepts name.
+func Known(name string) bool {
+	_, ok := rules[name]
+	return ok
+}
+
+// Schema returns the parameters a rule takes, the common ones first.
+func Schema(name string) []Param {
+	info, ok := rules[name]
+	if !ok {
+		return nil
+	}
+	return append(append([]Param(nil), common...), info.params...)
+}
+
+// Space is a rule's schema as a search space.
+func Space(name string) search.Space {
+	var s search.Space
+	for _, p := range Schema(name) {
+		if p.Log {
+			s = append(s, search.LogFloat(p.Name, p.Min, p.Max))
+		} else {
+			s = append(s, search.Float(p.Name, p.Min, p.Max))
+		}
+	}
+	return s
+}
+
+// FromPoint picks a rule's parameters out of a search point.
+func FromPoint(name string, pt search.Point) Params {
+	p := Params{}
+	for _, s := range Schema(name) {
+		if _, ok := pt[s.Name]; ok {
+			p[s.Name] = pt.Float(s.Name)
+		}
+	}
+	return p
+}
+
+// New returns the named rule with p over its defaults. Parameters the rule
+// does not take are an error. r drives the rules that update at random
+// (see Doc) and may be nil for the others.
+func New[T paragon.Numeric](name string, p Params, r *rand.Rand) (LocalUpdateRule[T], error) {
+	info, ok := rules[name]
+	if !ok {
+		return nil, fmt.Errorf("distill: unknown rule %q (have %v)", name, Names())
+	}
+	vals := Params{}
+	for _, s := range Schema(name) {
+		vals[s.Name] = s.Default
+	}
+	for k, v := range p {
+		if _, ok := vals[k]; !ok {
+			return nil, fmt.Errorf("distill: rule %s has no parameter %q", name, k)
+		}
+		vals[k] = v
+	}
+	if info.random && r == nil {
+		return nil, fmt.Errorf("distill: rule %s needs a random source", name)
+	}
+	b := base{name: name, params: vals, lr: vals["lr"], maxUpdate: vals["max_update"], damping: vals["damping"]}
+	switch name {
+	case "upstream":
+		return &upstream[T]{base: b, mod: vals["proxy_mod"], decay: vals["proxy_decay"]}, nil
+	case "upstream-smart":
+		return &upstreamSmart[T]{b}, nil
+	case "upstream-depth-scaled":
+		return &depthScaled[T]{b}, nil
+	case "behavioral-pulse":
+		return &behavioralPulse[T]{b}, nil
+	case "wave-prop":
+		return &waveProp[T]{b}, nil
+	case "pulse-flow":
+		return &pulseFlow[T]{base: b, r: r}, nil
+	case "sparse-echo":
+		return &sparseEcho[T]{base: b, r: r}, nil
+	case "feature-echo":
+		return &featureEcho[T]{b}, nil
+	case "stdp-direct":
+		return &stdpDirect[T]{b}, nil
+	case "hebb-error":
+		return &hebbError[T]{b}, nil
+	case "momentum":
+		m := &momentum[T]{base: b}
+		m.Reset()
+		return m, nil
+	case "phase-tuned-contrast":
+		return &phaseContrast[T]{base: b}, nil
+	}
+	panic("distill: rule " + name + " registered without a constructor")
+}
+
+// base carries the parameters every rule shares.
+type base struct {
+	name                   string
+	params                 Params
+	lr, maxUpdate, damping float64
+}
+
+func (b *base) Name() string   { return b.name }
+func (b *base) Params() Params { return maps.Clone(b.params) }
+func (b *base) Reset()         {}
+
+// clamp caps v to ±b.maxUpdate.
+func (b *base) clamp(v float64) float64 {
+	return max(-b.maxUpdate, min(b.maxUpdate, v))
+}
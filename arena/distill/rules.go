@@ -0,0 +1,488 @@
+package distill
+
+import (
+	"math"
+	"math/rand"
+
+	"paragon"
+)
+
+// Every rule walks the layers from the output back to the first hidden
+// one, as invert2's adjustNetwork* helpers did. They are written for
+// float networks; integer weights round the small steps away.
+
+// upstream is adjustNetworkUpstream, with adjustNetworkUpstreamModulated's
+// proxy multiplier and a configurable proxy decay.
+type upstream[T paragon.Numeric] struct {
+	base
+	mod, decay float64
+}
+
+func (u *upstream[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	proxy := meanInput(input) * u.mod
+	for l := net.OutputLayer; l > 0; l-- {
+		adj := u.clamp(u.lr * err * u.damping)
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			n.Bias += T(adj)
+			for i := range n.Inputs {
+				n.Inputs[i].Weight += T(adj * proxy)
+			}
+		})
+		proxy *= u.decay
+	}
+}
+
+// upstreamSmart is adjustNetworkUpstreamSmart.
+type upstreamSmart[T paragon.Numeric] struct{ base }
+
+func (u *upstreamSmart[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	proxy := 1.0
+	if n := cells(input); n > 0 {
+		var total float64
+		for _, row := range input {
+			for _, v := range row {
+				total += math.Abs(v)
+			}
+		}
+		proxy = total / float64(n)
+	}
+	out := net.OutputLayer
+	for l := out; l > 0; l-- {
+		scale := 1 - float64(out-l)/float64(out)
+		adj := u.clamp(u.lr * err * u.damping * scale)
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			n.Bias += T(adj)
+			for i := range n.Inputs {
+				n.Inputs[i].Weight += T(adj * proxy)
+			}
+		})
+		proxy *= 0.9
+	}
+}
+
+// depthScaled is adjustNetworkUpstreamDepthScaled.
+type depthScaled[T paragon.Numeric] struct{ base }
+
+func (d *depthScaled[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	rms := rmsInput(input)
+	out := net.OutputLayer
+	for l := out; l > 0; l-- {
+		adj := d.clamp(d.lr * err * d.damping * math.Pow(0.7, float64(out-l)))
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			n.Bias += T(adj)
+			for i := range n.Inputs {
+				w := float64(n.Inputs[i].Weight)
+				dw := d.clamp(adj * rms / (1 + math.Abs(w)))
+				n.Inputs[i].Weight += T(dw - d.lr*1e-4*w)
+			}
+		})
+		rms *= 0.9
+	}
+}
+
+// behavioralPulse is adjustNetworkBehavioralPulse.
+type behavioralPulse[T paragon.Numeric] struct{ base }
+
+func (b *behavioralPulse[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	// Intensity weighted by distance from the top-left corner.
+	proxy := 1.0
+	if n := cells(input); n > 0 {
+		var total float64
+		for y, row := range input {
+			for x, v := range row {
+				total += v * float64(x+y)
+			}
+		}
+		proxy = total / float64(n)
+	}
+	pulse := b.clamp(err * b.damping)
+	out := net.OutputLayer
+	for l := out; l > 0; l-- {
+		factor := 1 / float64(out-l+1)
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			n.Bias += T(pulse * b.lr * factor)
+			for i := range n.Inputs {
+				w := float64(n.Inputs[i].Weight)
+				s := 1.0
+				if w < 0 {
+					s = -1
+				}
+				n.Inputs[i].Weight += T(b.clamp(s * pulse * b.lr * proxy * factor / (1 + math.Abs(w))))
+			}
+		})
+		pulse *= 0.8
+	}
+}
+
+// waveProp is adjustNetworkWaveProp.
+type waveProp[T paragon.Numeric] struct{ base }
+
+func (w *waveProp[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	// Input intensity weighted towards the centre, where MNIST digits sit.
+	proxy, weights := 0.0, 0.0
+	rows := len(input)
+	for y, row := range input {
+		for x, v := range row {
+			g := gauss(x-len(row)/2, y-rows/2, rows)
+			proxy += v * g
+			weights += g
+		}
+	}
+	if weights > 0 {
+		proxy /= weights
+	} else {
+		proxy = 1
+	}
+
+	energy := math.Min(math.Abs(err)*w.damping, w.maxUpdate)
+	momentum := 0.0
+	out := net.OutputLayer
+	for l := out; l > 0; l-- {
+		layer := &net.Layers[l]
+		depth := 1 - float64(out-l)/float64(out+1)
+		damp := w.damping * (0.5 + 0.5*math.Tanh(energy)) * depth
+		forNeurons(layer, func(y, x int, n *paragon.Neuron[T]) {
+			adj := math.Tanh(w.lr*err*damp*(1+0.2*momentum)) * w.maxUpdate
+			n.Bias += T(adj)
+			for i := range n.Inputs {
+				wt := float64(n.Inputs[i].Weight)
+				dw := adj*proxy/(1+math.Abs(wt)) + 0.1*momentum*sign(wt)
+				n.Inputs[i].Weight += T(w.clamp(dw))
+			}
+			// Neurons near the layer's centre build more momentum.
+			momentum += energy * gauss(x-layer.Width/2, y-layer.Height/2, layer.Width) * 0.1
+		})
+		energy *= 0.85
+		momentum *= 0.9
+	}
+}
+
+// pulseFlow is adjustNetworkPulseFlow.
+type pulseFlow[T paragon.Numeric] struct {
+	base
+	r *rand.Rand
+}
+
+func (p *pulseFlow[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	proxy := 1.0
+	if cells(input) > 0 {
+		proxy = meanInput(input)
+	}
+	scaled := p.clamp(math.Log1p(math.Abs(err)*10) * p.damping)
+	if err < 0 {
+		scaled = -scaled
+	}
+	out := net.OutputLayer
+	for l := out; l > 0; l-- {
+		depth := float64(l) / float64(out+1)
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			adj := p.lr * scaled / (1 + float64(len(n.Inputs))) * depth
+			n.Bias += T(adj)
+			for i := range n.Inputs {
+				if p.r.Float64() < 0.1 {
+					continue
+				}
+				n.Inputs[i].Weight += T(p.clamp(adj * proxy))
+			}
+		})
+		scaled *= 0.95
+	}
+}
+
+// sparseEcho is adjustNetworkSparseEcho.
+type sparseEcho[T paragon.Numeric] struct {
+	base
+	r *rand.Rand
+}
+
+func (s *sparseEcho[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	proxy := 0.0
+	for _, row := range input {
+		for _, v := range row {
+			proxy = math.Max(proxy, v)
+		}
+	}
+	if proxy == 0 {
+		proxy = 1
+	}
+	scaled := s.clamp(math.Sqrt(math.Abs(err)) * s.damping * 2)
+	if err < 0 {
+		scaled = -scaled
+	}
+
+	// Only weights above half the mean magnitude move.
+	var total, count float64
+	for l := 1; l <= net.OutputLayer; l++ {
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			for _, c := range n.Inputs {
+				total += math.Abs(float64(c.Weight))
+				count++
+			}
+		})
+	}
+	threshold := 0.0
+	if count > 0 {
+		threshold = total / count * 0.5
+	}
+
+	for l := net.OutputLayer; l > 0; l-- {
+		adj := s.lr * scaled
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			if s.r.Float64() < 0.2 && math.Abs(float64(n.Value)) < 0.1 {
+				return
+			}
+			n.Bias += T(adj)
+			for i := range n.Inputs {
+				if math.Abs(float64(n.Inputs[i].Weight)) < threshold {
+					continue
+				}
+				n.Inputs[i].Weight += T(s.clamp(adj * proxy))
+			}
+		})
+		scaled *= 0.8
+	}
+}
+
+// featureEcho is adjustNetworkFeatureEcho.
+type featureEcho[T paragon.Numeric] struct{ base }
+
+func (f *featureEcho[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	// Mean intensity of each input quadrant.
+	rows := len(input)
+	cols := 0
+	if rows > 0 {
+		cols = len(input[0])
+	}
+	size := max(1, rows/2)
+	var proxies [4]float64
+	for q := range proxies {
+		var sum float64
+		for y := (q / 2) * size; y < (q/2+1)*size && y < rows; y++ {
+			for x := (q % 2) * size; x < (q%2+1)*size && x < cols; x++ {
+				sum += input[y][x]
+			}
+		}
+		proxies[q] = sum / float64(size*size)
+	}
+
+	scaled := f.clamp((2/(1+math.Exp(-math.Abs(err)*5)) - 1) * f.damping)
+	if err < 0 {
+		scaled = -scaled
+	}
+	out := net.OutputLayer
+	for l := out; l > 0; l-- {
+		layer := &net.Layers[l]
+		depth := 1 - float64(out-l)/float64(out+1)
+		forNeurons(layer, func(y, x int, n *paragon.Neuron[T]) {
+			adj := f.lr * scaled * proxies[quadrant(layer.Width, layer.Height, y, x)] * depth
+			n.Bias += T(adj)
+			for i := range n.Inputs {
+				c := n.Inputs[i]
+				src := &net.Layers[c.SourceLayer]
+				n.Inputs[i].Weight += T(f.clamp(adj * proxies[quadrant(src.Width, src.Height, c.SourceY, c.SourceX)]))
+			}
+		})
+		scaled *= 0.9
+	}
+}
+
+// stdpDirect is adjustNetworkSTDPDirect.
+type stdpDirect[T paragon.Numeric] struct{ base }
+
+func (s *stdpDirect[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	out := net.OutputLayer
+	for l := out; l > 0; l-- {
+		scale := s.lr * s.damping * math.Pow(0.7, float64(out-l))
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			n.Bias += T(s.clamp(scale * err))
+			for i := range n.Inputs {
+				c := &n.Inputs[i]
+				w := float64(c.Weight)
+				dw := s.clamp(scale * err * source(net, input, *c) / (1 + math.Abs(w)))
+				c.Weight += T(dw - s.lr*1e-4*w)
+			}
+		})
+	}
+}
+
+// hebbError is adjustNetworkHebbError:
+//
+//	Δw = lr·damping·err·pre·post / (1+|w|),  Δb = lr·damping·err·post
+type hebbError[T paragon.Numeric] struct{ base }
+
+func (h *hebbError[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	out := net.OutputLayer
+	for l := out; l > 0; l-- {
+		scale := h.lr * h.damping * err * math.Pow(0.7, float64(out-l))
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			post := float64(n.Value)
+			n.Bias += T(h.clamp(scale * post))
+			for i := range n.Inputs {
+				c := &n.Inputs[i]
+				w := float64(c.Weight)
+				dw := h.clamp(scale * source(net, input, *c) * post / (1 + math.Abs(w)))
+				c.Weight += T(dw - h.lr*1e-4*w)
+			}
+		})
+	}
+}
+
+// momentum is adjustNetworkMomentum. Its velocities are keyed by the
+// address of each bias and weight, so one instance serves one network.
+type momentum[T paragon.Numeric] struct {
+	base
+	vel map[*T]float64
+}
+
+func (m *momentum[T]) Reset() { m.vel = map[*T]float64{} }
+
+func (m *momentum[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	beta := 1 - m.damping
+	step := func(p *T, raw float64) float64 {
+		v := beta*m.vel[p] + (1-beta)*raw
+		m.vel[p] = v
+		return v
+	}
+	out := net.OutputLayer
+	for l := out; l > 0; l-- {
+		scale := m.lr * err * math.Pow(0.8, float64(out-l))
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			n.Bias += T(step(&n.Bias, m.clamp(scale)))
+			for i := range n.Inputs {
+				c := &n.Inputs[i]
+				w := float64(c.Weight)
+				v := step(&c.Weight, m.clamp(scale*source(net, input, *c)/(1+math.Abs(w))))
+				c.Weight += T(v - m.lr*1e-4*w)
+			}
+		})
+	}
+}
+
+// phaseContrast is adjustNetworkPhaseTunedContrast. Its phase advances by
+// one tick per update. Inputs smaller than 3x3 have no local contrast and
+// leave the network unchanged.
+type phaseContrast[T paragon.Numeric] struct {
+	base
+	tick int
+}
+
+func (p *phaseContrast[T]) Reset() { p.tick = 0 }
+
+func (p *phaseContrast[T]) Update(net *paragon.Network[T], input [][]float64, err float64) {
+	phase := math.Sin(float64(p.tick) * 0.1)
+	p.tick++
+
+	// Mean absolute difference of each interior cell to its 4 neighbours.
+	rows := len(input)
+	if rows < 3 || len(input[0]) < 3 {
+		return
+	}
+	cols := len(input[0])
+	var contrast float64
+	for y := 1; y < rows-1; y++ {
+		for x := 1; x < cols-1; x++ {
+			c := input[y][x]
+			contrast += math.Abs(c-input[y+1][x]) + math.Abs(c-input[y-1][x]) +
+				math.Abs(c-input[y][x+1]) + math.Abs(c-input[y][x-1])
+		}
+	}
+	contrast /= float64((rows - 2) * (cols - 2) * 4)
+
+	out := net.OutputLayer
+	for l := out; l > 0; l-- {
+		depth := math.Pow(0.6, float64(out-l))
+		forNeurons(&net.Layers[l], func(_, _ int, n *paragon.Neuron[T]) {
+			act := float64(n.Value)
+			adj := p.clamp(p.lr * err * phase * act * p.damping * contrast * depth)
+			n.Bias += T(adj)
+			for i := range n.Inputs {
+				c := &n.Inputs[i]
+				c.Weight += T(p.clamp(adj * (source(net, input, *c) - act)))
+			}
+		})
+	}
+}
+
+// forNeurons calls f on every neuron of layer.
+func forNeurons[T paragon.Numeric](layer *paragon.Grid[T], f func(y, x int, n *paragon.Neuron[T])) {
+	for y := 0; y < layer.Height; y++ {
+		for x := 0; x < layer.Width; x++ {
+			f(y, x, layer.Neurons[y][x])
+		}
+	}
+}
+
+// source is the activation feeding c: the raw input for the input layer.
+func source[T paragon.Numeric](net *paragon.Network[T], input [][]float64, c paragon.Connection[T]) float64 {
+	if c.SourceLayer == 0 {
+		return input[c.SourceY][c.SourceX]
+	}
+	return float64(net.Layers[c.SourceLayer].Neurons[c.SourceY][c.SourceX].Value)
+}
+
+func cells(input [][]float64) int {
+	n := 0
+	for _, row := range input {
+		n += len(row)
+	}
+	return n
+}
+
+// meanInput is the mean input value, 0 for an empty input.
+func meanInput(input [][]float64) float64 {
+	n := cells(input)
+	if n == 0 {
+		return 0
+	}
+	var sum float64
+	for _, row := range input {
+		for _, v := range row {
+			sum += v
+		}
+	}
+	return sum / float64(n)
+}
+
+// rmsInput is the root mean square input value, 1 for an empty input.
+func rmsInput(input [][]float64) float64 {
+	n := cells(input)
+	if n == 0 {
+		return 1
+	}
+	var sumSq float64
+	for _, row := range input {
+		for _, v := range row {
+			sumSq += v * v
+		}
+	}
+	return math.Sqrt(sumSq / float64(n))
+}
+
+// gauss weighs an offset (dx, dy) from a centre with invert2's
+// exp(-(dx²+dy²) / (2·⌊size/4⌋)), the spread kept at least 1.
+func gauss(dx, dy, size int) float64 {
+	return math.Exp(-float64(dx*dx+dy*dy) / (2 * float64(max(1, size/4))))
+}
+
+// quadrant is the quadrant (0-3, row-major) of cell (y, x) in a width x
+// height grid; single rows and columns use halves.
+func quadrant(width, height, y, x int) int {
+	q := 0
+	if height > 1 && y >= height/2 {
+		q += 2
+	}
+	if width > 1 && x >= width/2 {
+		q++
+	}
+	return q
+}
+
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
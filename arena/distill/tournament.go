@@ -0,0 +1,251 @@
+package distill
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"arena/eval"
+	"arena/parallel"
+	"arena/results"
+	"arena/rng"
+	"arena/stats"
+	"paragon"
+)
+
+// Data is what a tournament distils from: training inputs with the
+// teacher's output for each, and a labelled test set with the teacher's
+// outputs. The teacher itself is not needed once its outputs are known.
+type Data struct {
+	TrainX       [][][]float64
+	TrainTeacher [][]float64
+	TestX, TestY [][][]float64
+	TestTeacher  [][]float64
+}
+
+// TeacherData runs teacher once over both sets.
+func TeacherData[T paragon.Numeric](teacher *paragon.Network[T], trainX, testX, testY [][][]float64) Data {
+	return Data{
+		TrainX:       trainX,
+		TrainTeacher: eval.Outputs(teacher, trainX),
+		TestX:        testX,
+		TestY:        testY,
+		TestTeacher:  eval.Outputs(teacher, testX),
+	}
+}
+
+// TeacherAccuracy is the teacher's test accuracy, the ceiling a student
+// that agrees with it everywhere reaches.
+func (d Data) TeacherAccuracy() float64 {
+	return eval.FromOutputs(d.TestTeacher, eval.Labels(d.TestY)).Accuracy
+}
+
+// Options configures Tournament. Zero fields take the defaults noted.
+type Options[T paragon.Numeric] struct {
+	// Student returns a fresh student initialised from seed. Required.
+	Student func(seed int64) *paragon.Network[T]
+	Epochs  int     // passes over TrainX; 3
+	Clip    float64 // the per-unit error is clipped to ±Clip; 0.1
+	Params  map[string]Params
+	Runs    int // runs per rule; 3
+	Workers int // concurrent students; 80% of the CPUs
+	// Seeds is required. Run r initialises the student, orders the
+	// samples and drives random rules from Seeds.Sub("run=<r>"), the same
+	// for every rule.
+	Seeds *rng.Seeds
+	// Recorder, when set, gets one final record per student, model
+	// "distill/<rule>" with params rule and run.
+	Recorder *results.Recorder
+}
+
+func (o *Options[T]) defaults() {
+	if o.Epochs == 0 {
+		o.Epochs = 3
+	}
+	if o.Clip == 0 {
+		o.Clip = 0.1
+	}
+	if o.Runs == 0 {
+		o.Runs = parallel.Runs
+	}
+	o.Workers = parallel.Workers(o.Workers)
+}
+
+// Entry is one distilled student's result.
+type Entry struct {
+	Rule      string
+	Params    Params
+	Run       int
+	Seed      int64
+	Agreement float64 // % of test inputs where student and teacher pick the same class
+	Result    eval.Result
+	Wall      time.Duration // training time
+}
+
+// Tournament distils a student with every named rule opts.Runs times, all
+// with the same budget (Epochs passes, one update per output unit per
+// sample), and scores each on the test set. Entries come back ordered by
+// rule and run.
+func Tournament[T paragon.Numeric](rules []string, data Data, opts Options[T]) ([]Entry, error) {
+	if opts.Seeds == nil || opts.Student == nil {
+		return nil, errors.New("distill: Options.Seeds and Options.Student are required")
+	}
+	if len(data.TrainX) != len(data.TrainTeacher) || len(data.TestX) != len(data.TestTeacher) || len(data.TestX) != len(data.TestY) {
+		return nil, errors.New("distill: inputs and teacher outputs differ in length")
+	}
+	opts.defaults()
+	for _, name := range rules {
+		if !Known(name) {
+			return nil, fmt.Errorf("distill: unknown rule %q (have %v)", name, Names())
+		}
+	}
+
+	teacherLabels := make([]int, len(data.TestTeacher))
+	for i, out := range data.TestTeacher {
+		teacherLabels[i] = paragon.ArgMax(out)
+	}
+	entries := make([]Entry, len(rules)*opts.Runs)
+	err := parallel.Do(len(entries), opts.Workers, func(i int) (err error) {
+		entries[i], err = distil(rules[i/opts.Runs], i%opts.Runs, data, teacherLabels, opts)
+		return err
+	})
+	return entries, err
+}
+
+// distil trains and scores one student.
+func distil[T paragon.Numeric](name string, run int, data Data, teacherLabels []int, opts Options[T]) (Entry, error) {
+	streams := opts.Seeds.Subf("run=%d", run)
+	rule, err := New[T](name, opts.Params[name], streams.Rand("rule"))
+	if err != nil {
+		return Entry{}, err
+	}
+	seed := streams.Seed(rng.Init)
+	student := opts.Student(seed)
+	order := streams.Rand(rng.Shuffle).Perm(len(data.TrainX))
+
+	start := time.Now()
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		for _, i := range order {
+			input := data.TrainX[i]
+			student.Forward(input)
+			out := student.ExtractOutput()
+			for j, t := range data.TrainTeacher[i] {
+				rule.Update(student, input, max(-opts.Clip, min(opts.Clip, t-out[j])))
+			}
+		}
+	}
+	wall := time.Since(start)
+
+	res := eval.Evaluate(student, data.TestX, data.TestY)
+	e := Entry{
+		Rule:      name,
+		Params:    rule.Params(),
+		Run:       run,
+		Seed:      seed,
+		Agreement: eval.FromOutputs(eval.Outputs(student, data.TestX), teacherLabels).Accuracy,
+		Result:    res,
+		Wall:      wall,
+	}
+	if opts.Recorder != nil {
+		m := res.Metrics()
+		m["agreement"] = e.Agreement
+		m["wall_seconds"] = wall.Seconds()
+		params := map[string]any{"rule": name, "run": run}
+		for k, v := range e.Params {
+			params[k] = v
+		}
+		err := opts.Recorder.Write(results.Record{
+			Kind:    results.KindFinal,
+			Model:   "distill/" + name,
+			Params:  params,
+			Seed:    seed,
+			ADHD:    res.ADHD,
+			Metrics: m,
+		})
+		if err != nil {
+			return e, fmt.Errorf("distill: %w", err)
+		}
+	}
+	return e, nil
+}
+
+// Standing is one rule's place on the leaderboard, averaged over runs.
+type Standing struct {
+	Rule                   string
+	Runs                   int
+	Agreement, AgreementSD float64
+	Accuracy, ADHD         float64
+	Wall                   time.Duration
+}
+
+// Standings ranks the rules by mean agreement with the teacher, then mean
+// ADHD score, then mean wall time.
+func Standings(entries []Entry) []Standing {
+	var order []string
+	by := map[string][]Entry{}
+	for _, e := range entries {
+		if _, ok := by[e.Rule]; !ok {
+			order = append(order, e.Rule)
+		}
+		by[e.Rule] = append(by[e.Rule], e)
+	}
+	out := make([]Standing, 0, len(order))
+	for _, name := range order {
+		es := by[name]
+		var agree, acc, adhd []float64
+		var wall time.Duration
+		for _, e := range es {
+			agree = append(agree, e.Agreement)
+			acc = append(acc, e.Result.Accuracy)
+			adhd = append(adhd, e.Result.Score())
+			wall += e.Wall
+		}
+		s := Standing{Rule: name, Runs: len(es), Agreement: stats.Mean(agree), Accuracy: stats.Mean(acc),
+			ADHD: stats.Mean(adhd), Wall: wall / time.Duration(len(es))}
+		if len(es) > 1 {
+			s.AgreementSD = stats.StdDev(agree)
+		}
+		out = append(out, s)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Agreement != b.Agreement {
+			return a.Agreement > b.Agreement
+		}
+		if a.ADHD != b.ADHD && !math.IsNaN(a.ADHD) && !math.IsNaN(b.ADHD) {
+			return a.ADHD > b.ADHD
+		}
+		return a.Wall < b.Wall
+	})
+	return out
+}
+
+// WriteLeaderboard renders the standings as a table.
+func WriteLeaderboard(w io.Writer, title string, standings []Standing) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " #\t Rule\t Runs\t Agreement %\t Accuracy %\t ADHD\t Wall\t")
+	for i, s := range standings {
+		fmt.Fprintf(tw, " %d\t %s\t %d\t %.2f ± %.2f\t %.2f\t %.2f\t %s\t\n",
+			i+1, s.Rule, s.Runs, s.Agreement, s.AgreementSD, s.Accuracy, s.ADHD, s.Wall.Round(time.Millisecond))
+	}
+	return tw.Flush()
+}
+
+// WriteMarkdown renders the standings as a Markdown table, for READMEs.
+func WriteMarkdown(w io.Writer, standings []Standing) error {
+	fmt.Fprintln(w, "| # | Rule | Runs | Agreement % | Accuracy % | ADHD | Wall |")
+	fmt.Fprintln(w, "| -: | - | -: | -: | -: | -: | -: |")
+	for i, s := range standings {
+		_, err := fmt.Fprintf(w, "| %d | `%s` | %d | %.2f ± %.2f | %.2f | %.2f | %s |\n",
+			i+1, s.Rule, s.Runs, s.Agreement, s.AgreementSD, s.Accuracy, s.ADHD, s.Wall.Round(time.Millisecond))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
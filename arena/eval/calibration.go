@@ -0,0 +1,187 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+
+	"paragon"
+)
+
+// DefaultBins is the number of confidence bins Evaluate uses for ECE.
+const DefaultBins = 15
+
+// Bin is one confidence interval of a reliability diagram.
+type Bin struct {
+	Lo, Hi     float64
+	Count      int
+	Confidence float64 // mean top-class probability
+	Accuracy   float64 // fraction of those predictions that were right
+}
+
+// Calibration measures whether a network's output probabilities can be
+// taken at face value: of the predictions made with 80% confidence, 80%
+// should be right.
+type Calibration struct {
+	N     int
+	ECE   float64 // Σ_b |accuracy - confidence| · count/N
+	MCE   float64 // max_b |accuracy - confidence|
+	Brier float64 // mean Σ_k (p_k - y_k)²
+	Bins  []Bin   // equal-width bins over [0,1]
+}
+
+// Calibrate bins the top-class probability of each output. Outputs that
+// are not already distributions are softmaxed first.
+func Calibrate(outputs [][]float64, labels []int, bins int) Calibration {
+	if bins < 1 {
+		bins = DefaultBins
+	}
+	c := Calibration{N: len(outputs), Bins: make([]Bin, bins)}
+	for b := range c.Bins {
+		c.Bins[b].Lo, c.Bins[b].Hi = float64(b)/float64(bins), float64(b+1)/float64(bins)
+	}
+	if len(outputs) == 0 {
+		return c
+	}
+	for i, out := range outputs {
+		if len(out) == 0 {
+			continue
+		}
+		p := Probabilities(out)
+		pred := paragon.ArgMax(p)
+		conf := p[pred]
+		b := min(int(conf*float64(bins)), bins-1)
+		c.Bins[b].Count++
+		c.Bins[b].Confidence += conf
+		if pred == labels[i] {
+			c.Bins[b].Accuracy++
+		}
+		for k, v := range p {
+			y := 0.0
+			if k == labels[i] {
+				y = 1
+			}
+			c.Brier += (v - y) * (v - y)
+		}
+	}
+	n := float64(len(outputs))
+	c.Brier /= n
+	for b := range c.Bins {
+		bin := &c.Bins[b]
+		if bin.Count == 0 {
+			continue
+		}
+		bin.Confidence /= float64(bin.Count)
+		bin.Accuracy /= float64(bin.Count)
+		gap := math.Abs(bin.Accuracy - bin.Confidence)
+		c.ECE += gap * float64(bin.Count) / n
+		c.MCE = math.Max(c.MCE, gap)
+	}
+	return c
+}
+
+// CalibrateNet runs net over inputs and calibrates its outputs against the
+// one-hot targets.
+func CalibrateNet[T paragon.Numeric](net *paragon.Network[T], inputs, targets [][][]float64, bins int) Calibration {
+	return Calibrate(Outputs(net, inputs), Labels(targets), bins)
+}
+
+// Logits recovers logits from an output: the log of a softmax output (up
+// to a constant, which softmax ignores) or the raw output otherwise.
+func Logits(out []float64) []float64 {
+	if !isDistribution(out) {
+		return out
+	}
+	z := make([]float64, len(out))
+	for i, v := range out {
+		z[i] = math.Log(math.Max(v, probFloor))
+	}
+	return z
+}
+
+// Temperature returns softmax(logits/t) for each output. t > 1 softens
+// over-confident outputs, t < 1 sharpens them.
+func Temperature(outputs [][]float64, t float64) [][]float64 {
+	scaled := make([][]float64, len(outputs))
+	for i, out := range outputs {
+		if len(out) == 0 {
+			continue
+		}
+		z := Logits(out)
+		s := make([]float64, len(z))
+		for k, v := range z {
+			s[k] = v / t
+		}
+		scaled[i] = paragon.Softmax(s)
+	}
+	return scaled
+}
+
+// FitTemperature finds the temperature that minimises the log-loss of
+// outputs on labels, searching t in [0.05, 20] on a log scale. Fit it on a
+// validation split and apply it with Temperature to the test split.
+func FitTemperature(outputs [][]float64, labels []int) float64 {
+	logits := make([][]float64, len(outputs))
+	for i, out := range outputs {
+		if len(out) > 0 {
+			logits[i] = Logits(out)
+		}
+	}
+	nll := func(logT float64) float64 {
+		t := math.Exp(logT)
+		var sum float64
+		for i, z := range logits {
+			if labels[i] >= len(z) {
+				continue
+			}
+			// log-softmax of z/t at the label, stabilised by the max
+			m := math.Inf(-1)
+			for _, v := range z {
+				m = math.Max(m, v/t)
+			}
+			var e float64
+			for _, v := range z {
+				e += math.Exp(v/t - m)
+			}
+			sum -= z[labels[i]]/t - m - math.Log(e)
+		}
+		return sum
+	}
+	// Golden-section search; the NLL is unimodal in log t.
+	lo, hi := math.Log(0.05), math.Log(20)
+	g := (math.Sqrt(5) - 1) / 2
+	a, b := hi-g*(hi-lo), lo+g*(hi-lo)
+	fa, fb := nll(a), nll(b)
+	for hi-lo > 1e-4 {
+		if fa < fb {
+			hi, b, fb = b, a, fa
+			a = hi - g*(hi-lo)
+			fa = nll(a)
+		} else {
+			lo, a, fa = a, b, fb
+			b = lo + g*(hi-lo)
+			fb = nll(b)
+		}
+	}
+	return math.Exp((lo + hi) / 2)
+}
+
+// WriteReliability renders the reliability diagram of c as a table with a
+// bar per bin: a calibrated network has Accuracy ≈ Confidence in every bin.
+func WriteReliability(w io.Writer, title string, c Calibration) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	fmt.Fprintf(w, "N=%d  ECE %.4f  MCE %.4f  Brier %.4f\n", c.N, c.ECE, c.MCE, c.Brier)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " Confidence\t Count\t Mean conf\t Accuracy\t Gap\t Diagram\t")
+	for _, b := range c.Bins {
+		if b.Count == 0 {
+			continue
+		}
+		bar := strings.Repeat("#", int(math.Round(b.Accuracy*20)))
+		fmt.Fprintf(tw, " %.2f-%.2f\t %d\t %.3f\t %.3f\t %+.3f\t %s\t\n",
+			b.Lo, b.Hi, b.Count, b.Confidence, b.Accuracy, b.Accuracy-b.Confidence, bar)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,240 @@
+// Package eval scores classifiers in one pass: accuracy, top-k accuracy,
+// log-loss, the confusion matrix, per-class precision/recall/F1, calibration
+// and paragon's ADHD score, so comparisons are not down to a single number.
+//
+//	res := eval.Evaluate(net, testX, testY, 3)
+//	rec.Final("dynamic", params, res.ADHD, res.Metrics())
+//	eval.WriteReport(os.Stdout, "dynamic", res)
+//
+// Targets are one-hot rows, targets[i][0], as everywhere in the arena.
+package eval
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"arena/results"
+	"paragon"
+)
+
+// Result of evaluating a classifier on a labelled set. Accuracies are
+// percentages, like the experiments print them.
+type Result struct {
+	N          int
+	Accuracy   float64
+	TopK       map[int]float64 // k -> top-k accuracy
+	LogLoss    float64         // mean -ln p(true class)
+	Confusion  [][]int         // [true][predicted]
+	Classes    []Class
+	MacroF1    float64
+	WeightedF1 float64
+	ECE        float64 // expected calibration error over DefaultBins bins
+	Brier      float64
+	ADHD       *results.ADHD         // nil when computed from outputs alone
+	Worst      []results.SampleError // the WorstSamples highest-loss samples
+
+	// Labels as float64s, the form paragon's EvaluateModel and
+	// EvaluateFull take.
+	Expected, Predicted []float64
+}
+
+// Class holds one class's metrics.
+type Class struct {
+	Precision, Recall, F1 float64
+	Support               int // samples whose true class this is
+}
+
+// WorstSamples is how many of the highest-loss samples a Result keeps.
+const WorstSamples = 10
+
+// probFloor keeps log-loss finite for confidently wrong predictions.
+const probFloor = 1e-15
+
+// Evaluate runs net over inputs and scores it against the one-hot targets.
+// topK lists the k values to report (top-1 is Accuracy). net's Performance
+// is left holding the ADHD evaluation, as after EvaluateModel.
+func Evaluate[T paragon.Numeric](net *paragon.Network[T], inputs, targets [][][]float64, topK ...int) Result {
+	res := FromOutputs(Outputs(net, inputs), Labels(targets), topK...)
+	net.EvaluateModel(res.Expected, res.Predicted)
+	res.ADHD = results.ADHDOf(net)
+	return res
+}
+
+// Outputs runs net over inputs and returns its output for each.
+func Outputs[T paragon.Numeric](net *paragon.Network[T], inputs [][][]float64) [][]float64 {
+	outputs := make([][]float64, len(inputs))
+	for i, in := range inputs {
+		net.Forward(in)
+		outputs[i] = net.ExtractOutput()
+	}
+	return outputs
+}
+
+// Labels returns the class index of each one-hot target.
+func Labels(targets [][][]float64) []int {
+	labels := make([]int, len(targets))
+	for i, t := range targets {
+		labels[i] = paragon.ArgMax(t[0])
+	}
+	return labels
+}
+
+// FromOutputs scores precomputed network outputs against integer labels.
+// Outputs that are not already a probability distribution are softmaxed
+// for the log-loss. The result has no ADHD score.
+func FromOutputs(outputs [][]float64, labels []int, topK ...int) Result {
+	classes := 0
+	for i, out := range outputs {
+		classes = max(classes, len(out), labels[i]+1)
+	}
+	res := Result{
+		N:         len(outputs),
+		TopK:      map[int]float64{},
+		Confusion: make([][]int, classes),
+		Classes:   make([]Class, classes),
+		Expected:  make([]float64, len(outputs)),
+		Predicted: make([]float64, len(outputs)),
+	}
+	for c := range res.Confusion {
+		res.Confusion[c] = make([]int, classes)
+	}
+	if len(outputs) == 0 {
+		return res
+	}
+
+	correct := 0
+	hits := make([]int, len(topK))
+	samples := make([]results.SampleError, len(outputs))
+	for i, out := range outputs {
+		label := labels[i]
+		pred := 0
+		if len(out) > 0 {
+			pred = paragon.ArgMax(out)
+		}
+		res.Expected[i], res.Predicted[i] = float64(label), float64(pred)
+		res.Confusion[label][pred]++
+		if pred == label {
+			correct++
+		}
+		for j, k := range topK {
+			if rankOf(out, label) < k {
+				hits[j]++
+			}
+		}
+		p, conf := probFloor, 0.0
+		if len(out) > 0 {
+			probs := Probabilities(out)
+			conf = probs[pred]
+			if label < len(out) {
+				p = math.Max(probs[label], probFloor)
+			}
+		}
+		res.LogLoss -= math.Log(p)
+		samples[i] = results.SampleError{Index: i, Label: label, Predicted: pred, Confidence: conf, Loss: -math.Log(p)}
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Loss > samples[j].Loss })
+	res.Worst = samples[:min(WorstSamples, len(samples))]
+	n := float64(len(outputs))
+	res.Accuracy = float64(correct) / n * 100
+	res.LogLoss /= n
+	for j, k := range topK {
+		res.TopK[k] = float64(hits[j]) / n * 100
+	}
+
+	cal := Calibrate(outputs, labels, DefaultBins)
+	res.ECE, res.Brier = cal.ECE, cal.Brier
+
+	for c := range res.Classes {
+		tp, predicted, actual := res.Confusion[c][c], 0, 0
+		for o := 0; o < classes; o++ {
+			predicted += res.Confusion[o][c]
+			actual += res.Confusion[c][o]
+		}
+		cl := Class{Precision: ratio(tp, predicted), Recall: ratio(tp, actual), Support: actual}
+		if cl.Precision+cl.Recall > 0 {
+			cl.F1 = 2 * cl.Precision * cl.Recall / (cl.Precision + cl.Recall)
+		}
+		res.Classes[c] = cl
+		res.MacroF1 += cl.F1 / float64(classes)
+		res.WeightedF1 += cl.F1 * float64(actual) / n
+	}
+	return res
+}
+
+// Metrics flattens the result for a results.Record: accuracy, top<k>,
+// log_loss, macro_f1, weighted_f1, ece and brier.
+func (r Result) Metrics() map[string]float64 {
+	m := map[string]float64{
+		"accuracy":    r.Accuracy,
+		"log_loss":    r.LogLoss,
+		"macro_f1":    r.MacroF1,
+		"weighted_f1": r.WeightedF1,
+		"ece":         r.ECE,
+		"brier":       r.Brier,
+	}
+	for k, v := range r.TopK {
+		m[fmt.Sprintf("top%d", k)] = v
+	}
+	return m
+}
+
+// Diagnostics keeps the per-class scores, confusion matrix and worst
+// samples for a results.Record.
+func (r Result) Diagnostics() *results.Diagnostics {
+	d := &results.Diagnostics{Confusion: r.Confusion, Worst: r.Worst}
+	for _, c := range r.Classes {
+		d.Classes = append(d.Classes, results.ClassMetrics{Precision: c.Precision, Recall: c.Recall, F1: c.F1, Support: c.Support})
+	}
+	return d
+}
+
+// Score is the ADHD score, or NaN without one.
+func (r Result) Score() float64 {
+	if r.ADHD == nil {
+		return math.NaN()
+	}
+	return r.ADHD.Score
+}
+
+// Probabilities returns out unchanged when it is already a distribution
+// (a softmax output layer) and its softmax otherwise.
+func Probabilities(out []float64) []float64 {
+	if isDistribution(out) {
+		return out
+	}
+	return paragon.Softmax(out)
+}
+
+// isDistribution reports whether out is non-negative and sums to 1.
+func isDistribution(out []float64) bool {
+	sum := 0.0
+	for _, v := range out {
+		if v < 0 || v > 1 || math.IsNaN(v) {
+			return false
+		}
+		sum += v
+	}
+	return len(out) > 0 && math.Abs(sum-1) <= 1e-3
+}
+
+// rankOf is how many outputs score strictly above out[label].
+func rankOf(out []float64, label int) int {
+	if label >= len(out) {
+		return len(out)
+	}
+	r := 0
+	for _, v := range out {
+		if v > out[label] {
+			r++
+		}
+	}
+	return r
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
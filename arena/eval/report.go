@@ -0,0 +1,115 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"arena/results"
+)
+
+// WriteReport renders one result: the headline metrics, per-class
+// precision/recall/F1, the confusion matrix and the worst samples.
+func WriteReport(w io.Writer, title string, r Result) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	fmt.Fprintf(w, "N=%d  accuracy %.2f%%%s  log-loss %.4f  macro-F1 %.4f  weighted-F1 %.4f  ECE %.4f  Brier %.4f  ADHD %s\n",
+		r.N, r.Accuracy, topK(r), r.LogLoss, r.MacroF1, r.WeightedF1, r.ECE, r.Brier, num(r.Score()))
+
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " Class\t Precision\t Recall\t F1\t Support\t")
+	for c, cl := range r.Classes {
+		fmt.Fprintf(tw, " %d\t %.4f\t %.4f\t %.4f\t %d\t\n", c, cl.Precision, cl.Recall, cl.F1, cl.Support)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "Confusion matrix (rows = true, columns = predicted):")
+	tw = tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	header := []string{""}
+	for c := range r.Confusion {
+		header = append(header, fmt.Sprint(c))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+	for c, row := range r.Confusion {
+		cells := []string{fmt.Sprint(c)}
+		for _, v := range row {
+			cells = append(cells, fmt.Sprint(v))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t")+"\t")
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	return WriteWorst(w, r.Worst)
+}
+
+// WriteWorst lists the highest-loss samples of a result.
+func WriteWorst(w io.Writer, worst []results.SampleError) error {
+	if len(worst) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w, "Worst samples:")
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " Index\t Label\t Predicted\t Confidence\t Loss\t")
+	for _, s := range worst {
+		fmt.Fprintf(tw, " %d\t %d\t %d\t %.4f\t %.4f\t\n", s.Index, s.Label, s.Predicted, s.Confidence, s.Loss)
+	}
+	return tw.Flush()
+}
+
+// WriteTable renders several results side by side, one row per name.
+func WriteTable(w io.Writer, title string, names []string, rs []Result) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	var ks []int
+	for _, r := range rs {
+		for k := range r.TopK {
+			if !slices.Contains(ks, k) {
+				ks = append(ks, k)
+			}
+		}
+	}
+	sort.Ints(ks)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	header := " Model\t N\t Acc%"
+	for _, k := range ks {
+		header += fmt.Sprintf("\t Top-%d%%", k)
+	}
+	fmt.Fprintln(tw, header+"\t LogLoss\t MacroF1\t ECE\t Brier\t ADHD\t")
+	for i, r := range rs {
+		row := fmt.Sprintf(" %s\t %d\t %.2f", names[i], r.N, r.Accuracy)
+		for _, k := range ks {
+			if v, ok := r.TopK[k]; ok {
+				row += fmt.Sprintf("\t %.2f", v)
+			} else {
+				row += "\t -"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t %.4f\t %.4f\t %.4f\t %.4f\t %s\t\n", row, r.LogLoss, r.MacroF1, r.ECE, r.Brier, num(r.Score()))
+	}
+	return tw.Flush()
+}
+
+func topK(r Result) string {
+	ks := make([]int, 0, len(r.TopK))
+	for k := range r.TopK {
+		ks = append(ks, k)
+	}
+	sort.Ints(ks)
+	s := ""
+	for _, k := range ks {
+		s += fmt.Sprintf("  top-%d %.2f%%", k, r.TopK[k])
+	}
+	return s
+}
+
+func num(x float64) string {
+	if math.IsNaN(x) {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", x)
+}
@@ -0,0 +1,248 @@
+// Package experiment lets an experiment binary expose its studies as named
+// steps that can be listed and run one at a time instead of commenting out
+// calls in main.
+//
+// An experiment registers its steps and hands control to Main:
+//
+//	func main() {
+//		experiment.Before(setup)
+//		experiment.Register("singleCompare", "baseline vs replay, one run each", experiment.Func(singleCompare))
+//		experiment.Optional("fullSweep", "every setting, several hours", experiment.Func(fullSweep))
+//		experiment.Main("replay6")
+//	}
+//
+// The binary then understands:
+//
+//	go run .                      run every registered (non-optional) step
+//	go run . list                 print the steps
+//	go run . run <step> [-seed N] run one step
+//
+// The arena command wraps this so any step can be started from the repo
+// root as `arena run replay6/singleCompare -seed 7`. Steps draw their
+// randomness from ctx.Seeds; the master seed and every stream derived from
+// it are appended to seeds.jsonl when the run ends, under ctx.RunID, which
+// ctx.NewRun stamps on the run's results too.
+package experiment
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"arena/results"
+	"arena/rng"
+)
+
+// Context is passed to every step.
+type Context struct {
+	Experiment string
+	Step       string
+	Seed       int64      // from -seed, $ARENA_SEED, or the start time
+	Seeds      *rng.Seeds // streams derived from Seed; saved to seeds.jsonl
+	RunID      string     // shared by the seed manifest and ctx.NewRun
+	Args       []string   // arguments after the step name
+}
+
+// NewRun is results.NewRun for this run: its records carry ctx.RunID, so
+// reports find the seed manifest by ID.
+func (ctx *Context) NewRun(config any) results.Run {
+	run := results.NewRun(ctx.Experiment, ctx.Seed, config)
+	run.ID = ctx.RunID
+	return run
+}
+
+// OpenResults opens the results sink at data with a recorder for
+// ctx.NewRun(nil), and log for appending human-readable tables under a
+// header naming the run's time, ID and seed.
+func (ctx *Context) OpenResults(data, log string) (*results.Recorder, *os.File, error) {
+	sink, err := results.Open(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	rec := results.NewRecorder(sink, ctx.NewRun(nil))
+	f, err := os.OpenFile(log, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		rec.Close()
+		return nil, nil, fmt.Errorf("experiment: %w", err)
+	}
+	fmt.Fprintf(f, "\n=== Test Run: %s (run %s, seed %d) ===\n",
+		time.Now().Format("2006-01-02 15:04:05"), ctx.RunID, ctx.Seed)
+	return rec, f, nil
+}
+
+// StepFunc runs one step.
+type StepFunc func(ctx *Context) error
+
+// Step is one runnable study inside an experiment.
+type Step struct {
+	Name     string
+	Doc      string
+	Optional bool // skipped when the experiment runs without a step name
+	Run      StepFunc
+}
+
+var (
+	steps  []Step
+	before []StepFunc
+)
+
+// Register adds a step that also runs when no step is named.
+func Register(name, doc string, run StepFunc) {
+	add(Step{Name: name, Doc: doc, Run: run})
+}
+
+// Optional adds a step that only runs when asked for by name, for studies
+// that used to sit commented out in main.
+func Optional(name, doc string, run StepFunc) {
+	add(Step{Name: name, Doc: doc, Optional: true, Run: run})
+}
+
+func add(s Step) {
+	if _, ok := Lookup(s.Name); ok {
+		panic("experiment: step registered twice: " + s.Name)
+	}
+	steps = append(steps, s)
+}
+
+// Before registers setup that runs once before the first step, after the
+// seed is known. Hooks run in registration order.
+func Before(fn StepFunc) {
+	before = append(before, fn)
+}
+
+// Func adapts a step written as a plain func().
+func Func(fn func()) StepFunc {
+	return func(*Context) error {
+		fn()
+		return nil
+	}
+}
+
+// Steps returns the registered steps in registration order.
+func Steps() []Step {
+	return append([]Step(nil), steps...)
+}
+
+// Lookup finds a step by name.
+func Lookup(name string) (Step, bool) {
+	for _, s := range steps {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Step{}, false
+}
+
+// Main parses os.Args, runs the requested steps and exits non-zero on error.
+func Main(experiment string) {
+	if err := Run(experiment, os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", experiment, err)
+		os.Exit(1)
+	}
+}
+
+// Run is Main without the exit, writing listings to w.
+func Run(experiment string, args []string, w io.Writer) error {
+	cmd := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	switch cmd {
+	case "list":
+		List(w)
+		return nil
+	case "run":
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+			return errors.New("usage: run <step> [-seed N] [args...]")
+		}
+		name := args[0]
+		s, ok := Lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown step %q (try list)", name)
+		}
+		ctx, err := newContext(experiment, name, args[1:])
+		if err != nil {
+			return err
+		}
+		defer saveSeeds(ctx)
+		if err := setup(ctx); err != nil {
+			return err
+		}
+		return s.Run(ctx)
+	case "":
+		ctx, err := newContext(experiment, "", args)
+		if err != nil {
+			return err
+		}
+		defer saveSeeds(ctx)
+		if err := setup(ctx); err != nil {
+			return err
+		}
+		for _, s := range steps {
+			if s.Optional {
+				continue
+			}
+			ctx.Step = s.Name
+			if err := s.Run(ctx); err != nil {
+				return fmt.Errorf("%s: %w", s.Name, err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q (want list or run)", cmd)
+	}
+}
+
+// List writes one line per step; optional steps are marked.
+func List(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, s := range steps {
+		mark := ""
+		if s.Optional {
+			mark = "(optional)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Name, mark, s.Doc)
+	}
+	tw.Flush()
+}
+
+func newContext(experiment, step string, args []string) (*Context, error) {
+	fs := flag.NewFlagSet(experiment, flag.ContinueOnError)
+	seed := fs.Int64("seed", 0, "master random seed (default: $ARENA_SEED, else current time)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	ctx := &Context{Experiment: experiment, Step: step, Seed: *seed, RunID: results.NewRunID(), Args: fs.Args()}
+	seeded := false
+	fs.Visit(func(f *flag.Flag) { seeded = seeded || f.Name == "seed" })
+	if !seeded {
+		ctx.Seed = rng.EnvSeed()
+	}
+	ctx.Seeds = rng.New(ctx.Seed)
+	fmt.Printf("🎲 %s seed %d\n", experiment, ctx.Seed)
+	return ctx, nil
+}
+
+// saveSeeds appends the run's seed manifest, even when a step failed.
+func saveSeeds(ctx *Context) {
+	m := ctx.Seeds.Manifest(ctx.Experiment, ctx.Step)
+	m.RunID = ctx.RunID
+	if err := rng.AppendManifest(rng.ManifestFile, m); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: saving seeds: %v\n", ctx.Experiment, err)
+	}
+}
+
+func setup(ctx *Context) error {
+	for _, fn := range before {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
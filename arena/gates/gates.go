@@ -0,0 +1,314 @@
+// Package gates is the shared library of dynamic replay gates. A gate is a
+// paragon ReplayGateFunc bound to one layer: it scores the layer's latest
+// pass in [0,1], higher meaning "replay more". A policy is a
+// ReplayGateToReps that turns the score into a replay count.
+//
+//	layer := &net.Layers[1]
+//	gate, err := gates.New("entropy", layer)
+//	...
+//	layer.ReplayEnabled = true
+//	layer.ReplayBudget = 3
+//	layer.ReplayGateFunc = gate
+//	layer.ReplayGateToReps = gates.Threshold(0.5, 3)
+//
+// or in one call, gates.Apply(layer, "entropy", gates.Threshold(0.5, 3), 3).
+// Experiments pick gates by name so sweeps and config files can name them.
+// Loss and Learned need state from the training loop and are built
+// directly instead.
+package gates
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"paragon"
+)
+
+// Neutral is what history-based gates return until they have enough
+// history, and what replay6's gates returned for empty layers.
+const Neutral = 0.5
+
+// MinHistory is the number of cached passes Temporal, Hybrid and Drift wait
+// for before scoring.
+const MinHistory = 5
+
+// varianceScale maps output variances onto [0,1]; replay6 tuned it for
+// leaky_relu layers of MNIST models.
+const varianceScale = 0.1
+
+var constructors = map[string]string{
+	"entropy":       "normalised Shannon entropy of the softmaxed outputs",
+	"input-entropy": "replay5Dyn's entropy of the softmaxed gate input",
+	"raw-entropy":   "replay6's entropy of the raw outputs, divided by width",
+	"margin":        "1 - (top1 - top2) of the softmaxed outputs",
+	"variance":      "variance across the outputs / 0.1",
+	"gradient":      "mean squared difference of neighbouring outputs / 0.1",
+	"temporal":      "mean per-neuron variance over CachedOutputsHistory / 0.1",
+	"drift":         "distance of the outputs from their history mean, relative",
+	"hybrid":        "0.6 temporal + 0.4 variance",
+}
+
+// Names returns the gate names New accepts, sorted.
+func Names() []string {
+	names := make([]string, 0, len(constructors))
+	for n := range constructors {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Doc returns a one-line description of a gate.
+func Doc(name string) string { return constructors[name] }
+
+// Known reports whether New accepts name.
+func Known(name string) bool {
+	_, ok := constructors[name]
+	return ok
+}
+
+// New returns the named gate bound to layer.
+func New[T paragon.Numeric](name string, layer *paragon.Grid[T]) (func([][]T) float64, error) {
+	switch name {
+	case "entropy":
+		return Entropy(layer), nil
+	case "input-entropy":
+		return InputEntropy(layer), nil
+	case "raw-entropy":
+		return RawEntropy(layer), nil
+	case "margin":
+		return Margin(layer), nil
+	case "variance":
+		return Variance(layer), nil
+	case "gradient":
+		return Gradient(layer), nil
+	case "temporal":
+		return Temporal(layer), nil
+	case "drift":
+		return Drift(layer), nil
+	case "hybrid":
+		return Hybrid(layer), nil
+	}
+	return nil, fmt.Errorf("unknown gate %q (have %v)", name, Names())
+}
+
+// Apply enables dynamic replay on layer with the named gate and policy.
+func Apply[T paragon.Numeric](layer *paragon.Grid[T], gate string, policy func(float64) int, budget int) error {
+	g, err := New(gate, layer)
+	if err != nil {
+		return err
+	}
+	layer.ReplayEnabled = true
+	layer.ReplayBudget = budget
+	layer.ReplayGateFunc = g
+	layer.ReplayGateToReps = policy
+	return nil
+}
+
+// Entropy scores the Shannon entropy of the softmaxed outputs, normalised
+// by its maximum: 1 for a flat (undecided) layer, 0 for a one-hot one. It
+// is the gate nlpReplay* and replayEyeState each carried a copy of. Empty
+// layers score 0.
+func Entropy[T paragon.Numeric](layer *paragon.Grid[T]) func([][]T) float64 {
+	return func([][]T) float64 {
+		return entropy(layer.CachedOutputs)
+	}
+}
+
+// InputEntropy is Entropy over the values the gate is called with rather
+// than the layer's outputs. It is replay5Dyn's gate, kept so its numbers
+// stay comparable; the layer is not read.
+func InputEntropy[T paragon.Numeric](layer *paragon.Grid[T]) func([][]T) float64 {
+	return func(input [][]T) float64 {
+		var vals []T
+		for _, row := range input {
+			vals = append(vals, row...)
+		}
+		return entropy(vals)
+	}
+}
+
+// entropy is the normalised entropy of softmax(out), 0 for fewer than two
+// values.
+func entropy[T paragon.Numeric](out []T) float64 {
+	if len(out) < 2 {
+		return 0
+	}
+	p := softmax(out)
+	var h float64
+	for _, v := range p {
+		if v > 0 {
+			h -= v * math.Log2(v)
+		}
+	}
+	return clamp01(h / math.Log2(float64(len(p))))
+}
+
+// RawEntropy is replay6's entropy gate: -Σ v·ln v over the raw outputs,
+// divided by the width and by ln(width). It is kept so replay6's numbers
+// stay comparable; prefer Entropy.
+func RawEntropy[T paragon.Numeric](layer *paragon.Grid[T]) func([][]T) float64 {
+	return func([][]T) float64 {
+		out := layer.CachedOutputs
+		if len(out) == 0 {
+			return Neutral
+		}
+		maxEntropy := math.Log(float64(len(out)))
+		if maxEntropy == 0 {
+			return Neutral
+		}
+		var sum float64
+		for _, v := range out {
+			if v64 := float64(v); v64 > 1e-10 {
+				sum += v64 * math.Log(v64)
+			}
+		}
+		return clamp01(-sum / float64(len(out)) / maxEntropy)
+	}
+}
+
+// Margin scores 1 minus the gap between the two largest softmaxed outputs:
+// close to 1 when the layer cannot decide between its top two units.
+// Layers narrower than two score 0.
+func Margin[T paragon.Numeric](layer *paragon.Grid[T]) func([][]T) float64 {
+	return func([][]T) float64 {
+		out := layer.CachedOutputs
+		if len(out) < 2 {
+			return 0
+		}
+		first, second := math.Inf(-1), math.Inf(-1)
+		for _, v := range softmax(out) {
+			switch {
+			case v > first:
+				first, second = v, first
+			case v > second:
+				second = v
+			}
+		}
+		return clamp01(1 - (first - second))
+	}
+}
+
+// Variance scores the spread of the outputs across the layer.
+func Variance[T paragon.Numeric](layer *paragon.Grid[T]) func([][]T) float64 {
+	return func([][]T) float64 {
+		if len(layer.CachedOutputs) == 0 {
+			return Neutral
+		}
+		return clamp01(variance(layer.CachedOutputs) / varianceScale)
+	}
+}
+
+// Gradient scores how sharply neighbouring outputs differ.
+func Gradient[T paragon.Numeric](layer *paragon.Grid[T]) func([][]T) float64 {
+	return func([][]T) float64 {
+		out := layer.CachedOutputs
+		if len(out) < 2 {
+			return Neutral
+		}
+		var sum float64
+		for i := 1; i < len(out); i++ {
+			d := float64(out[i] - out[i-1])
+			sum += d * d
+		}
+		return clamp01(sum / float64(len(out)-1) / varianceScale)
+	}
+}
+
+// Temporal scores how much each unit's output varies over the cached
+// history (the "adaptive temporal replay" gate of replay6).
+func Temporal[T paragon.Numeric](layer *paragon.Grid[T]) func([][]T) float64 {
+	return func([][]T) float64 {
+		return clamp01(temporal(layer.CachedOutputsHistory))
+	}
+}
+
+// Drift scores how far the current outputs have moved from the mean of the
+// cached history, relative to that mean's magnitude: high when the layer
+// sees something unlike what it saw recently.
+func Drift[T paragon.Numeric](layer *paragon.Grid[T]) func([][]T) float64 {
+	return func([][]T) float64 {
+		hist, cur := layer.CachedOutputsHistory, layer.CachedOutputs
+		if len(hist) < MinHistory || len(cur) == 0 {
+			return Neutral
+		}
+		var dist, norm float64
+		for i, v := range cur {
+			var mean float64
+			var n int
+			for _, h := range hist {
+				if i < len(h) {
+					mean += float64(h[i])
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			mean /= float64(n)
+			d := float64(v) - mean
+			dist += d * d
+			norm += mean * mean
+		}
+		return clamp01(math.Sqrt(dist) / (math.Sqrt(norm) + 1e-8))
+	}
+}
+
+// Hybrid mixes Temporal (0.6) with Variance (0.4).
+func Hybrid[T paragon.Numeric](layer *paragon.Grid[T]) func([][]T) float64 {
+	return func([][]T) float64 {
+		if len(layer.CachedOutputsHistory) < MinHistory || len(layer.CachedOutputs) == 0 {
+			return Neutral
+		}
+		t := temporal(layer.CachedOutputsHistory)
+		s := clamp01(variance(layer.CachedOutputs) / varianceScale)
+		return clamp01(0.6*t + 0.4*s)
+	}
+}
+
+// temporal is the mean per-unit variance over hist, scaled; Neutral until
+// MinHistory passes are cached.
+func temporal[T paragon.Numeric](hist [][]T) float64 {
+	if len(hist) < MinHistory || len(hist[0]) == 0 {
+		return Neutral
+	}
+	n := float64(len(hist))
+	var avg float64
+	for i := range hist[0] {
+		var mean, sumSq float64
+		for _, h := range hist {
+			v := float64(h[i])
+			mean += v / n
+			sumSq += v * v
+		}
+		avg += (sumSq/n - mean*mean) / varianceScale
+	}
+	return avg / float64(len(hist[0]))
+}
+
+func variance[T paragon.Numeric](out []T) float64 {
+	n := float64(len(out))
+	var mean, sumSq float64
+	for _, v := range out {
+		v64 := float64(v)
+		mean += v64 / n
+		sumSq += v64 * v64
+	}
+	return sumSq/n - mean*mean
+}
+
+func softmax[T paragon.Numeric](out []T) []float64 {
+	vals := make([]float64, len(out))
+	for i, v := range out {
+		vals[i] = float64(v)
+	}
+	return paragon.Softmax(vals)
+}
+
+func clamp01(x float64) float64 {
+	if math.IsNaN(x) {
+		return 0
+	}
+	return math.Min(1, math.Max(0, x))
+}
@@ -0,0 +1,218 @@
+package gates
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"paragon"
+)
+
+func layerWith(out []float32, hist ...[]float32) *paragon.Grid[float32] {
+	return &paragon.Grid[float32]{CachedOutputs: out, CachedOutputsHistory: hist}
+}
+
+func repeat(out []float32, n int) [][]float32 {
+	h := make([][]float32, n)
+	for i := range h {
+		h[i] = append([]float32(nil), out...)
+	}
+	return h
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
+
+func TestScores(t *testing.T) {
+	flat := []float32{1, 1, 1, 1}
+	peaked := []float32{50, 0, 0, 0}
+	tied := []float32{20, 20, 0, 0}
+
+	tests := []struct {
+		name string
+		gate func([][]float32) float64
+		want float64
+		tol  float64
+	}{
+		{"entropy flat", Entropy(layerWith(flat)), 1, 1e-9},
+		{"entropy peaked", Entropy(layerWith(peaked)), 0, 1e-6},
+		{"entropy empty", Entropy(layerWith(nil)), 0, 0},
+		{"entropy single unit", Entropy(layerWith([]float32{3})), 0, 0},
+
+		{"margin tied", Margin(layerWith(tied)), 1, 1e-6},
+		{"margin peaked", Margin(layerWith(peaked)), 0, 1e-6},
+		{"margin flat", Margin(layerWith(flat)), 1, 1e-9},
+		{"margin single unit", Margin(layerWith([]float32{3})), 0, 0},
+
+		// -Σ v ln v over {0.5, 0.5} is ln 2; / width 2 / ln 2 = 0.5
+		{"raw entropy", RawEntropy(layerWith([]float32{0.5, 0.5})), 0.5, 1e-6},
+		{"raw entropy empty", RawEntropy(layerWith(nil)), Neutral, 0},
+
+		{"variance constant", Variance(layerWith(flat)), 0, 1e-9},
+		// variance of {0, 0.2} is 0.01; / 0.1 = 0.1
+		{"variance", Variance(layerWith([]float32{0, 0.2})), 0.1, 1e-6},
+		{"variance clamps", Variance(layerWith([]float32{0, 10})), 1, 0},
+		{"variance empty", Variance(layerWith(nil)), Neutral, 0},
+
+		{"gradient constant", Gradient(layerWith(flat)), 0, 1e-9},
+		// (0.2-0)^2 / 1 / 0.1 = 0.4
+		{"gradient", Gradient(layerWith([]float32{0, 0.2})), 0.4, 1e-6},
+		{"gradient single unit", Gradient(layerWith([]float32{1})), Neutral, 0},
+
+		{"temporal short history", Temporal(layerWith(flat, repeat(flat, MinHistory-1)...)), Neutral, 0},
+		{"temporal steady", Temporal(layerWith(flat, repeat(flat, MinHistory)...)), 0, 1e-9},
+		{"temporal alternating", Temporal(layerWith(flat,
+			[]float32{0}, []float32{0.2}, []float32{0}, []float32{0.2}, []float32{0}, []float32{0.2})), 0.1, 1e-6},
+
+		{"drift short history", Drift(layerWith(flat, repeat(flat, 2)...)), Neutral, 0},
+		{"drift steady", Drift(layerWith(flat, repeat(flat, MinHistory)...)), 0, 1e-9},
+		{"drift jump", Drift(layerWith([]float32{5, 5, 5, 5}, repeat(flat, MinHistory)...)), 1, 0},
+		// |1.5-1| / |1| per unit → 0.5
+		{"drift half", Drift(layerWith([]float32{1.5, 1.5, 1.5, 1.5}, repeat(flat, MinHistory)...)), 0.5, 1e-6},
+
+		{"hybrid short history", Hybrid(layerWith(flat)), Neutral, 0},
+		{"hybrid steady", Hybrid(layerWith(flat, repeat(flat, MinHistory)...)), 0, 1e-9},
+	}
+	for _, tt := range tests {
+		got := tt.gate(nil)
+		if math.Abs(got-tt.want) > tt.tol {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+		if got < 0 || got > 1 {
+			t.Errorf("%s = %v, outside [0,1]", tt.name, got)
+		}
+	}
+}
+
+// InputEntropy scores what it is passed and ignores the layer.
+func TestInputEntropy(t *testing.T) {
+	gate := InputEntropy(layerWith([]float32{50, 0, 0, 0}))
+	if got := gate([][]float32{{1, 1}, {1, 1}}); !near(got, 1) {
+		t.Fatalf("flat input = %v, want 1", got)
+	}
+	if got := gate([][]float32{{50, 0}, {0, 0}}); got > 1e-6 {
+		t.Fatalf("peaked input = %v, want 0", got)
+	}
+	if got := gate(nil); got != 0 {
+		t.Fatalf("no input = %v, want 0", got)
+	}
+}
+
+// Gates read the layer when called, not when built.
+func TestGatesReadLiveOutputs(t *testing.T) {
+	layer := layerWith([]float32{50, 0, 0, 0})
+	gate := Entropy(layer)
+	if got := gate(nil); got > 1e-6 {
+		t.Fatalf("peaked entropy = %v", got)
+	}
+	layer.CachedOutputs = []float32{1, 1, 1, 1}
+	if got := gate(nil); !near(got, 1) {
+		t.Fatalf("entropy after update = %v, want 1", got)
+	}
+}
+
+func TestEntropyOrdersUncertainty(t *testing.T) {
+	sure := Entropy(layerWith([]float32{4, 0, 0}))(nil)
+	unsure := Entropy(layerWith([]float32{1, 0.5, 0}))(nil)
+	if !(sure < unsure) {
+		t.Fatalf("entropy(sure)=%v should be below entropy(unsure)=%v", sure, unsure)
+	}
+	sure = Margin(layerWith([]float32{4, 0, 0}))(nil)
+	unsure = Margin(layerWith([]float32{1, 0.9, 0}))(nil)
+	if !(sure < unsure) {
+		t.Fatalf("margin(sure)=%v should be below margin(unsure)=%v", sure, unsure)
+	}
+}
+
+func TestNew(t *testing.T) {
+	layer := layerWith([]float32{1, 2, 3}, repeat([]float32{1, 2, 3}, MinHistory)...)
+	for _, name := range Names() {
+		g, err := New(name, layer)
+		if err != nil {
+			t.Fatalf("New(%q): %v", name, err)
+		}
+		if s := g(nil); s < 0 || s > 1 {
+			t.Errorf("%s scored %v", name, s)
+		}
+		if !Known(name) || Doc(name) == "" {
+			t.Errorf("%s: missing from Known/Doc", name)
+		}
+	}
+	if _, err := New("confidence", layer); err == nil || !strings.Contains(err.Error(), "entropy") {
+		t.Fatalf("New(unknown) error = %v, want one listing the gates", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	layer := layerWith([]float32{1, 1})
+	if err := Apply(layer, "entropy", Threshold(0.5, 3), 3); err != nil {
+		t.Fatal(err)
+	}
+	if !layer.ReplayEnabled || layer.ReplayBudget != 3 {
+		t.Fatalf("replay not enabled: %+v", layer)
+	}
+	if reps := layer.ReplayGateToReps(layer.ReplayGateFunc(nil)); reps != 3 {
+		t.Fatalf("flat layer got %d replays, want 3", reps)
+	}
+	if err := Apply(layerWith(nil), "nope", Linear(2), 2); err == nil {
+		t.Fatal("Apply with unknown gate succeeded")
+	}
+}
+
+func TestLoss(t *testing.T) {
+	layer := layerWith(nil)
+	tr := NewLossTracker(0.9)
+	gate := Loss(layer, tr)
+	if got := gate(nil); got != Neutral {
+		t.Fatalf("unobserved loss gate = %v, want Neutral", got)
+	}
+	tr.Observe(1)
+	if got := gate(nil); !near(got, 0.5) {
+		t.Fatalf("typical loss = %v, want 0.5", got)
+	}
+	tr.Observe(10) // avg 0.9·1 + 0.1·10 = 1.9
+	if got := gate(nil); !near(got, 10/11.9) {
+		t.Fatalf("hard sample = %v, want %v", got, 10/11.9)
+	}
+	tr.Observe(math.NaN())
+	if got := gate(nil); !near(got, 10/11.9) {
+		t.Fatalf("NaN loss changed the score to %v", got)
+	}
+}
+
+func TestLearned(t *testing.T) {
+	layer := layerWith([]float32{1, 1, 1, 1})
+	if _, err := NewLearned(layer, 0.5); err == nil {
+		t.Fatal("NewLearned with no inputs succeeded")
+	}
+	if _, err := NewLearned(layer, 0.5, "nope"); err == nil {
+		t.Fatal("NewLearned with unknown input succeeded")
+	}
+	l, err := NewLearned(layer, 1, "entropy", "margin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Update(1) // before any Gate call
+	gate := l.Gate()
+	if got := gate(nil); got != Neutral {
+		t.Fatalf("untrained learned gate = %v, want Neutral", got)
+	}
+	// Replays on flat (uncertain) outputs pay off, on peaked ones they do not.
+	for i := 0; i < 200; i++ {
+		layer.CachedOutputs = []float32{1, 1, 1, 1}
+		gate(nil)
+		l.Update(1)
+		layer.CachedOutputs = []float32{50, 0, 0, 0}
+		gate(nil)
+		l.Update(0)
+	}
+	layer.CachedOutputs = []float32{1, 1, 1, 1}
+	flat := gate(nil)
+	layer.CachedOutputs = []float32{50, 0, 0, 0}
+	peaked := gate(nil)
+	if !(flat > 0.9 && peaked < 0.1) {
+		t.Fatalf("learned gate scored flat %v, peaked %v", flat, peaked)
+	}
+	if w, _ := l.Weights(); w["entropy"] <= 0 {
+		t.Fatalf("entropy weight = %v, want positive", w["entropy"])
+	}
+}
@@ -0,0 +1,133 @@
+package gates
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"paragon"
+)
+
+// LossTracker carries the training loss to a Loss gate, which cannot see
+// the targets itself. The training loop calls Observe after each sample.
+// It is safe for concurrent use.
+type LossTracker struct {
+	mu        sync.Mutex
+	decay     float64
+	last, avg float64
+	seen      bool
+}
+
+// NewLossTracker averages losses with an exponential moving average of the
+// given decay (e.g. 0.99).
+func NewLossTracker(decay float64) *LossTracker {
+	return &LossTracker{decay: decay}
+}
+
+// Observe records the loss of the latest sample.
+func (t *LossTracker) Observe(loss float64) {
+	if math.IsNaN(loss) || math.IsInf(loss, 0) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.seen {
+		t.avg, t.seen = loss, true
+	}
+	t.last = loss
+	t.avg = t.decay*t.avg + (1-t.decay)*loss
+}
+
+// score is last/(last+avg): Neutral for a typical sample, towards 1 for
+// one much harder than average.
+func (t *LossTracker) score() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.seen || t.last+t.avg <= 0 {
+		return Neutral
+	}
+	return clamp01(t.last / (t.last + t.avg))
+}
+
+// Loss scores how the latest observed loss compares with the running
+// average, so samples the network currently gets wrong are replayed more.
+// The layer argument only fixes T.
+func Loss[T paragon.Numeric](_ *paragon.Grid[T], t *LossTracker) func([][]T) float64 {
+	return func([][]T) float64 { return t.score() }
+}
+
+// Learned is a gate that learns how to weigh other gates: it scores
+// sigmoid(w·x + b) over the named gates' scores x and is trained online by
+// Update, e.g. with 1 when a replay lowered the loss and 0 when it did not.
+// It is safe for concurrent use.
+type Learned[T paragon.Numeric] struct {
+	mu       sync.Mutex
+	names    []string
+	features []func([][]T) float64
+	w        []float64
+	b, lr    float64
+	x        []float64 // features of the latest Gate call
+	p        float64   // and its score
+}
+
+// NewLearned builds a learned gate over the named gates of layer, with all
+// weights zero (so it starts at Neutral) and learning rate lr.
+func NewLearned[T paragon.Numeric](layer *paragon.Grid[T], lr float64, names ...string) (*Learned[T], error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("learned gate needs at least one input gate")
+	}
+	l := &Learned[T]{names: names, w: make([]float64, len(names)), lr: lr}
+	for _, n := range names {
+		g, err := New(n, layer)
+		if err != nil {
+			return nil, err
+		}
+		l.features = append(l.features, g)
+	}
+	return l, nil
+}
+
+// Gate returns the ReplayGateFunc.
+func (l *Learned[T]) Gate() func([][]T) float64 {
+	return func(input [][]T) float64 {
+		x := make([]float64, len(l.features))
+		for i, f := range l.features {
+			x[i] = f(input)
+		}
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		z := l.b
+		for i, v := range x {
+			z += l.w[i] * v
+		}
+		l.x, l.p = x, 1/(1+math.Exp(-z))
+		return l.p
+	}
+}
+
+// Update takes one logistic-regression step towards target in [0,1] for
+// the latest scored pass. It is a no-op before the first Gate call.
+func (l *Learned[T]) Update(target float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.x == nil {
+		return
+	}
+	g := l.lr * (clamp01(target) - l.p)
+	for i, v := range l.x {
+		l.w[i] += g * v
+	}
+	l.b += g
+}
+
+// Weights returns the current weight of each input gate, by name, and the
+// bias.
+func (l *Learned[T]) Weights() (map[string]float64, float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w := make(map[string]float64, len(l.names))
+	for i, n := range l.names {
+		w[n] = l.w[i]
+	}
+	return w, l.b
+}
@@ -0,0 +1,174 @@
+package gates
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+var policies = map[string]string{
+	"threshold":     "budget replays when the score exceeds threshold, else none",
+	"scaled":        "ceil(score·budget) replays above threshold",
+	"linear":        "1 to budget replays, linear in the score",
+	"power":         "1 + score^exp·(budget-1) replays",
+	"steps":         "the replays of the first step the score exceeds, else floor",
+	"entropy-steps": "replay5Dyn's table: 10 above 0.9, 5 above 0.7, 2 above 0.5, else 1",
+}
+
+// PolicyNames returns the policy names NewPolicy accepts, sorted.
+func PolicyNames() []string {
+	names := make([]string, 0, len(policies))
+	for n := range policies {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PolicyDoc returns a one-line description of a policy.
+func PolicyDoc(name string) string { return policies[name] }
+
+// KnownPolicy reports whether NewPolicy accepts name.
+func KnownPolicy(name string) bool {
+	_, ok := policies[name]
+	return ok
+}
+
+// PolicyParams are the settings of a named policy; each policy reads the
+// fields its constructor takes and ignores the rest.
+type PolicyParams struct {
+	Threshold float64
+	Budget    int
+	Exp       float64
+	Steps     []Step
+	Floor     int
+}
+
+// NewPolicy returns the named policy, so policies can be saved and
+// rebuilt by name like gates.
+func NewPolicy(name string, p PolicyParams) (func(float64) int, error) {
+	switch name {
+	case "threshold":
+		return Threshold(p.Threshold, p.Budget), nil
+	case "scaled":
+		return Scaled(p.Threshold, p.Budget), nil
+	case "linear":
+		return Linear(p.Budget), nil
+	case "power":
+		if p.Exp <= 0 {
+			return nil, fmt.Errorf("power policy: exp %g", p.Exp)
+		}
+		return Power(p.Budget, p.Exp), nil
+	case "steps":
+		if len(p.Steps) == 0 {
+			return nil, errors.New("steps policy: no steps")
+		}
+		return Steps(p.Steps, p.Floor), nil
+	case "entropy-steps":
+		return EntropySteps(), nil
+	}
+	return nil, fmt.Errorf("unknown policy %q (have %v)", name, PolicyNames())
+}
+
+// Threshold replays the full budget when the score exceeds threshold and
+// not at all otherwise (replay6's BIGTEST policy).
+func Threshold(threshold float64, budget int) func(float64) int {
+	return func(score float64) int {
+		if score > threshold {
+			return budget
+		}
+		return 0
+	}
+}
+
+// Scaled replays ceil(score·budget) times above threshold, at most budget
+// (replay6's temporal policy).
+func Scaled(threshold float64, budget int) func(float64) int {
+	return func(score float64) int {
+		if score <= threshold {
+			return 0
+		}
+		return int(math.Min(float64(budget), math.Ceil(score*float64(budget))))
+	}
+}
+
+// Linear always replays at least once and grows linearly with the score
+// up to budget (nlpReplay1's policy).
+func Linear(budget int) func(float64) int {
+	return Power(budget, 1)
+}
+
+// Power replays 1 + score^exp·(budget-1) times, truncated (nlpReplay2/3 and
+// replayEyeState use exp 1.1).
+func Power(budget int, exp float64) func(float64) int {
+	return func(score float64) int {
+		r := 1 + int(math.Pow(clamp01(score), exp)*float64(budget-1))
+		return max(1, min(r, max(budget, 1)))
+	}
+}
+
+// Step is one rung of a Steps policy.
+type Step struct {
+	Above float64 `json:"above" yaml:"above"` // scores strictly above this...
+	Reps  int     `json:"reps" yaml:"reps"`   // ...replay this many times
+}
+
+// Steps replays the Reps of the first step whose Above the score exceeds,
+// or floor when none does. List steps from the highest Above down.
+func Steps(steps []Step, floor int) func(float64) int {
+	return func(score float64) int {
+		for _, s := range steps {
+			if score > s.Above {
+				return s.Reps
+			}
+		}
+		return floor
+	}
+}
+
+// EntropySteps is replay5Dyn's entropy-to-replays table: 10 above 0.9, 5
+// above 0.7, 2 above 0.5, else 1.
+func EntropySteps() func(float64) int {
+	return Steps([]Step{{0.9, 10}, {0.7, 5}, {0.5, 2}}, 1)
+}
+
+// Budget caps the total replays a set of layers may take, e.g. per epoch
+// or per run, so gates that fire often cannot blow up training time. It
+// is safe for concurrent use.
+type Budget struct {
+	mu          sync.Mutex
+	total, used int
+}
+
+// NewBudget allows total replays until Reset.
+func NewBudget(total int) *Budget {
+	return &Budget{total: total}
+}
+
+// Wrap limits policy to what is left of the budget.
+func (b *Budget) Wrap(policy func(float64) int) func(float64) int {
+	return func(score float64) int {
+		want := policy(score)
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		grant := max(0, min(want, b.total-b.used))
+		b.used += grant
+		return grant
+	}
+}
+
+// Used returns the replays granted since the last Reset.
+func (b *Budget) Used() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Reset refills the budget.
+func (b *Budget) Reset() {
+	b.mu.Lock()
+	b.used = 0
+	b.mu.Unlock()
+}
@@ -0,0 +1,119 @@
+package gates
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy func(float64) int
+		score  float64
+		want   int
+	}{
+		{"threshold below", Threshold(0.5, 3), 0.5, 0},
+		{"threshold above", Threshold(0.5, 3), 0.51, 3},
+
+		{"scaled below", Scaled(0.3, 4), 0.3, 0},
+		{"scaled partial", Scaled(0.3, 4), 0.4, 2},
+		{"scaled full", Scaled(0.3, 4), 1, 4},
+
+		{"linear zero", Linear(5), 0, 1},
+		{"linear half", Linear(5), 0.5, 3},
+		{"linear one", Linear(5), 1, 5},
+		{"linear clamps", Linear(5), 7, 5},
+		{"linear no budget", Linear(0), 1, 1},
+
+		{"power zero", Power(20, 1.1), 0, 1},
+		{"power one", Power(20, 1.1), 1, 20},
+		{"power mid", Power(10, 1.1), 0.5, 5}, // 1 + int(0.5^1.1·9 = 4.2)
+
+		{"steps top", EntropySteps(), 0.95, 10},
+		{"steps middle", EntropySteps(), 0.8, 5},
+		{"steps low", EntropySteps(), 0.6, 2},
+		{"steps floor", EntropySteps(), 0.5, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.policy(tt.score); got != tt.want {
+			t.Errorf("%s(%v) = %d, want %d", tt.name, tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(5)
+	p := b.Wrap(Threshold(0, 2))
+	got := []int{p(1), p(1), p(1), p(1)}
+	want := []int{2, 2, 1, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("grants = %v, want %v", got, want)
+		}
+	}
+	if b.Used() != 5 {
+		t.Fatalf("Used = %d, want 5", b.Used())
+	}
+	if p(0) != 0 || b.Used() != 5 {
+		t.Fatal("a zero request should not use budget")
+	}
+	b.Reset()
+	if p(1) != 2 {
+		t.Fatal("Reset did not refill the budget")
+	}
+}
+
+func TestBudgetConcurrent(t *testing.T) {
+	b := NewBudget(100)
+	p := b.Wrap(Threshold(0, 3))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	granted := 0
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g := p(1)
+			mu.Lock()
+			granted += g
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if granted != 100 || b.Used() != 100 {
+		t.Fatalf("granted %d, used %d; want both 100", granted, b.Used())
+	}
+}
+
+// Named policies behave like the constructors they stand for.
+func TestNewPolicy(t *testing.T) {
+	steps := []Step{{0.8, 4}, {0.4, 2}}
+	p := PolicyParams{Threshold: 0.3, Budget: 6, Exp: 1.1, Steps: steps, Floor: 1}
+	want := map[string]func(float64) int{
+		"threshold":     Threshold(0.3, 6),
+		"scaled":        Scaled(0.3, 6),
+		"linear":        Linear(6),
+		"power":         Power(6, 1.1),
+		"steps":         Steps(steps, 1),
+		"entropy-steps": EntropySteps(),
+	}
+	if len(want) != len(PolicyNames()) {
+		t.Fatalf("PolicyNames = %v, test covers %d", PolicyNames(), len(want))
+	}
+	for name, direct := range want {
+		named, err := NewPolicy(name, p)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, s := range []float64{0, 0.25, 0.3, 0.5, 0.75, 0.95, 1} {
+			if got, w := named(s), direct(s); got != w {
+				t.Errorf("%s(%v) = %d, want %d", name, s, got, w)
+			}
+		}
+	}
+	for _, name := range []string{"nope", "power", "steps"} {
+		if _, err := NewPolicy(name, PolicyParams{}); err == nil {
+			t.Errorf("NewPolicy(%q, zero params) did not fail", name)
+		}
+	}
+}
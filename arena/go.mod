@@ -0,0 +1,10 @@
+module arena
+
+go 1.24.3
+
+require (
+	gopkg.in/yaml.v3 v3.0.1
+	paragon v0.0.0
+)
+
+replace paragon => ../../
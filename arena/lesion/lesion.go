@@ -0,0 +1,282 @@
+// Package lesion measures how much a trained network relies on each of its
+// neurons. A lesion zeroes a neuron's bias and incoming weights; Analyze
+// lesions one group of neurons at a time, scores the network on a labelled
+// set, and puts the weights back before the next group.
+//
+//	groups, err := lesion.Singles(net)     // or lesion.Blocks(net, 4)
+//	rep, err := lesion.Analyze(net, testX, testY, groups, lesion.Options{})
+//	lesion.WriteEffects(os.Stdout, "lesions", rep, 20)
+//	lesion.WriteMaps(os.Stdout, rep.Maps)
+//
+// The per-layer salience maps hold the accuracy lost when each neuron was
+// lesioned: neurons that cost nothing are candidates for pruning, the
+// hottest ones are where to look first when interpreting the network.
+// This is multiverseInversionAblation's knockout (invert2) as a
+// stand-alone analysis.
+package lesion
+
+import (
+	"errors"
+	"fmt"
+
+	"arena/eval"
+	"arena/train"
+	"paragon"
+)
+
+// Unit is one neuron: Layers[Layer].Neurons[Y][X].
+type Unit struct {
+	Layer int `json:"layer"`
+	X     int `json:"x"`
+	Y     int `json:"y"`
+}
+
+// Group is a set of neurons lesioned together.
+type Group struct {
+	Name  string `json:"name"`
+	Units []Unit `json:"units"`
+}
+
+// Singles returns one group per neuron of the given layers, every layer
+// after the input when none are given.
+func Singles[T paragon.Numeric](net *paragon.Network[T], layers ...int) ([]Group, error) {
+	layers, err := pick(net, layers)
+	if err != nil {
+		return nil, err
+	}
+	var out []Group
+	for _, l := range layers {
+		g := net.Layers[l]
+		for y := 0; y < g.Height; y++ {
+			for x := 0; x < g.Width; x++ {
+				out = append(out, Group{Name: fmt.Sprintf("L%d(%d,%d)", l, x, y), Units: []Unit{{l, x, y}}})
+			}
+		}
+	}
+	return out, nil
+}
+
+// Blocks returns one group per size×size tile of the given layers, every
+// layer after the input when none are given. Tiles at the right and bottom
+// edges may be smaller.
+func Blocks[T paragon.Numeric](net *paragon.Network[T], size int, layers ...int) ([]Group, error) {
+	layers, err := pick(net, layers)
+	if err != nil {
+		return nil, err
+	}
+	size = max(1, size)
+	var out []Group
+	for _, l := range layers {
+		g := net.Layers[l]
+		for y0 := 0; y0 < g.Height; y0 += size {
+			for x0 := 0; x0 < g.Width; x0 += size {
+				grp := Group{Name: fmt.Sprintf("L%d[%d:%d,%d:%d]", l, x0, min(x0+size, g.Width), y0, min(y0+size, g.Height))}
+				for y := y0; y < min(y0+size, g.Height); y++ {
+					for x := x0; x < min(x0+size, g.Width); x++ {
+						grp.Units = append(grp.Units, Unit{l, x, y})
+					}
+				}
+				out = append(out, grp)
+			}
+		}
+	}
+	return out, nil
+}
+
+// pick checks the given layers, or lists every layer after the input.
+func pick[T paragon.Numeric](net *paragon.Network[T], layers []int) ([]int, error) {
+	for _, l := range layers {
+		if l <= net.InputLayer || l >= len(net.Layers) {
+			return nil, fmt.Errorf("lesion: layer %d is not a lesionable layer of a %d-layer network", l, len(net.Layers))
+		}
+	}
+	if len(layers) > 0 {
+		return layers, nil
+	}
+	var all []int
+	for l := net.InputLayer + 1; l < len(net.Layers); l++ {
+		all = append(all, l)
+	}
+	return all, nil
+}
+
+// Options configures Analyze.
+type Options struct {
+	// OnEffect, when set, is called after each lesion with its index in
+	// the groups, for progress reports.
+	OnEffect func(i int, e Effect)
+}
+
+// Effect is what lesioning one group cost. Drops are in percentage points
+// of accuracy or of per-class recall, positive when the lesion hurt.
+type Effect struct {
+	Group     Group       `json:"group"`
+	Accuracy  float64     `json:"accuracy"`
+	Drop      float64     `json:"drop"`
+	ClassDrop []float64   `json:"class_drop"`
+	Flipped   float64     `json:"flipped"` // % of inputs whose predicted class changed
+	Result    eval.Result `json:"-"`
+}
+
+// WorstClass is the class whose recall the lesion hurt most, -1 when none
+// has classes.
+func (e Effect) WorstClass() int {
+	worst := -1
+	for c, d := range e.ClassDrop {
+		if worst < 0 || d > e.ClassDrop[worst] {
+			worst = c
+		}
+	}
+	return worst
+}
+
+// Report is the outcome of Analyze.
+type Report struct {
+	Samples          int         `json:"samples"`
+	BaselineAccuracy float64     `json:"baseline_accuracy"`
+	Baseline         eval.Result `json:"-"`
+	Effects          []Effect    `json:"effects"` // in the order of the groups
+	Maps             []Map       `json:"maps"`
+}
+
+// Map is one layer's salience: Drop[y][x] is the accuracy drop of the
+// lesion that covered neuron (x, y), 0 for neurons not lesioned. When
+// groups overlap the largest drop is kept.
+type Map struct {
+	Layer  int         `json:"layer"`
+	Width  int         `json:"width"`
+	Height int         `json:"height"`
+	Drop   [][]float64 `json:"drop"`
+}
+
+// Analyze lesions each group of net in turn and scores it on inputs and
+// one-hot targets against the unlesioned network. The lesioned neurons'
+// values are restored after every group, and every weight is restored from
+// a snapshot before Analyze returns, whatever happens in between.
+func Analyze[T paragon.Numeric](net *paragon.Network[T], inputs, targets [][][]float64, groups []Group, opts Options) (rep Report, err error) {
+	if len(inputs) == 0 || len(inputs) != len(targets) {
+		return Report{}, errors.New("lesion: need as many targets as inputs, and at least one")
+	}
+	in := net.Layers[net.InputLayer]
+	if len(inputs[0]) != in.Height || len(inputs[0][0]) != in.Width {
+		return Report{}, fmt.Errorf("lesion: inputs are %dx%d, the network takes %dx%d",
+			len(inputs[0][0]), len(inputs[0]), in.Width, in.Height)
+	}
+	for _, g := range groups {
+		for _, u := range g.Units {
+			if err := check(net, u); err != nil {
+				return Report{}, fmt.Errorf("lesion: group %s: %w", g.Name, err)
+			}
+		}
+	}
+
+	snap := train.Snapshot(net)
+	defer func() {
+		if rerr := snap.Restore(net); rerr != nil && err == nil {
+			err = fmt.Errorf("lesion: restore: %w", rerr)
+		}
+	}()
+
+	labels := eval.Labels(targets)
+	base := eval.FromOutputs(eval.Outputs(net, inputs), labels)
+	rep = Report{Samples: len(inputs), BaselineAccuracy: base.Accuracy, Baseline: base}
+	for i, g := range groups {
+		restore := lesion(net, g)
+		res := eval.FromOutputs(eval.Outputs(net, inputs), labels)
+		restore()
+
+		e := Effect{Group: g, Accuracy: res.Accuracy, Drop: base.Accuracy - res.Accuracy, Result: res}
+		for c := range base.Classes {
+			d := base.Classes[c].Recall
+			if c < len(res.Classes) {
+				d -= res.Classes[c].Recall
+			}
+			e.ClassDrop = append(e.ClassDrop, d*100)
+		}
+		flipped := 0
+		for j := range res.Predicted {
+			if res.Predicted[j] != base.Predicted[j] {
+				flipped++
+			}
+		}
+		e.Flipped = float64(flipped) / float64(len(inputs)) * 100
+		rep.Effects = append(rep.Effects, e)
+		if opts.OnEffect != nil {
+			opts.OnEffect(i, e)
+		}
+	}
+	rep.Maps = maps(net, rep.Effects)
+	return rep, nil
+}
+
+func check[T paragon.Numeric](net *paragon.Network[T], u Unit) error {
+	if u.Layer <= net.InputLayer || u.Layer >= len(net.Layers) {
+		return fmt.Errorf("layer %d is not a lesionable layer of a %d-layer network", u.Layer, len(net.Layers))
+	}
+	g := net.Layers[u.Layer]
+	if u.X < 0 || u.X >= g.Width || u.Y < 0 || u.Y >= g.Height || net.Layers[u.Layer].Neurons[u.Y][u.X] == nil {
+		return fmt.Errorf("no neuron at (%d,%d) in layer %d (%dx%d)", u.X, u.Y, u.Layer, g.Width, g.Height)
+	}
+	return nil
+}
+
+// lesion zeroes the group's biases and incoming weights and returns the
+// function that puts them back.
+func lesion[T paragon.Numeric](net *paragon.Network[T], g Group) func() {
+	type saved struct {
+		n       *paragon.Neuron[T]
+		bias    T
+		weights []T
+	}
+	var keep []saved
+	for _, u := range g.Units {
+		n := net.Layers[u.Layer].Neurons[u.Y][u.X]
+		s := saved{n: n, bias: n.Bias, weights: make([]T, len(n.Inputs))}
+		n.Bias = 0
+		for j := range n.Inputs {
+			s.weights[j] = n.Inputs[j].Weight
+			n.Inputs[j].Weight = 0
+		}
+		keep = append(keep, s)
+	}
+	return func() {
+		// in reverse, so a unit listed twice gets its original values
+		for i := len(keep) - 1; i >= 0; i-- {
+			s := keep[i]
+			s.n.Bias = s.bias
+			for j, w := range s.weights {
+				s.n.Inputs[j].Weight = w
+			}
+		}
+	}
+}
+
+// maps lays the drops out per layer, in layer order.
+func maps[T paragon.Numeric](net *paragon.Network[T], effects []Effect) []Map {
+	byLayer := map[int]*Map{}
+	seen := map[Unit]bool{}
+	for _, e := range effects {
+		for _, u := range e.Group.Units {
+			m := byLayer[u.Layer]
+			if m == nil {
+				g := net.Layers[u.Layer]
+				m = &Map{Layer: u.Layer, Width: g.Width, Height: g.Height, Drop: make([][]float64, g.Height)}
+				for y := range m.Drop {
+					m.Drop[y] = make([]float64, g.Width)
+				}
+				byLayer[u.Layer] = m
+			}
+			if !seen[u] || e.Drop > m.Drop[u.Y][u.X] {
+				m.Drop[u.Y][u.X] = e.Drop
+			}
+			seen[u] = true
+		}
+	}
+	var out []Map
+	for l := range net.Layers {
+		if m := byLayer[l]; m != nil {
+			out = append(out, *m)
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package lesion
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteEffects renders the top lesions by accuracy drop as a table; top <=
+// 0 lists them all.
+func WriteEffects(w io.Writer, title string, rep Report, top int) error {
+	effects := append([]Effect(nil), rep.Effects...)
+	sort.SliceStable(effects, func(i, j int) bool { return effects[i].Drop > effects[j].Drop })
+	if top > 0 && top < len(effects) {
+		effects = effects[:top]
+	}
+
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	fmt.Fprintf(w, "baseline accuracy %.2f%% on %d samples, %d lesions\n", rep.BaselineAccuracy, rep.Samples, len(rep.Effects))
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " #\t Group\t Neurons\t Accuracy %\t Drop\t Flipped %\t Worst class\t")
+	for i, e := range effects {
+		worst := "-"
+		if c := e.WorstClass(); c >= 0 {
+			worst = fmt.Sprintf("%d (%.2f)", c, e.ClassDrop[c])
+		}
+		fmt.Fprintf(tw, " %d\t %s\t %d\t %.2f\t %.2f\t %.2f\t %s\t\n",
+			i+1, e.Group.Name, len(e.Group.Units), e.Accuracy, e.Drop, e.Flipped, worst)
+	}
+	return tw.Flush()
+}
+
+// shades run from no drop to the layer's largest.
+const shades = " .:-=+*#%@"
+
+// WriteMaps draws each salience map as text, one character per neuron,
+// shaded relative to the largest drop in its layer. Neurons whose lesion
+// helped are drawn as "~".
+func WriteMaps(w io.Writer, maps []Map) error {
+	for _, m := range maps {
+		peak := 0.0
+		for _, row := range m.Drop {
+			for _, d := range row {
+				peak = math.Max(peak, d)
+			}
+		}
+		fmt.Fprintf(w, "\nLayer %d (%dx%d), largest drop %.2f points\n", m.Layer, m.Width, m.Height, peak)
+		for _, row := range m.Drop {
+			var b strings.Builder
+			for _, d := range row {
+				switch {
+				case d < 0:
+					b.WriteByte('~')
+				case peak == 0:
+					b.WriteByte(shades[0])
+				default:
+					b.WriteByte(shades[min(len(shades)-1, int(d/peak*float64(len(shades)-1)+0.5))])
+				}
+			}
+			if _, err := fmt.Fprintf(w, "|%s|\n", b.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
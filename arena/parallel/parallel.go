@@ -0,0 +1,47 @@
+// Package parallel runs independent trainings on a bounded number of
+// goroutines. It is the worker pool and default run count shared by the
+// tasks runner, the dataset study and the distillation tournaments.
+//
+//	scores := make([]Score, n)
+//	err := parallel.Do(n, opts.Workers, func(i int) (err error) {
+//		scores[i], err = fit(i)
+//		return err
+//	})
+package parallel
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// Runs is the default number of runs per configuration.
+const Runs = 3
+
+// Workers returns n when it is positive and 80% of the CPUs, at least
+// one, otherwise.
+func Workers(n int) int {
+	if n > 0 {
+		return n
+	}
+	return max(1, int(0.8*float64(runtime.NumCPU())))
+}
+
+// Do calls fn(i) for every i in [0, n), at most Workers(workers) at a
+// time, and waits for all of them. The errors are joined in index order.
+func Do(n, workers int, fn func(i int) error) error {
+	errs := make([]error, n)
+	sem := make(chan struct{}, Workers(workers))
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = fn(i)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
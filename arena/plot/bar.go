@@ -0,0 +1,88 @@
+package plot
+
+import (
+	"image"
+	"math"
+)
+
+// BarSeries is one bar per group, e.g. "dynamic" across hidden depths.
+type BarSeries struct {
+	Name   string
+	Values []float64 // per group; NaN leaves a gap
+	Err    []float64 // optional ± error bar per group, e.g. the std
+}
+
+// BarChart draws grouped bars: each group holds one bar per series, e.g.
+// baseline/static/dynamic side by side for every hidden depth.
+type BarChart struct {
+	Title, YLabel string
+	Groups        []string
+	Series        []BarSeries
+	Width, Height int // 0 for DefaultWidth, DefaultHeight
+	// YMin and YMax fix the y range when YMax > YMin. Otherwise it fits
+	// the data, starting at 0 unless values are negative.
+	YMin, YMax float64
+}
+
+// Render draws the chart.
+func (c *BarChart) Render() *image.RGBA {
+	img := newCanvas(c.Width, c.Height)
+	top := drawTitle(img, c.Title)
+
+	var lows, highs []float64
+	names := make([]string, len(c.Series))
+	for i, s := range c.Series {
+		names[i] = s.Name
+		for g, v := range s.Values {
+			e := 0.0
+			if g < len(s.Err) && !math.IsNaN(s.Err[g]) {
+				e = s.Err[g]
+			}
+			lows, highs = append(lows, v-e), append(highs, v+e)
+		}
+	}
+	ylo, _ := dataRange(lows)
+	_, yhi := dataRange(highs)
+	switch {
+	case c.YMax > c.YMin:
+		ylo, yhi = c.YMin, c.YMax
+	case ylo > 0:
+		ylo = 0
+	}
+	yTicks, ylo, yhi := niceTicks(ylo, yhi, 6)
+
+	legend := names
+	if len(c.Series) == 1 {
+		legend = nil // a single series is named by the title or y label
+	}
+	right := img.Bounds().Dx() - pad - legendWidth(legend)
+	f := newFrame(img, top, right, nil, 0, 1, yTicks, ylo, yhi, "", c.YLabel)
+
+	n := max(len(c.Groups), 1)
+	groupW := float64(f.x.pxHi-f.x.pxLo) / float64(n)
+	barW := groupW * 0.8 / float64(max(len(c.Series), 1))
+	base := f.y.px(math.Max(ylo, math.Min(0, yhi)))
+	for g, name := range c.Groups {
+		gx := float64(f.x.pxLo) + float64(g)*groupW
+		for i, s := range c.Series {
+			if g >= len(s.Values) || math.IsNaN(s.Values[g]) {
+				continue
+			}
+			x0 := int(gx + groupW*0.1 + float64(i)*barW)
+			x1 := int(gx + groupW*0.1 + float64(i+1)*barW)
+			y := f.y.px(s.Values[g])
+			fillRect(img, x0, min(y, base), max(x1-x0-1, 1), max(abs(base-y), 1), paletteColor(i))
+			if g < len(s.Err) && s.Err[g] > 0 {
+				mid := (x0 + x1) / 2
+				lo, hi := f.y.px(s.Values[g]-s.Err[g]), f.y.px(s.Values[g]+s.Err[g])
+				fillRect(img, mid, hi, 1, lo-hi+1, black)
+				fillRect(img, mid-3, hi, 7, 1, black)
+				fillRect(img, mid-3, lo, 7, 1, black)
+			}
+		}
+		l := fit(name, int(groupW)-4, scale)
+		drawText(img, int(gx+groupW/2)-textWidth(l, scale)/2, f.y.pxLo+tickLen+4, l, black, scale)
+	}
+	drawLegend(img, right, top, legend)
+	return img
+}
@@ -0,0 +1,149 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+// A 5x7 bitmap font covering what chart labels need: digits, letters
+// (lower case is drawn as upper case) and common punctuation. Unknown
+// runes draw as '?'.
+const (
+	glyphW       = 5
+	glyphH       = 7
+	glyphAdvance = glyphW + 1
+)
+
+var glyphSrc = map[rune]string{
+	'0':  ".###. #...# #..## #.#.# ##..# #...# .###.",
+	'1':  "..#.. .##.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'2':  ".###. #...# ....# ...#. ..#.. .#... #####",
+	'3':  "####. ....# ....# .###. ....# ....# ####.",
+	'4':  "...#. ..##. .#.#. #..#. ##### ...#. ...#.",
+	'5':  "##### #.... ####. ....# ....# #...# .###.",
+	'6':  ".###. #.... #.... ####. #...# #...# .###.",
+	'7':  "##### ....# ...#. ..#.. .#... .#... .#...",
+	'8':  ".###. #...# #...# .###. #...# #...# .###.",
+	'9':  ".###. #...# #...# .#### ....# ....# .###.",
+	'A':  ".###. #...# #...# ##### #...# #...# #...#",
+	'B':  "####. #...# #...# ####. #...# #...# ####.",
+	'C':  ".###. #...# #.... #.... #.... #...# .###.",
+	'D':  "####. #...# #...# #...# #...# #...# ####.",
+	'E':  "##### #.... #.... ####. #.... #.... #####",
+	'F':  "##### #.... #.... ####. #.... #.... #....",
+	'G':  ".###. #...# #.... #.### #...# #...# .####",
+	'H':  "#...# #...# #...# ##### #...# #...# #...#",
+	'I':  ".###. ..#.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'J':  "..### ...#. ...#. ...#. ...#. #..#. .##..",
+	'K':  "#...# #..#. #.#.. ##... #.#.. #..#. #...#",
+	'L':  "#.... #.... #.... #.... #.... #.... #####",
+	'M':  "#...# ##.## #.#.# #.#.# #...# #...# #...#",
+	'N':  "#...# #...# ##..# #.#.# #..## #...# #...#",
+	'O':  ".###. #...# #...# #...# #...# #...# .###.",
+	'P':  "####. #...# #...# ####. #.... #.... #....",
+	'Q':  ".###. #...# #...# #...# #.#.# #..#. .##.#",
+	'R':  "####. #...# #...# ####. #.#.. #..#. #...#",
+	'S':  ".#### #.... #.... .###. ....# ....# ####.",
+	'T':  "##### ..#.. ..#.. ..#.. ..#.. ..#.. ..#..",
+	'U':  "#...# #...# #...# #...# #...# #...# .###.",
+	'V':  "#...# #...# #...# #...# #...# .#.#. ..#..",
+	'W':  "#...# #...# #...# #.#.# #.#.# #.#.# .#.#.",
+	'X':  "#...# #...# .#.#. ..#.. .#.#. #...# #...#",
+	'Y':  "#...# #...# .#.#. ..#.. ..#.. ..#.. ..#..",
+	'Z':  "##### ....# ...#. ..#.. .#... #.... #####",
+	' ':  "..... ..... ..... ..... ..... ..... .....",
+	'.':  "..... ..... ..... ..... ..... .##.. .##..",
+	',':  "..... ..... ..... ..... .##.. ..#.. .#...",
+	':':  "..... .##.. .##.. ..... .##.. .##.. .....",
+	'-':  "..... ..... ..... ##### ..... ..... .....",
+	'+':  "..... ..#.. ..#.. ##### ..#.. ..#.. .....",
+	'=':  "..... ..... ##### ..... ##### ..... .....",
+	'%':  "##..# ##..# ...#. ..#.. .#... #..## #..##",
+	'/':  "....# ....# ...#. ..#.. .#... #.... #....",
+	'(':  "...#. ..#.. .#... .#... .#... ..#.. ...#.",
+	')':  ".#... ..#.. ...#. ...#. ...#. ..#.. .#...",
+	'[':  ".###. .#... .#... .#... .#... .#... .###.",
+	']':  ".###. ...#. ...#. ...#. ...#. ...#. .###.",
+	'_':  "..... ..... ..... ..... ..... ..... #####",
+	'<':  "...#. ..#.. .#... #.... .#... ..#.. ...#.",
+	'>':  ".#... ..#.. ...#. ....# ...#. ..#.. .#...",
+	'*':  "..... #.#.# .###. ##### .###. #.#.# .....",
+	'#':  ".#.#. .#.#. ##### .#.#. ##### .#.#. .#.#.",
+	'\'': "..#.. ..#.. .#... ..... ..... ..... .....",
+	'?':  ".###. #...# ....# ...#. ..#.. ..... ..#..",
+	'!':  "..#.. ..#.. ..#.. ..#.. ..#.. ..... ..#..",
+	'|':  "..#.. ..#.. ..#.. ..#.. ..#.. ..#.. ..#..",
+	'δ':  "..##. .#... ..#.. .###. #...# #...# .###.",
+	'Δ':  "..#.. ..#.. .#.#. .#.#. #...# #...# #####",
+}
+
+var glyphs = map[rune][glyphH]uint8{}
+
+func init() {
+	for r, src := range glyphSrc {
+		var g [glyphH]uint8
+		for y, row := range strings.Fields(src) {
+			for x, c := range row {
+				if c == '#' {
+					g[y] |= 1 << (glyphW - 1 - x)
+				}
+			}
+		}
+		glyphs[r] = g
+	}
+}
+
+// textWidth is the width of s in pixels at the given scale.
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*glyphAdvance - 1) * scale
+}
+
+// drawText draws s with its top-left corner at (x, y).
+func drawText(img *image.RGBA, x, y int, s string, c color.Color, scale int) {
+	for _, r := range s {
+		g, ok := glyphs[r]
+		if !ok {
+			g, ok = glyphs[[]rune(strings.ToUpper(string(r)))[0]]
+		}
+		if !ok {
+			g = glyphs['?']
+		}
+		for gy := 0; gy < glyphH; gy++ {
+			for gx := 0; gx < glyphW; gx++ {
+				if g[gy]&(1<<(glyphW-1-gx)) == 0 {
+					continue
+				}
+				fillRect(img, x+gx*scale, y+gy*scale, scale, scale, c)
+			}
+		}
+		x += glyphAdvance * scale
+	}
+}
+
+// drawTextVertical draws s bottom-to-top with its bottom-left corner at
+// (x, y), for y-axis labels.
+func drawTextVertical(img *image.RGBA, x, y int, s string, c color.Color, scale int) {
+	for _, r := range s {
+		g, ok := glyphs[r]
+		if !ok {
+			g, ok = glyphs[[]rune(strings.ToUpper(string(r)))[0]]
+		}
+		if !ok {
+			g = glyphs['?']
+		}
+		for gy := 0; gy < glyphH; gy++ {
+			for gx := 0; gx < glyphW; gx++ {
+				if g[gy]&(1<<(glyphW-1-gx)) == 0 {
+					continue
+				}
+				fillRect(img, x+gy*scale, y-(gx+1)*scale, scale, scale, c)
+			}
+		}
+		y -= glyphAdvance * scale
+	}
+}
@@ -0,0 +1,142 @@
+package plot
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// Heatmap colours a grid of values, e.g. accuracy for every replay setting
+// (rows) at every hidden depth (columns). Missing cells are NaN and stay
+// blank.
+type Heatmap struct {
+	Title, XLabel, YLabel string
+	Rows, Cols            []string
+	Values                [][]float64 // [row][col]
+	Format                string      // cell label format, "%.1f" by default; "-" hides labels
+	Width, Height         int         // 0 for DefaultWidth and a height that fits the rows
+}
+
+// Render draws the heatmap with a colour scale on the right.
+func (c *Heatmap) Render() *image.RGBA {
+	height := c.Height
+	if height <= 0 {
+		height = max(DefaultHeight, 160+len(c.Rows)*(lineH+8))
+	}
+	img := newCanvas(c.Width, height)
+	b := img.Bounds()
+	top := drawTitle(img, c.Title)
+
+	lo, hi := dataRange(c.Values...)
+	if math.IsInf(lo, 1) {
+		lo, hi = 0, 1
+	}
+
+	rowW := 0
+	for _, r := range c.Rows {
+		rowW = max(rowW, textWidth(r, scale))
+	}
+	rowW = min(rowW, b.Dx()/2)
+	left := pad + rowW + pad
+	if c.YLabel != "" {
+		left += lineH + pad
+	}
+	const barW = 20
+	scaleW := max(textWidth(tickLabel(lo), scale), textWidth(tickLabel(hi), scale))
+	right := b.Dx() - 3*pad - barW - scaleW
+	bottom := b.Dy() - 2*pad - lineH
+	if c.XLabel != "" {
+		bottom -= lineH + pad/2
+	}
+
+	nr, nc := max(len(c.Rows), 1), max(len(c.Cols), 1)
+	cellW := float64(right-left) / float64(nc)
+	cellH := float64(bottom-top) / float64(nr)
+	format := c.Format
+	if format == "" {
+		format = "%.1f"
+	}
+	for r := range c.Rows {
+		y0, y1 := top+int(float64(r)*cellH), top+int(float64(r+1)*cellH)
+		l := fit(c.Rows[r], rowW, scale)
+		drawText(img, left-pad-textWidth(l, scale), (y0+y1-lineH)/2, l, black, scale)
+		for k := range c.Cols {
+			if r >= len(c.Values) || k >= len(c.Values[r]) || math.IsNaN(c.Values[r][k]) {
+				continue
+			}
+			v := c.Values[r][k]
+			x0, x1 := left+int(float64(k)*cellW), left+int(float64(k+1)*cellW)
+			col := heat(norm(v, lo, hi))
+			fillRect(img, x0, y0, x1-x0, y1-y0, col)
+			if format == "-" {
+				continue
+			}
+			s := fmt.Sprintf(format, v)
+			for _, sc := range []int{scale, 1} {
+				if textWidth(s, sc) <= x1-x0-4 && glyphH*sc <= y1-y0-2 {
+					drawText(img, (x0+x1-textWidth(s, sc))/2, (y0+y1-glyphH*sc)/2, s, contrast(col), sc)
+					break
+				}
+			}
+		}
+	}
+	strokeRect(img, left, top, right-left+1, bottom-top+1, black)
+	for k := range c.Cols {
+		x0, x1 := left+int(float64(k)*cellW), left+int(float64(k+1)*cellW)
+		l := fit(c.Cols[k], x1-x0-4, scale)
+		drawText(img, (x0+x1-textWidth(l, scale))/2, bottom+pad/2, l, black, scale)
+	}
+	if c.XLabel != "" {
+		l := fit(c.XLabel, right-left, scale)
+		drawText(img, (left+right-textWidth(l, scale))/2, b.Dy()-pad-lineH, l, black, scale)
+	}
+	if c.YLabel != "" {
+		l := fit(c.YLabel, bottom-top, scale)
+		drawTextVertical(img, pad, (top+bottom+textWidth(l, scale))/2, l, black, scale)
+	}
+
+	// colour scale, hi at the top
+	bx := right + 2*pad
+	for y := top; y <= bottom; y++ {
+		fillRect(img, bx, y, barW, 1, heat(float64(bottom-y)/float64(max(bottom-top, 1))))
+	}
+	strokeRect(img, bx, top, barW, bottom-top+1, black)
+	drawText(img, bx+barW+pad/2, top, tickLabel(hi), grey, scale)
+	drawText(img, bx+barW+pad/2, bottom-lineH, tickLabel(lo), grey, scale)
+	return img
+}
+
+func norm(v, lo, hi float64) float64 {
+	if hi <= lo {
+		return 0.5
+	}
+	return (v - lo) / (hi - lo)
+}
+
+// heatStops is a viridis-like dark-blue to yellow scale.
+var heatStops = []color.RGBA{
+	{68, 1, 84, 255},
+	{59, 82, 139, 255},
+	{33, 145, 140, 255},
+	{94, 201, 98, 255},
+	{253, 231, 37, 255},
+}
+
+// heat maps t in [0,1] onto heatStops.
+func heat(t float64) color.RGBA {
+	t = math.Max(0, math.Min(1, t)) * float64(len(heatStops)-1)
+	i := min(int(t), len(heatStops)-2)
+	f := t - float64(i)
+	a, b := heatStops[i], heatStops[i+1]
+	mix := func(x, y uint8) uint8 { return uint8(math.Round(float64(x) + f*(float64(y)-float64(x)))) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
+
+// contrast picks black or white text for a cell of colour c.
+func contrast(c color.RGBA) color.RGBA {
+	if 0.299*float64(c.R)+0.587*float64(c.G)+0.114*float64(c.B) > 140 {
+		return black
+	}
+	return white
+}
@@ -0,0 +1,75 @@
+package plot
+
+import (
+	"image"
+	"math"
+)
+
+// Series is one line of a LineChart. Points with a NaN Y are skipped and
+// break the line.
+type Series struct {
+	Name string
+	X, Y []float64 // X defaults to 1, 2, 3, …
+}
+
+// LineChart plots series against a shared numeric x axis, e.g. validation
+// accuracy per epoch or score per replay depth.
+type LineChart struct {
+	Title, XLabel, YLabel string
+	Series                []Series
+	Width, Height         int // 0 for DefaultWidth, DefaultHeight
+	// YMin and YMax fix the y range when YMax > YMin; otherwise it fits
+	// the data.
+	YMin, YMax float64
+}
+
+// Render draws the chart.
+func (c *LineChart) Render() *image.RGBA {
+	img := newCanvas(c.Width, c.Height)
+	top := drawTitle(img, c.Title)
+
+	xs := make([][]float64, len(c.Series))
+	ys := make([][]float64, len(c.Series))
+	names := make([]string, len(c.Series))
+	for i, s := range c.Series {
+		xs[i], ys[i], names[i] = s.X, s.Y, s.Name
+		if len(s.X) == 0 {
+			xs[i] = make([]float64, len(s.Y))
+			for k := range xs[i] {
+				xs[i][k] = float64(k + 1)
+			}
+		}
+	}
+	xlo, xhi := dataRange(xs...)
+	ylo, yhi := dataRange(ys...)
+	if c.YMax > c.YMin {
+		ylo, yhi = c.YMin, c.YMax
+	} else if d := (yhi - ylo) * 0.05; d > 0 {
+		ylo, yhi = ylo-d, yhi+d
+	}
+	xTicks, xlo, xhi := niceTicks(xlo, xhi, 8)
+	yTicks, ylo, yhi := niceTicks(ylo, yhi, 6)
+
+	right := img.Bounds().Dx() - pad - legendWidth(names)
+	f := newFrame(img, top, right, xTicks, xlo, xhi, yTicks, ylo, yhi, c.XLabel, c.YLabel)
+
+	for i := range c.Series {
+		col := paletteColor(i)
+		havePrev := false
+		var px, py int
+		for k := range ys[i] {
+			if k >= len(xs[i]) || math.IsNaN(ys[i][k]) || math.IsNaN(xs[i][k]) {
+				havePrev = false
+				continue
+			}
+			x, y := f.x.px(xs[i][k]), f.y.px(ys[i][k])
+			if havePrev {
+				drawLine(img, px, py, x, y, 2, col)
+			}
+			fillRect(img, x-3, y-3, 7, 7, col)
+			px, py, havePrev = x, y, true
+		}
+	}
+	drawLegend(img, right, top, names)
+	return img
+}
@@ -0,0 +1,308 @@
+// Package plot draws benchmark figures straight to PNG with nothing but
+// the standard library: line charts for learning curves and sweeps, grouped
+// bar charts for baseline/static/dynamic comparisons and heatmaps for
+// settings grids. Labels use a built-in bitmap font, so figures come out
+// the same on every machine without fonts or external tools.
+//
+//	c := &plot.LineChart{Title: "Learning curve", XLabel: "epoch", YLabel: "val accuracy",
+//		Series: plot.Epochs(rec.StepRecords(), "val_accuracy", plot.Param("replay"))}
+//	err := plot.Save("figures/curves.png", c)
+package plot
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Chart is a figure that can be rendered.
+type Chart interface {
+	Render() *image.RGBA
+}
+
+// Default figure size in pixels.
+const (
+	DefaultWidth  = 900
+	DefaultHeight = 560
+)
+
+// Save renders c to a PNG file, creating its directory if needed.
+func Save(path string, c Chart) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("plot: %w", err)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("plot: %w", err)
+	}
+	if err := Encode(f, c); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Encode renders c as PNG to w.
+func Encode(w io.Writer, c Chart) error {
+	if err := png.Encode(w, c.Render()); err != nil {
+		return fmt.Errorf("plot: %w", err)
+	}
+	return nil
+}
+
+// Palette colours series in order; it repeats after eight.
+var Palette = []color.RGBA{
+	{31, 119, 180, 255},  // blue
+	{255, 127, 14, 255},  // orange
+	{44, 160, 44, 255},   // green
+	{214, 39, 40, 255},   // red
+	{148, 103, 189, 255}, // purple
+	{140, 86, 75, 255},   // brown
+	{227, 119, 194, 255}, // pink
+	{23, 190, 207, 255},  // cyan
+}
+
+func paletteColor(i int) color.RGBA { return Palette[i%len(Palette)] }
+
+var (
+	white = color.RGBA{255, 255, 255, 255}
+	black = color.RGBA{0, 0, 0, 255}
+	grey  = color.RGBA{110, 110, 110, 255}
+	grid  = color.RGBA{225, 225, 225, 255}
+)
+
+const (
+	scale   = 2 // font scale of titles and labels
+	pad     = 12
+	lineH   = glyphH * scale
+	tickLen = 5
+)
+
+func newCanvas(w, h int) *image.RGBA {
+	if w <= 0 {
+		w = DefaultWidth
+	}
+	if h <= 0 {
+		h = DefaultHeight
+	}
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	fillRect(img, 0, 0, w, h, white)
+	return img
+}
+
+func fillRect(img *image.RGBA, x, y, w, h int, c color.Color) {
+	r := image.Rect(x, y, x+w, y+h).Intersect(img.Bounds())
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			img.Set(px, py, c)
+		}
+	}
+}
+
+func strokeRect(img *image.RGBA, x, y, w, h int, c color.Color) {
+	fillRect(img, x, y, w, 1, c)
+	fillRect(img, x, y+h-1, w, 1, c)
+	fillRect(img, x, y, 1, h, c)
+	fillRect(img, x+w-1, y, 1, h, c)
+}
+
+// drawLine draws a width-pixel line from (x0, y0) to (x1, y1) (Bresenham).
+func drawLine(img *image.RGBA, x0, y0, x1, y1, width int, c color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		fillRect(img, x0-width/2, y0-width/2, width, width, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * e; e2 >= dy {
+			e += dy
+			x0 += sx
+		} else {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// drawTitle centres title at the top of img and returns the y below it.
+func drawTitle(img *image.RGBA, title string) int {
+	if title == "" {
+		return pad
+	}
+	title = fit(title, img.Bounds().Dx()-2*pad, scale)
+	drawText(img, (img.Bounds().Dx()-textWidth(title, scale))/2, pad, title, black, scale)
+	return 2*pad + lineH
+}
+
+// fit shortens s with a trailing '.' until it is at most width pixels wide.
+func fit(s string, width, scale int) string {
+	r := []rune(s)
+	if textWidth(s, scale) <= width {
+		return s
+	}
+	for len(r) > 1 && textWidth(string(r)+".", scale) > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "."
+}
+
+// axis maps a data range onto a pixel range.
+type axis struct {
+	lo, hi     float64
+	pxLo, pxHi int
+}
+
+func (a axis) px(v float64) int {
+	if a.hi == a.lo {
+		return (a.pxLo + a.pxHi) / 2
+	}
+	return a.pxLo + int(math.Round((v-a.lo)/(a.hi-a.lo)*float64(a.pxHi-a.pxLo)))
+}
+
+// niceTicks returns round tick values (1, 2 or 5 × 10^k apart) covering
+// [lo, hi] with about n ticks, and the widened range they span.
+func niceTicks(lo, hi float64, n int) (ticks []float64, tlo, thi float64) {
+	if math.IsNaN(lo) || math.IsInf(lo, 0) || math.IsNaN(hi) || math.IsInf(hi, 0) {
+		lo, hi = 0, 1
+	}
+	if hi <= lo {
+		d := math.Max(math.Abs(lo)*0.1, 1)
+		lo, hi = lo-d, hi+d
+	}
+	raw := (hi - lo) / float64(max(n, 1))
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := 10 * mag
+	for _, m := range []float64{1, 2, 5} {
+		if raw <= m*mag {
+			step = m * mag
+			break
+		}
+	}
+	tlo, thi = math.Floor(lo/step)*step, math.Ceil(hi/step)*step
+	for v := tlo; v <= thi+step/2; v += step {
+		ticks = append(ticks, math.Round(v/step)*step)
+	}
+	return ticks, tlo, thi
+}
+
+func tickLabel(v float64) string {
+	if v == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// frame is the plotting area of a chart with its axes and labels drawn.
+type frame struct {
+	img  *image.RGBA
+	x, y axis
+}
+
+// newFrame lays out an x/y plot below top and left of right, drawing the
+// y ticks, grid lines and axis labels. xTicks may be nil for a
+// categorical x axis the caller labels itself.
+func newFrame(img *image.RGBA, top, right int, xTicks []float64, xlo, xhi float64,
+	yTicks []float64, ylo, yhi float64, xLabel, yLabel string) frame {
+	b := img.Bounds()
+	yw := 0
+	for _, t := range yTicks {
+		yw = max(yw, textWidth(tickLabel(t), scale))
+	}
+	left := pad + yw + tickLen + 4
+	if yLabel != "" {
+		left += lineH + pad
+	}
+	bottom := b.Dy() - pad - lineH - tickLen - 6
+	if xLabel != "" {
+		bottom -= lineH + pad/2
+	}
+	f := frame{
+		img: img,
+		x:   axis{lo: xlo, hi: xhi, pxLo: left, pxHi: right},
+		y:   axis{lo: ylo, hi: yhi, pxLo: bottom, pxHi: top},
+	}
+
+	for _, t := range yTicks {
+		py := f.y.px(t)
+		fillRect(img, left, py, right-left, 1, grid)
+		fillRect(img, left-tickLen, py, tickLen, 1, black)
+		l := tickLabel(t)
+		drawText(img, left-tickLen-4-textWidth(l, scale), py-lineH/2, l, grey, scale)
+	}
+	for _, t := range xTicks {
+		px := f.x.px(t)
+		fillRect(img, px, top, 1, bottom-top, grid)
+		fillRect(img, px, bottom, 1, tickLen, black)
+		l := tickLabel(t)
+		drawText(img, px-textWidth(l, scale)/2, bottom+tickLen+4, l, grey, scale)
+	}
+	strokeRect(img, left, top, right-left+1, bottom-top+1, black)
+
+	if xLabel != "" {
+		l := fit(xLabel, right-left, scale)
+		drawText(img, (left+right-textWidth(l, scale))/2, b.Dy()-pad-lineH, l, black, scale)
+	}
+	if yLabel != "" {
+		l := fit(yLabel, bottom-top, scale)
+		drawTextVertical(img, pad, (top+bottom+textWidth(l, scale))/2, l, black, scale)
+	}
+	return f
+}
+
+// legendWidth is the width drawLegend needs for names.
+func legendWidth(names []string) int {
+	w := 0
+	for _, n := range names {
+		w = max(w, textWidth(n, scale))
+	}
+	if w == 0 {
+		return 0
+	}
+	return w + lineH + 3*pad
+}
+
+// drawLegend lists names with their palette swatches from (x, y) down.
+func drawLegend(img *image.RGBA, x, y int, names []string) {
+	for i, n := range names {
+		fillRect(img, x+pad, y, lineH, lineH, paletteColor(i))
+		drawText(img, x+2*pad+lineH, y, n, black, scale)
+		y += lineH + pad/2
+	}
+}
+
+// dataRange returns the finite min and max of vs.
+func dataRange(vs ...[]float64) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range vs {
+		for _, x := range v {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				continue
+			}
+			lo, hi = math.Min(lo, x), math.Max(hi, x)
+		}
+	}
+	return lo, hi
+}
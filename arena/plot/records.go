@@ -0,0 +1,200 @@
+package plot
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"arena/results"
+)
+
+// Key names what a record belongs to in a chart: a series, group, row or
+// column.
+type Key func(results.Record) string
+
+// ByModel keys records by their model name.
+func ByModel(r results.Record) string { return r.Model }
+
+// Param keys records by one of their params, e.g. Param("replay").
+func Param(name string) Key {
+	return func(r results.Record) string {
+		v, ok := r.Params[name]
+		if !ok {
+			return ""
+		}
+		if f, ok := number(v); ok {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		return fmt.Sprint(v)
+	}
+}
+
+// value reads metric from a record; "adhd" is the ADHD score.
+func value(r results.Record, metric string) (float64, bool) {
+	if metric == "adhd" && r.ADHD != nil {
+		return r.ADHD.Score, true
+	}
+	v, ok := r.Metrics[metric]
+	return v, ok
+}
+
+// number converts a param as written (int, float) or read back from JSON
+// (float64) to float64.
+func number(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	case float32:
+		return float64(x), true
+	}
+	return 0, false
+}
+
+// means averages metric per (a, b) pair over records of the given kind,
+// returning the sorted a and b keys.
+func means(records []results.Record, kind, metric string, a, b func(results.Record) (string, bool)) (as, bs []string, mean, std map[[2]string]float64) {
+	vals := map[[2]string][]float64{}
+	for _, r := range records {
+		if r.Kind != kind {
+			continue
+		}
+		v, ok := value(r, metric)
+		ka, okA := a(r)
+		kb, okB := b(r)
+		if !ok || !okA || !okB {
+			continue
+		}
+		vals[[2]string{ka, kb}] = append(vals[[2]string{ka, kb}], v)
+	}
+	mean, std = map[[2]string]float64{}, map[[2]string]float64{}
+	seenA, seenB := map[string]bool{}, map[string]bool{}
+	for k, xs := range vals {
+		var m, ss float64
+		for _, x := range xs {
+			m += x
+		}
+		m /= float64(len(xs))
+		for _, x := range xs {
+			ss += (x - m) * (x - m)
+		}
+		mean[k] = m
+		if len(xs) > 1 {
+			std[k] = math.Sqrt(ss / float64(len(xs)-1))
+		}
+		if !seenA[k[0]] {
+			seenA[k[0]] = true
+			as = append(as, k[0])
+		}
+		if !seenB[k[1]] {
+			seenB[k[1]] = true
+			bs = append(bs, k[1])
+		}
+	}
+	sortKeys(as)
+	sortKeys(bs)
+	return as, bs, mean, std
+}
+
+// sortKeys orders numerically when every key is a number, else as strings.
+func sortKeys(keys []string) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, errA := strconv.ParseFloat(keys[i], 64)
+		b, errB := strconv.ParseFloat(keys[j], 64)
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+func keyed(k Key) func(results.Record) (string, bool) {
+	if k == nil {
+		return func(results.Record) (string, bool) { return "", true }
+	}
+	return func(r results.Record) (string, bool) { return k(r), true }
+}
+
+// Epochs builds one learning curve per series from epoch records, the
+// metric averaged over records that share a series and epoch (e.g. seeds).
+func Epochs(records []results.Record, metric string, series Key) []Series {
+	epoch := func(r results.Record) (string, bool) { return strconv.Itoa(r.Epoch), r.Epoch > 0 }
+	names, epochs, mean, _ := means(records, results.KindEpoch, metric, keyed(series), epoch)
+	return toSeries(names, epochs, mean, metric)
+}
+
+// Sweep builds one line per series of the metric's mean over final
+// records against a numeric param, e.g. accuracy against max_replay.
+func Sweep(records []results.Record, metric, param string, series Key) []Series {
+	x := func(r results.Record) (string, bool) {
+		f, ok := number(r.Params[param])
+		return strconv.FormatFloat(f, 'g', -1, 64), ok
+	}
+	names, xs, mean, _ := means(records, results.KindFinal, metric, keyed(series), x)
+	return toSeries(names, xs, mean, metric)
+}
+
+func toSeries(names, xs []string, mean map[[2]string]float64, metric string) []Series {
+	out := make([]Series, 0, len(names))
+	for _, n := range names {
+		s := Series{Name: n}
+		if n == "" {
+			s.Name = metric
+		}
+		for _, x := range xs {
+			m, ok := mean[[2]string{n, x}]
+			if !ok {
+				continue
+			}
+			f, _ := strconv.ParseFloat(x, 64)
+			s.X, s.Y = append(s.X, f), append(s.Y, m)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// Bars charts the mean ± std of metric over final records, one group per
+// group key and one bar per series key. A nil series gives one bar per
+// group.
+func Bars(records []results.Record, metric string, group, series Key) *BarChart {
+	groups, names, mean, std := means(records, results.KindFinal, metric, keyed(group), keyed(series))
+	c := &BarChart{YLabel: metric, Groups: groups}
+	for _, n := range names {
+		s := BarSeries{Name: n, Values: make([]float64, len(groups)), Err: make([]float64, len(groups))}
+		if n == "" {
+			s.Name = metric
+		}
+		for g, gk := range groups {
+			m, ok := mean[[2]string{gk, n}]
+			if !ok {
+				m = math.NaN()
+			}
+			s.Values[g], s.Err[g] = m, std[[2]string{gk, n}]
+		}
+		c.Series = append(c.Series, s)
+	}
+	return c
+}
+
+// Grid is a heatmap of the mean of metric over final records, one row per
+// row key and one column per column key.
+func Grid(records []results.Record, metric string, row, col Key) *Heatmap {
+	rows, cols, mean, _ := means(records, results.KindFinal, metric, keyed(row), keyed(col))
+	h := &Heatmap{Rows: rows, Cols: cols, Values: make([][]float64, len(rows))}
+	for r, rk := range rows {
+		h.Values[r] = make([]float64, len(cols))
+		for c, ck := range cols {
+			m, ok := mean[[2]string{rk, ck}]
+			if !ok {
+				m = math.NaN()
+			}
+			h.Values[r][c] = m
+		}
+	}
+	return h
+}
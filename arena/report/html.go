@@ -0,0 +1,520 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"arena/plot"
+	"arena/results"
+	"arena/stats"
+)
+
+// maxBars is the most models a step's bar charts show.
+const maxBars = 20
+
+// Write renders run as one HTML page with its charts embedded. base, when
+// not nil, is compared with run model by model.
+func Write(w io.Writer, run, base *Run) error {
+	p := page{Run: run, Base: base, Generated: time.Now().UTC().Format(time.RFC3339), BucketKeys: results.BucketKeys}
+	if run.Seeds != nil {
+		for _, k := range sortedKeys(run.Seeds.Streams) {
+			p.Streams = append(p.Streams, stream{k, run.Seeds.Streams[k]})
+		}
+	}
+	for _, name := range run.Steps() {
+		s, err := newStep(name, run.StepRecords(name))
+		if err != nil {
+			return err
+		}
+		p.Steps = append(p.Steps, s)
+	}
+	if base != nil {
+		p.Diff = diff(run, base)
+	}
+	if err := pageTmpl.Execute(w, p); err != nil {
+		return fmt.Errorf("report: %w", err)
+	}
+	return nil
+}
+
+type page struct {
+	Run, Base  *Run
+	Generated  string
+	BucketKeys []string
+	Streams    []stream
+	Steps      []step
+	Diff       []diffRow
+}
+
+type stream struct {
+	Name string
+	Seed int64
+}
+
+type step struct {
+	Name    string
+	Models  []model
+	Metrics []string // summary columns
+	Buckets bool
+	Charts  []chart
+	Epochs  []epochTable
+	// Record counts by kind.
+	FinalRecords, EpochRecords int
+}
+
+type model struct {
+	Summary results.Summary
+	Params  []param
+	Cells   []string // one per step.Metrics
+	Buckets []string // one per results.BucketKeys
+	// Diagnostics of the model's lowest-seed run, and that seed.
+	Diag *results.Diagnostics
+	Seed int64
+}
+
+type param struct{ Key, Value string }
+
+type chart struct {
+	Title string
+	Src   template.URL
+}
+
+type epochTable struct {
+	Model   string
+	Metrics []string
+	Rows    [][]string
+}
+
+func newStep(name string, recs []results.Record) (step, error) {
+	s := step{Name: name}
+	if s.Name == "" {
+		s.Name = "(whole program)"
+	}
+	sums := results.Summarize(recs)
+	sort.SliceStable(sums, func(i, j int) bool { return sums[i].Model < sums[j].Model })
+	metricSet := map[string]bool{}
+	for _, sum := range sums {
+		for k := range sum.Metrics {
+			metricSet[k] = true
+		}
+		s.Buckets = s.Buckets || len(sum.Buckets) > 0
+	}
+	s.Metrics = sortedKeys(metricSet)
+
+	for _, sum := range sums {
+		m := model{Summary: sum, Params: params(recs, sum.Model)}
+		for _, k := range s.Metrics {
+			if st, ok := sum.Metrics[k]; ok {
+				m.Cells = append(m.Cells, st.String())
+			} else {
+				m.Cells = append(m.Cells, "-")
+			}
+		}
+		for _, k := range results.BucketKeys {
+			m.Buckets = append(m.Buckets, fmt.Sprintf("%.1f", sum.Buckets[k]))
+		}
+		m.Diag, m.Seed = diagnostics(recs, sum.Model)
+		s.Models = append(s.Models, m)
+	}
+	for _, r := range recs {
+		switch r.Kind {
+		case results.KindFinal:
+			s.FinalRecords++
+		case results.KindEpoch:
+			s.EpochRecords++
+		}
+	}
+
+	if n := len(sums); n > 0 && n <= maxBars {
+		for _, metric := range []string{"accuracy", "adhd"} {
+			c := plot.Bars(recs, metric, plot.ByModel, nil)
+			if len(c.Groups) == 0 {
+				continue
+			}
+			c.Title = fmt.Sprintf("%s: %s (mean ± std)", s.Name, metric)
+			if err := s.addChart(c.Title, c); err != nil {
+				return s, err
+			}
+		}
+	}
+	for _, metric := range epochMetrics(recs) {
+		c := &plot.LineChart{Title: fmt.Sprintf("%s: %s per epoch", s.Name, metric),
+			XLabel: "epoch", YLabel: metric, Series: plot.Epochs(recs, metric, plot.ByModel)}
+		if err := s.addChart(c.Title, c); err != nil {
+			return s, err
+		}
+	}
+	s.Epochs = epochTables(recs)
+	return s, nil
+}
+
+func (s *step) addChart(title string, c plot.Chart) error {
+	var buf bytes.Buffer
+	if err := plot.Encode(&buf, c); err != nil {
+		return err
+	}
+	src := "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	s.Charts = append(s.Charts, chart{Title: title, Src: template.URL(src)})
+	return nil
+}
+
+// params lists every param of a model's final records, with the distinct
+// values each took.
+func params(recs []results.Record, modelName string) []param {
+	values := map[string][]string{}
+	for _, r := range recs {
+		if r.Kind != results.KindFinal || r.Model != modelName {
+			continue
+		}
+		for k, v := range r.Params {
+			s := fmt.Sprint(v)
+			if !contains(values[k], s) {
+				values[k] = append(values[k], s)
+			}
+		}
+	}
+	var out []param
+	for _, k := range sortedKeys(values) {
+		out = append(out, param{k, strings.Join(values[k], ", ")})
+	}
+	return out
+}
+
+// diagnostics returns the diagnostics of the model's final record with the
+// lowest seed, so the same run is shown every time.
+func diagnostics(recs []results.Record, modelName string) (*results.Diagnostics, int64) {
+	var best *results.Record
+	for i := range recs {
+		r := &recs[i]
+		if r.Kind != results.KindFinal || r.Model != modelName || r.Diagnostics == nil {
+			continue
+		}
+		if best == nil || r.Seed < best.Seed {
+			best = r
+		}
+	}
+	if best == nil {
+		return nil, 0
+	}
+	return best.Diagnostics, best.Seed
+}
+
+// epochMetrics are the metrics worth a learning-curve chart: every epoch
+// metric except the learning rate and timings, at most four.
+func epochMetrics(recs []results.Record) []string {
+	set := map[string]bool{}
+	for _, r := range recs {
+		if r.Kind != results.KindEpoch {
+			continue
+		}
+		for k := range r.Metrics {
+			if k != "lr" && k != "seconds" {
+				set[k] = true
+			}
+		}
+	}
+	ms := sortedKeys(set)
+	return ms[:min(len(ms), 4)]
+}
+
+func epochTables(recs []results.Record) []epochTable {
+	var order []string
+	byModel := map[string][]results.Record{}
+	for _, r := range recs {
+		if r.Kind != results.KindEpoch {
+			continue
+		}
+		if _, ok := byModel[r.Model]; !ok {
+			order = append(order, r.Model)
+		}
+		byModel[r.Model] = append(byModel[r.Model], r)
+	}
+	sort.Strings(order)
+	var out []epochTable
+	for _, m := range order {
+		rs := byModel[m]
+		sort.SliceStable(rs, func(i, j int) bool { return rs[i].Epoch < rs[j].Epoch })
+		set := map[string]bool{}
+		for _, r := range rs {
+			for k := range r.Metrics {
+				set[k] = true
+			}
+		}
+		t := epochTable{Model: m, Metrics: sortedKeys(set)}
+		for _, r := range rs {
+			row := []string{fmt.Sprint(r.Epoch)}
+			for _, k := range t.Metrics {
+				if v, ok := r.Metrics[k]; ok {
+					row = append(row, fmt.Sprintf("%.4g", v))
+				} else {
+					row = append(row, "-")
+				}
+			}
+			t.Rows = append(t.Rows, row)
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+type diffRow struct {
+	Step, Model, Metric string
+	Base, Run           string
+	Diff                string
+	P                   string
+	Better, Worse       bool
+	Note                string
+}
+
+// diff compares every step/model the two runs share on accuracy, ADHD and
+// log-loss, and lists the models only one of them has.
+func diff(run, base *Run) []diffRow {
+	type key struct{ step, model string }
+	have := func(r *Run) (map[key][]results.Record, []key) {
+		m := map[key][]results.Record{}
+		var order []key
+		for _, rec := range r.Records {
+			if rec.Kind != results.KindFinal {
+				continue
+			}
+			k := key{rec.Step, rec.Model}
+			if _, ok := m[k]; !ok {
+				order = append(order, k)
+			}
+			m[k] = append(m[k], rec)
+		}
+		return m, order
+	}
+	cur, order := have(run)
+	old, oldOrder := have(base)
+
+	var rows []diffRow
+	for _, k := range order {
+		b, ok := old[k]
+		if !ok {
+			rows = append(rows, diffRow{Step: k.step, Model: k.model, Note: "new in this run"})
+			continue
+		}
+		for _, metric := range []string{"accuracy", "adhd", "log_loss"} {
+			cs := results.Samples(cur[k], metric)
+			bs := results.Samples(b, metric)
+			if len(cs) == 0 || len(bs) == 0 {
+				continue
+			}
+			c := stats.Compare(bs[0], cs[0], 0.95)
+			row := diffRow{Step: k.step, Model: k.model, Metric: metric,
+				Base: fmt.Sprintf("%.3f (n=%d)", c.MeanBase, c.NBase),
+				Run:  fmt.Sprintf("%.3f (n=%d)", c.MeanOther, c.NOther),
+				Diff: fmt.Sprintf("%+.3f", c.Diff),
+				P:    "-",
+			}
+			if c.NBase > 1 && c.NOther > 1 && !math.IsNaN(c.Welch.P) {
+				row.P = fmt.Sprintf("%.3g", c.Welch.P)
+				significant := c.Welch.Significant(0.05)
+				up := c.Diff > 0
+				if metric == "log_loss" {
+					up = !up
+				}
+				row.Better, row.Worse = significant && up, significant && !up
+			}
+			rows = append(rows, row)
+		}
+	}
+	for _, k := range oldOrder {
+		if _, ok := cur[k]; !ok {
+			rows = append(rows, diffRow{Step: k.step, Model: k.model, Note: "only in the baseline"})
+		}
+	}
+	return rows
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func contains(xs []string, s string) bool {
+	for _, x := range xs {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+var pageTmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.Format("2006-01-02 15:04:05 MST") },
+	"f3":   func(x float64) string { return fmt.Sprintf("%.3f", x) },
+}).Parse(pageHTML))
+
+const pageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Run.Experiment}} {{.Run.ID}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 1100px; color: #222; }
+h1, h2, h3 { font-weight: 600; }
+h2 { border-bottom: 1px solid #ccc; padding-bottom: .2em; margin-top: 2em; }
+table { border-collapse: collapse; margin: .5em 0 1em; font-size: 90%; }
+th, td { border: 1px solid #ddd; padding: .25em .6em; text-align: right; }
+th { background: #f3f3f3; }
+td.l, th.l { text-align: left; }
+img { max-width: 100%; border: 1px solid #eee; margin: .5em 0; }
+.better { background: #e3f5e1; }
+.worse { background: #fbe3e1; }
+.muted { color: #777; }
+details { margin: .5em 0; }
+code { background: #f6f6f6; padding: 0 .2em; }
+</style>
+</head>
+<body>
+<h1>{{.Run.Experiment}} — run {{.Run.ID}}</h1>
+
+<h2>Run</h2>
+<table>
+<tr><th class="l">Run ID</th><td class="l"><code>{{.Run.ID}}</code></td></tr>
+<tr><th class="l">Experiment</th><td class="l">{{.Run.Experiment}}</td></tr>
+<tr><th class="l">Commit</th><td class="l"><code>{{or .Run.Commit "unknown"}}</code></td></tr>
+<tr><th class="l">Master seed</th><td class="l">{{if .Run.Seeds}}{{.Run.Seed}} (rerun with <code>-seed {{.Run.Seed}}</code>){{else}}<span class="muted">no seed manifest found</span>{{end}}</td></tr>
+<tr><th class="l">Records</th><td class="l">{{len .Run.Records}} from <code>{{.Run.Source}}</code></td></tr>
+<tr><th class="l">Time</th><td class="l">{{time .Run.Start}} – {{time .Run.End}}</td></tr>
+<tr><th class="l">Config hashes</th><td class="l">{{range .Run.ConfigHashes}}<code>{{.}}</code> {{else}}-{{end}}</td></tr>
+{{- if .Base}}
+<tr><th class="l">Baseline</th><td class="l"><code>{{.Base.ID}}</code> ({{.Base.Experiment}}, commit <code>{{or .Base.Commit "unknown"}}</code>)</td></tr>
+{{- end}}
+</table>
+
+{{- if .Streams}}
+<details>
+<summary>Seed manifest ({{len .Streams}} streams, step {{or .Run.Seeds.Step "all"}})</summary>
+<table>
+<tr><th class="l">Stream</th><th>Seed</th></tr>
+{{- range .Streams}}
+<tr><td class="l"><code>{{.Name}}</code></td><td>{{.Seed}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{- end}}
+
+{{- if .Base}}
+<h2>Compared with {{.Base.ID}}</h2>
+{{- if .Diff}}
+<p class="muted">Means over the runs of each model; p is Welch's t-test. Highlighted rows differ at p &lt; 0.05.</p>
+<table>
+<tr><th class="l">Step</th><th class="l">Model</th><th class="l">Metric</th><th>Baseline</th><th>This run</th><th>Δ</th><th>p</th></tr>
+{{- range .Diff}}
+{{- if .Note}}
+<tr><td class="l">{{.Step}}</td><td class="l">{{.Model}}</td><td class="l muted" colspan="5">{{.Note}}</td></tr>
+{{- else}}
+<tr{{if .Better}} class="better"{{else if .Worse}} class="worse"{{end}}><td class="l">{{.Step}}</td><td class="l">{{.Model}}</td><td class="l">{{.Metric}}</td><td>{{.Base}}</td><td>{{.Run}}</td><td>{{.Diff}}</td><td>{{.P}}</td></tr>
+{{- end}}
+{{- end}}
+</table>
+{{- else}}
+<p class="muted">The runs have no final records in common.</p>
+{{- end}}
+{{- end}}
+
+{{- range .Steps}}
+<h2>{{.Name}}</h2>
+<p class="muted">{{.FinalRecords}} final and {{.EpochRecords}} epoch records.</p>
+
+{{- if .Models}}
+<h3>Results</h3>
+<table>
+<tr><th class="l">Model</th><th>N</th><th>ADHD</th>{{range .Metrics}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Models}}
+<tr><td class="l">{{.Summary.Model}}</td><td>{{.Summary.N}}</td><td>{{if .Summary.ADHD.N}}{{.Summary.ADHD}}{{else}}-{{end}}</td>{{range .Cells}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- range .Charts}}
+<img src="{{.Src}}" alt="{{.Title}}">
+{{- end}}
+
+{{- if .Models}}
+<h3>Configuration</h3>
+<table>
+<tr><th class="l">Model</th><th class="l">Params</th></tr>
+{{- range .Models}}
+<tr><td class="l">{{.Summary.Model}}</td><td class="l">{{range .Params}}<code>{{.Key}}={{.Value}}</code> {{else}}-{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- if .Buckets}}
+<h3>ADHD deviation buckets (mean # samples)</h3>
+<table>
+<tr><th class="l">Model</th>{{range $.BucketKeys}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Models}}
+<tr><td class="l">{{.Summary.Model}}</td>{{range .Buckets}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- range .Models}}
+{{- if .Diag}}
+<details>
+<summary>Diagnostics: {{.Summary.Model}} (seed {{.Seed}})</summary>
+{{- with .Diag}}
+{{- if .Classes}}
+<table>
+<tr><th>Class</th><th>Precision</th><th>Recall</th><th>F1</th><th>Support</th></tr>
+{{- range $c, $m := .Classes}}
+<tr><td>{{$c}}</td><td>{{f3 $m.Precision}}</td><td>{{f3 $m.Recall}}</td><td>{{f3 $m.F1}}</td><td>{{$m.Support}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Confusion}}
+<p>Confusion matrix (rows = true, columns = predicted):</p>
+<table>
+<tr><th></th>{{range $c, $_ := .Confusion}}<th>{{$c}}</th>{{end}}</tr>
+{{- range $c, $row := .Confusion}}
+<tr><th>{{$c}}</th>{{range $row}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Worst}}
+<p>Worst samples:</p>
+<table>
+<tr><th>Index</th><th>Label</th><th>Predicted</th><th>Confidence</th><th>Loss</th></tr>
+{{- range .Worst}}
+<tr><td>{{.Index}}</td><td>{{.Label}}</td><td>{{.Predicted}}</td><td>{{f3 .Confidence}}</td><td>{{f3 .Loss}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</details>
+{{- end}}
+{{- end}}
+
+{{- range .Epochs}}
+<details>
+<summary>Epochs: {{.Model}}</summary>
+<table>
+<tr><th>Epoch</th>{{range .Metrics}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+</details>
+{{- end}}
+{{- end}}
+
+<p class="muted">Generated {{.Generated}} by <code>arena report</code>.</p>
+</body>
+</html>
+`
@@ -0,0 +1,183 @@
+// Package report turns the records of one run into a single self-contained
+// HTML file: run metadata and seed manifest, the configuration of every
+// model, metric tables, charts embedded as PNG, ADHD and per-class
+// diagnostics, the worst samples, and a comparison with a baseline run.
+//
+//	run, err := report.Find([]string{"replay6/results.jsonl"}, "20250612-101502")
+//	base, err := report.Find(paths, baselineID)
+//	err = report.Write(f, run, base) // base may be nil
+package report
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"arena/results"
+	"arena/rng"
+)
+
+// Run is everything recorded under one run ID.
+type Run struct {
+	ID, Experiment, Commit string
+	Seed                   int64 // master seed; 0 without a manifest
+	ConfigHashes           []string
+	Start, End             time.Time // first and last record
+	Source                 string    // the results file the records came from
+	Records                []results.Record
+	// Seeds is the run's seed manifest from the seeds.jsonl next to
+	// Source; nil when none matches.
+	Seeds *rng.Manifest
+}
+
+// Steps returns the run's steps in the order they first wrote a record.
+func (r *Run) Steps() []string {
+	var steps []string
+	seen := map[string]bool{}
+	for _, rec := range r.Records {
+		if !seen[rec.Step] {
+			seen[rec.Step] = true
+			steps = append(steps, rec.Step)
+		}
+	}
+	return steps
+}
+
+// StepRecords returns the records of one step.
+func (r *Run) StepRecords(step string) []results.Record {
+	var out []results.Record
+	for _, rec := range r.Records {
+		if rec.Step == step {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// Find loads the run whose ID is id, or starts with it, from the first of
+// paths that holds it. Unreadable or missing files are skipped.
+func Find(paths []string, id string) (*Run, error) {
+	if id == "" {
+		return nil, errors.New("empty run ID")
+	}
+	for _, path := range paths {
+		recs, err := results.Read(path)
+		if err != nil {
+			continue
+		}
+		ids := map[string]bool{}
+		for _, r := range recs {
+			if strings.HasPrefix(r.RunID, id) {
+				ids[r.RunID] = true
+			}
+		}
+		switch len(ids) {
+		case 0:
+			continue
+		case 1:
+		default:
+			return nil, fmt.Errorf("run ID %q is ambiguous in %s: %s", id, path, strings.Join(sortedSet(ids), ", "))
+		}
+		for full := range ids {
+			return load(path, full, recs), nil
+		}
+	}
+	return nil, fmt.Errorf("no run %q in %s", id, strings.Join(paths, ", "))
+}
+
+func load(path, id string, recs []results.Record) *Run {
+	run := &Run{ID: id, Source: path}
+	hashes := map[string]bool{}
+	for _, r := range recs {
+		if r.RunID != id {
+			continue
+		}
+		if len(run.Records) == 0 {
+			run.Experiment, run.Commit = r.Experiment, r.Commit
+			run.Start, run.End = r.Time, r.Time
+		}
+		if r.Time.Before(run.Start) {
+			run.Start = r.Time
+		}
+		if r.Time.After(run.End) {
+			run.End = r.Time
+		}
+		if r.ConfigHash != "" {
+			hashes[r.ConfigHash] = true
+		}
+		run.Records = append(run.Records, r)
+	}
+	run.ConfigHashes = sortedSet(hashes)
+	// Records carry each model's own seed; the master seed is in the
+	// manifest, which experiment.Run appends once the steps finish.
+	if ms, err := rng.ReadManifests(filepath.Join(filepath.Dir(path), rng.ManifestFile)); err == nil {
+		run.Seeds = manifestOf(run, ms)
+	}
+	if run.Seeds != nil {
+		run.Seed = run.Seeds.Master
+	}
+	return run
+}
+
+// manifestOf returns the manifest saved under the run's ID. Manifests
+// written before they carried run IDs are matched by guess: the first of
+// the run's experiment written after its last record, preferring one that
+// derived a seed the records used.
+func manifestOf(run *Run, ms []rng.Manifest) *rng.Manifest {
+	for i := range ms {
+		if ms[i].RunID == run.ID {
+			return &ms[i]
+		}
+	}
+	used := map[int64]bool{}
+	for _, r := range run.Records {
+		used[r.Seed] = true
+	}
+	var best *rng.Manifest
+	bestHit := false
+	for i := range ms {
+		m := &ms[i]
+		if m.RunID != "" || m.Experiment != run.Experiment || m.Time.Before(run.End.Add(-time.Second)) {
+			continue
+		}
+		hit := used[m.Master]
+		for _, v := range m.Streams {
+			hit = hit || used[v]
+		}
+		if best == nil || hit && !bestHit || hit == bestHit && m.Time.Before(best.Time) {
+			best, bestHit = m, hit
+		}
+	}
+	return best
+}
+
+// ResultsFiles lists the results.jsonl and results.csv files directly
+// inside root's subdirectories, for looking up run IDs.
+func ResultsFiles(root string) []string {
+	var paths []string
+	for _, name := range []string{"results.jsonl", "results.csv"} {
+		matches, _ := filepath.Glob(filepath.Join(root, "*", name))
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+	out := paths[:0]
+	for _, p := range paths {
+		if st, err := os.Stat(p); err == nil && st.Size() > 0 {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func sortedSet(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,106 @@
+package results
+
+import (
+	"sync"
+	"time"
+)
+
+// Recorder stamps records with its run and writes them to a sink. It keeps
+// a copy of everything written so a step can render its own tables. It is
+// safe for concurrent use.
+type Recorder struct {
+	run  Run
+	sink Sink
+
+	mu      sync.Mutex
+	step    string
+	records []Record
+}
+
+// NewRecorder writes records of run to sink. A nil sink only keeps records
+// in memory.
+func NewRecorder(sink Sink, run Run) *Recorder {
+	return &Recorder{run: run, sink: sink}
+}
+
+// Run returns the run the recorder stamps records with.
+func (r *Recorder) Run() Run { return r.run }
+
+// SetStep tags subsequent records with a step name.
+func (r *Recorder) SetStep(step string) {
+	r.mu.Lock()
+	r.step = step
+	r.mu.Unlock()
+}
+
+// Write fills in the run fields of rec (time, run ID, experiment, step,
+// commit, seed and config hash, unless already set) and stores it. A record
+// with Params but no ConfigHash is hashed by its Params.
+func (r *Recorder) Write(rec Record) error {
+	r.mu.Lock()
+	if rec.Time.IsZero() {
+		rec.Time = time.Now().UTC()
+	}
+	rec.RunID = r.run.ID
+	rec.Experiment = r.run.Experiment
+	rec.Commit = r.run.Commit
+	if rec.Step == "" {
+		rec.Step = r.step
+	}
+	if rec.Seed == 0 {
+		rec.Seed = r.run.Seed
+	}
+	if rec.ConfigHash == "" {
+		if rec.Params != nil {
+			rec.ConfigHash = Hash(rec.Params)
+		} else {
+			rec.ConfigHash = r.run.ConfigHash
+		}
+	}
+	r.records = append(r.records, rec)
+	r.mu.Unlock()
+
+	if r.sink == nil {
+		return nil
+	}
+	return r.sink.Write(rec)
+}
+
+// Epoch records metrics after one training epoch (1-based).
+func (r *Recorder) Epoch(model string, epoch int, metrics map[string]float64) error {
+	return r.Write(Record{Model: model, Kind: KindEpoch, Epoch: epoch, Metrics: metrics})
+}
+
+// Final records a finished model. params describes the variant and may be
+// nil; adhd may be nil for models without an ADHD evaluation.
+func (r *Recorder) Final(model string, params map[string]any, adhd *ADHD, metrics map[string]float64) error {
+	return r.Write(Record{Model: model, Params: params, Kind: KindFinal, ADHD: adhd, Metrics: metrics})
+}
+
+// Records returns a copy of everything written so far.
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Record(nil), r.records...)
+}
+
+// StepRecords returns the records of the current step.
+func (r *Recorder) StepRecords() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Record
+	for _, rec := range r.records {
+		if rec.Step == r.step {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// Close closes the sink.
+func (r *Recorder) Close() error {
+	if r.sink == nil {
+		return nil
+	}
+	return r.sink.Close()
+}
@@ -0,0 +1,152 @@
+// Package results records experiment outcomes as structured data instead
+// of hand-formatted text. Every record carries the run ID, git commit, seed
+// and config hash it came from, so files from different days can be
+// concatenated, diffed and aggregated; human-readable tables are rendered
+// from the records afterwards.
+//
+// A run writes to one Sink (JSONL or CSV, picked by file extension):
+//
+//	sink, _ := results.Open("results.jsonl")
+//	rec := results.NewRecorder(sink, results.NewRun("replay6", seed, nil))
+//	rec.Epoch("static", 1, map[string]float64{"loss": 0.4})
+//	rec.Final("static", nil, results.ADHDOf(net), map[string]float64{"accuracy": 93.1})
+//	results.WriteSummary(os.Stdout, "MaxReplay", results.Summarize(rec.Records()))
+package results
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"time"
+
+	"paragon"
+)
+
+// Record kinds.
+const (
+	KindEpoch = "epoch" // metrics after one training epoch
+	KindFinal = "final" // metrics of a finished model
+)
+
+// BucketKeys are paragon's ADHD deviation buckets, in display order.
+var BucketKeys = []string{"0-10%", "10-20%", "20-30%", "30-40%", "40-50%", "50-100%", "100%+"}
+
+// Record is one row of results.
+type Record struct {
+	Time        time.Time          `json:"time"`
+	RunID       string             `json:"run_id"`
+	Experiment  string             `json:"experiment"`
+	Step        string             `json:"step,omitempty"`
+	Commit      string             `json:"commit,omitempty"`
+	Seed        int64              `json:"seed"`
+	ConfigHash  string             `json:"config_hash,omitempty"`
+	Model       string             `json:"model"` // variant within the step
+	Params      map[string]any     `json:"params,omitempty"`
+	Kind        string             `json:"kind"`
+	Epoch       int                `json:"epoch,omitempty"`
+	Metrics     map[string]float64 `json:"metrics,omitempty"`
+	ADHD        *ADHD              `json:"adhd,omitempty"`
+	Samples     []string           `json:"samples,omitempty"`     // generated text and the like; JSONL only
+	Diagnostics *Diagnostics       `json:"diagnostics,omitempty"` // JSONL only
+}
+
+// ADHD is paragon's evaluation score and its deviation bucket counts.
+type ADHD struct {
+	Score   float64        `json:"score"`
+	Buckets map[string]int `json:"buckets,omitempty"`
+}
+
+// Diagnostics is the detail behind a final record's metrics, kept for
+// reports: per-class scores, the confusion matrix and the samples with the
+// highest loss.
+type Diagnostics struct {
+	Classes   []ClassMetrics `json:"classes,omitempty"`
+	Confusion [][]int        `json:"confusion,omitempty"` // [true][predicted]
+	Worst     []SampleError  `json:"worst,omitempty"`     // highest loss first
+}
+
+// ClassMetrics are one class's precision, recall and F1.
+type ClassMetrics struct {
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Support   int     `json:"support"`
+}
+
+// SampleError is one sample of the evaluated set with its loss. Worst
+// lists the highest-loss ones, which may include correctly classified but
+// unconfident samples.
+type SampleError struct {
+	Index      int     `json:"index"`
+	Label      int     `json:"label"`
+	Predicted  int     `json:"predicted"`
+	Confidence float64 `json:"confidence"` // probability of the predicted class
+	Loss       float64 `json:"loss"`       // -ln p(label)
+}
+
+// ADHDOf copies net.Performance after EvaluateModel.
+func ADHDOf[T paragon.Numeric](net *paragon.Network[T]) *ADHD {
+	if net.Performance == nil {
+		return nil
+	}
+	a := &ADHD{Score: net.Performance.Score, Buckets: map[string]int{}}
+	for k, b := range net.Performance.Buckets {
+		a.Buckets[k] = b.Count
+	}
+	return a
+}
+
+// Run identifies one invocation of an experiment.
+type Run struct {
+	ID         string
+	Experiment string
+	Commit     string
+	Seed       int64
+	ConfigHash string
+}
+
+// NewRun returns a run with a fresh ID and the current git commit. config
+// may be nil; otherwise its hash becomes the run's default ConfigHash.
+func NewRun(experiment string, seed int64, config any) Run {
+	r := Run{ID: NewRunID(), Experiment: experiment, Commit: GitCommit(), Seed: seed}
+	if config != nil {
+		r.ConfigHash = Hash(config)
+	}
+	return r
+}
+
+// NewRunID returns a sortable, practically unique run ID such as
+// 20250612-101502-3fa9c1.
+func NewRunID() string {
+	var b [3]byte
+	rand.Read(b[:])
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b[:])
+}
+
+// GitCommit returns the short HEAD commit of the working directory, with a
+// "+dirty" suffix when there are uncommitted changes, or "" outside git.
+func GitCommit() string {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	commit := strings.TrimSpace(string(out))
+	if st, err := exec.Command("git", "status", "--porcelain").Output(); err == nil && len(st) > 0 {
+		commit += "+dirty"
+	}
+	return commit
+}
+
+// Hash returns the first 12 hex digits of the SHA-256 of v's JSON form.
+// Map keys are sorted by encoding/json, so equal configs hash equally.
+func Hash(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,303 @@
+package results
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink stores records.
+type Sink interface {
+	Write(r Record) error
+	Close() error
+}
+
+// Open appends to path as JSONL (.jsonl, .json) or CSV (.csv).
+func Open(path string) (Sink, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".json":
+		return OpenJSONL(path)
+	case ".csv":
+		return OpenCSV(path)
+	}
+	return nil, fmt.Errorf("%s: unknown results format (want .jsonl or .csv)", path)
+}
+
+// Read loads every record from a JSONL or CSV results file.
+func Read(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".json":
+		return ReadJSONL(f)
+	case ".csv":
+		return ReadCSV(f)
+	}
+	return nil, fmt.Errorf("%s: unknown results format (want .jsonl or .csv)", path)
+}
+
+// ---------------------------------------------------------------- JSONL
+
+// JSONL writes one JSON object per line.
+type JSONL struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenJSONL appends to path, creating it if needed.
+func OpenJSONL(path string) (*JSONL, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONL{f: f}, nil
+}
+
+// Write appends r as one line. Each line is written whole so a crashed run
+// leaves every finished record readable.
+func (s *JSONL) Write(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.f.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file.
+func (s *JSONL) Close() error { return s.f.Close() }
+
+// ReadJSONL decodes records line by line, skipping blank lines.
+func ReadJSONL(r io.Reader) ([]Record, error) {
+	var out []Record
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		out = append(out, rec)
+	}
+	return out, sc.Err()
+}
+
+// ---------------------------------------------------------------- CSV
+
+// csvHeader is the long ("tidy") layout: one row per metric, so runs with
+// different metrics share a file and load straight into a dataframe.
+// ADHD appears as the metrics "adhd" and "adhd_bucket:<range>". A record
+// without metrics is one row with metric and value empty. Samples and
+// diagnostics are not written.
+var csvHeader = []string{
+	"time", "run_id", "experiment", "step", "commit", "seed", "config_hash",
+	"model", "params", "kind", "epoch", "metric", "value",
+}
+
+const bucketMetric = "adhd_bucket:"
+
+// CSV writes records in the long layout of csvHeader.
+type CSV struct {
+	mu sync.Mutex
+	f  *os.File
+	w  *csv.Writer
+}
+
+// OpenCSV appends to path, writing the header if the file is new or empty.
+func OpenCSV(path string) (*CSV, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	s := &CSV{f: f, w: csv.NewWriter(f)}
+	if st.Size() == 0 {
+		if err := s.w.Write(csvHeader); err != nil {
+			f.Close()
+			return nil, err
+		}
+		s.w.Flush()
+	}
+	return s, nil
+}
+
+// Write appends one row per metric of r, or one empty row if it has none.
+func (s *CSV) Write(r Record) error {
+	params := ""
+	if len(r.Params) > 0 {
+		data, err := json.Marshal(r.Params)
+		if err != nil {
+			return err
+		}
+		params = string(data)
+	}
+	prefix := []string{
+		r.Time.Format(time.RFC3339Nano), r.RunID, r.Experiment, r.Step, r.Commit,
+		strconv.FormatInt(r.Seed, 10), r.ConfigHash, r.Model, params, r.Kind, strconv.Itoa(r.Epoch),
+	}
+	row := func(metric string, v float64) []string {
+		return append(append([]string(nil), prefix...), metric, strconv.FormatFloat(v, 'g', -1, 64))
+	}
+
+	var rows [][]string
+	if r.ADHD != nil {
+		rows = append(rows, row("adhd", r.ADHD.Score))
+		for _, k := range sortedKeys(r.ADHD.Buckets) {
+			rows = append(rows, row(bucketMetric+k, float64(r.ADHD.Buckets[k])))
+		}
+	}
+	for _, k := range sortedKeys(r.Metrics) {
+		rows = append(rows, row(k, r.Metrics[k]))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, append(append([]string(nil), prefix...), "", ""))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.w.WriteAll(rows); err != nil {
+		return err
+	}
+	return s.w.Error()
+}
+
+// Close flushes and closes the file.
+func (s *CSV) Close() error {
+	s.mu.Lock()
+	s.w.Flush()
+	err := s.w.Error()
+	s.mu.Unlock()
+	if cerr := s.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// ReadCSV reassembles records from long-layout rows. Consecutive rows that
+// agree on everything but metric and value form one record.
+func ReadCSV(r io.Reader) ([]Record, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[h] = i
+	}
+	for _, h := range csvHeader {
+		if _, ok := col[h]; !ok {
+			return nil, fmt.Errorf("csv header lacks %q", h)
+		}
+	}
+
+	var out []Record
+	lastKey := ""
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		key := strings.Join(row[:col["metric"]], "\x00")
+		if key != lastKey || len(out) == 0 {
+			rec, err := recordFromRow(row, col)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			out = append(out, rec)
+			lastKey = key
+		}
+		rec := &out[len(out)-1]
+		if row[col["metric"]] == "" {
+			continue // a record without metrics
+		}
+		v, err := strconv.ParseFloat(row[col["value"]], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		switch metric := row[col["metric"]]; {
+		case metric == "adhd":
+			if rec.ADHD == nil {
+				rec.ADHD = &ADHD{}
+			}
+			rec.ADHD.Score = v
+		case strings.HasPrefix(metric, bucketMetric):
+			if rec.ADHD == nil {
+				rec.ADHD = &ADHD{}
+			}
+			if rec.ADHD.Buckets == nil {
+				rec.ADHD.Buckets = map[string]int{}
+			}
+			rec.ADHD.Buckets[strings.TrimPrefix(metric, bucketMetric)] = int(v)
+		default:
+			if rec.Metrics == nil {
+				rec.Metrics = map[string]float64{}
+			}
+			rec.Metrics[metric] = v
+		}
+	}
+}
+
+func recordFromRow(row []string, col map[string]int) (Record, error) {
+	var rec Record
+	var err error
+	if rec.Time, err = time.Parse(time.RFC3339Nano, row[col["time"]]); err != nil {
+		return rec, err
+	}
+	if rec.Seed, err = strconv.ParseInt(row[col["seed"]], 10, 64); err != nil {
+		return rec, err
+	}
+	if rec.Epoch, err = strconv.Atoi(row[col["epoch"]]); err != nil {
+		return rec, err
+	}
+	rec.RunID = row[col["run_id"]]
+	rec.Experiment = row[col["experiment"]]
+	rec.Step = row[col["step"]]
+	rec.Commit = row[col["commit"]]
+	rec.ConfigHash = row[col["config_hash"]]
+	rec.Model = row[col["model"]]
+	rec.Kind = row[col["kind"]]
+	if p := row[col["params"]]; p != "" {
+		if err := json.Unmarshal([]byte(p), &rec.Params); err != nil {
+			return rec, errors.New("params: " + err.Error())
+		}
+	}
+	return rec, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,255 @@
+package results
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"arena/stats"
+)
+
+// Stat summarizes repeated measurements.
+type Stat struct {
+	N         int
+	Mean, Std float64 // Std is the sample standard deviation
+	Min, Max  float64
+}
+
+func statOf(xs []float64) Stat {
+	s := Stat{N: len(xs)}
+	if s.N == 0 {
+		return s
+	}
+	s.Min, s.Max = xs[0], xs[0]
+	for _, x := range xs {
+		s.Mean += x
+		s.Min = math.Min(s.Min, x)
+		s.Max = math.Max(s.Max, x)
+	}
+	s.Mean /= float64(s.N)
+	if s.N > 1 {
+		var ss float64
+		for _, x := range xs {
+			ss += (x - s.Mean) * (x - s.Mean)
+		}
+		s.Std = math.Sqrt(ss / float64(s.N-1))
+	}
+	return s
+}
+
+// String formats as "mean ± std", or just the mean for a single value.
+func (s Stat) String() string {
+	if s.N <= 1 {
+		return fmt.Sprintf("%.2f", s.Mean)
+	}
+	return fmt.Sprintf("%.2f ± %.2f", s.Mean, s.Std)
+}
+
+// Summary aggregates the final records of one model within a step.
+type Summary struct {
+	Step, Model string
+	N           int
+	ADHD        Stat
+	Metrics     map[string]Stat
+	Buckets     map[string]float64 // mean count per ADHD bucket
+}
+
+// Summarize groups final records by step and model, in first-seen order.
+func Summarize(records []Record) []Summary {
+	type acc struct {
+		adhd    []float64
+		metrics map[string][]float64
+		buckets map[string]float64
+		n       int
+	}
+	var order []string
+	groups := map[string]*acc{}
+	names := map[string][2]string{}
+	for _, r := range records {
+		if r.Kind != KindFinal {
+			continue
+		}
+		key := r.Step + "\x00" + r.Model
+		g, ok := groups[key]
+		if !ok {
+			g = &acc{metrics: map[string][]float64{}, buckets: map[string]float64{}}
+			groups[key] = g
+			names[key] = [2]string{r.Step, r.Model}
+			order = append(order, key)
+		}
+		g.n++
+		if r.ADHD != nil {
+			g.adhd = append(g.adhd, r.ADHD.Score)
+			for k, v := range r.ADHD.Buckets {
+				g.buckets[k] += float64(v)
+			}
+		}
+		for k, v := range r.Metrics {
+			g.metrics[k] = append(g.metrics[k], v)
+		}
+	}
+
+	out := make([]Summary, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		s := Summary{Step: names[key][0], Model: names[key][1], N: g.n, ADHD: statOf(g.adhd),
+			Metrics: map[string]Stat{}, Buckets: map[string]float64{}}
+		for k, xs := range g.metrics {
+			s.Metrics[k] = statOf(xs)
+		}
+		if len(g.adhd) > 0 {
+			for k, v := range g.buckets {
+				s.Buckets[k] = v / float64(len(g.adhd))
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// Samples collects metric ("adhd" for the ADHD score) from the final
+// records of each model, keyed by seed so models trained from the same seeds
+// are compared pairwise. Models come in first-seen order.
+func Samples(records []Record, metric string) []stats.Sample {
+	var out []stats.Sample
+	idx := map[string]int{}
+	for _, r := range records {
+		if r.Kind != KindFinal {
+			continue
+		}
+		v, ok := r.Metrics[metric]
+		if metric == "adhd" && r.ADHD != nil {
+			v, ok = r.ADHD.Score, true
+		}
+		if !ok {
+			continue
+		}
+		i, seen := idx[r.Model]
+		if !seen {
+			i = len(out)
+			idx[r.Model] = i
+			out = append(out, stats.Sample{Name: r.Model})
+		}
+		out[i].Values = append(out[i].Values, v)
+		out[i].Keys = append(out[i].Keys, r.Seed)
+	}
+	return out
+}
+
+// WriteSummary renders summaries as a table: one row per model with ADHD
+// and every metric as mean ± std, then the mean ADHD bucket counts.
+func WriteSummary(w io.Writer, title string, sums []Summary) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	if len(sums) == 0 {
+		_, err := fmt.Fprintln(w, "(no results)")
+		return err
+	}
+
+	metricSet := map[string]bool{}
+	hasADHD, hasBuckets := false, false
+	for _, s := range sums {
+		for k := range s.Metrics {
+			metricSet[k] = true
+		}
+		hasADHD = hasADHD || s.ADHD.N > 0
+		hasBuckets = hasBuckets || len(s.Buckets) > 0
+	}
+	metrics := sortedKeys(metricSet)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	head := []string{"Model", "N"}
+	if hasADHD {
+		head = append(head, "ADHD")
+	}
+	head = append(head, metrics...)
+	fmt.Fprintln(tw, row(head))
+	for _, s := range sums {
+		cells := []string{s.Model, fmt.Sprint(s.N)}
+		if hasADHD {
+			cells = append(cells, s.ADHD.String())
+		}
+		for _, m := range metrics {
+			if st, ok := s.Metrics[m]; ok {
+				cells = append(cells, st.String())
+			} else {
+				cells = append(cells, "-")
+			}
+		}
+		fmt.Fprintln(tw, row(cells))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if hasBuckets {
+		fmt.Fprintln(w, "\nDeviation buckets (mean # samples):")
+		tw = tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+		head := []string{"Bucket"}
+		for _, s := range sums {
+			head = append(head, s.Model)
+		}
+		fmt.Fprintln(tw, row(head))
+		for _, k := range BucketKeys {
+			cells := []string{k}
+			for _, s := range sums {
+				cells = append(cells, fmt.Sprintf("%.1f", s.Buckets[k]))
+			}
+			fmt.Fprintln(tw, row(cells))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, strings.Repeat("=", 30+len(title)))
+	return err
+}
+
+// WriteEpochs renders the epoch records of each model as its own table,
+// with any samples printed under their epoch.
+func WriteEpochs(w io.Writer, title string, records []Record) error {
+	var models []string
+	byModel := map[string][]Record{}
+	for _, r := range records {
+		if r.Kind != KindEpoch {
+			continue
+		}
+		if _, ok := byModel[r.Model]; !ok {
+			models = append(models, r.Model)
+		}
+		byModel[r.Model] = append(byModel[r.Model], r)
+	}
+
+	for _, m := range models {
+		recs := byModel[m]
+		sort.SliceStable(recs, func(i, j int) bool { return recs[i].Epoch < recs[j].Epoch })
+		metricSet := map[string]bool{}
+		for _, r := range recs {
+			for k := range r.Metrics {
+				metricSet[k] = true
+			}
+		}
+		metrics := sortedKeys(metricSet)
+
+		fmt.Fprintf(w, "\n========= %s (%s) =========\n", title, m)
+		for _, r := range recs {
+			cells := []string{fmt.Sprintf("epoch %d", r.Epoch)}
+			for _, k := range metrics {
+				if v, ok := r.Metrics[k]; ok {
+					cells = append(cells, fmt.Sprintf("%s=%.4g", k, v))
+				}
+			}
+			fmt.Fprintln(w, strings.Join(cells, " | "))
+			if len(r.Samples) > 0 {
+				fmt.Fprintln(w, strings.Join(r.Samples, "\n---\n"))
+			}
+		}
+	}
+	return nil
+}
+
+func row(cells []string) string {
+	return " " + strings.Join(cells, "\t ") + "\t"
+}
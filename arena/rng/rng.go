@@ -0,0 +1,202 @@
+// Package rng derives every random stream of a run from one master seed, so
+// any reported number can be reproduced by rerunning with the same seed.
+//
+// Each use of randomness gets its own named stream. Streams are independent
+// of each other and of the order in which they are requested, so adding a
+// shuffle to one step does not change the weights of another:
+//
+//	seeds := rng.FromEnv()                   // ARENA_SEED, or the clock
+//	split := seeds.Rand(rng.Split)
+//	run := seeds.Subf("h=%d/run=%d", h, i)   // per-run family of streams
+//	net := paragon.NewNetwork[float32](sizes, acts, fc, run.Seed(rng.Init))
+//	perm := run.Rand(rng.Shuffle).Perm(len(trainX))
+//	defer seeds.Save("replay6", "")          // append to seeds.jsonl
+//
+// Every derived seed is remembered and written to the run's manifest.
+package rng
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Conventional stream names.
+const (
+	Split   = "split"   // train/validation/test splits
+	Init    = "init"    // weight initialisation
+	Noise   = "noise"   // noise schedules, random inputs and targets
+	Shuffle = "shuffle" // per-epoch sample order
+	Global  = "global"  // math/rand's global source, for code that uses it
+)
+
+// EnvVar holds a master seed to reproduce a run.
+const EnvVar = "ARENA_SEED"
+
+// ManifestFile is where Save appends manifests, relative to the working
+// directory.
+const ManifestFile = "seeds.jsonl"
+
+// Seeds derives named seeds from a master seed. Seeds returned by Sub share
+// their parent's manifest. It is safe for concurrent use.
+type Seeds struct {
+	master int64
+	prefix string
+	log    *streamLog
+}
+
+type streamLog struct {
+	mu      sync.Mutex
+	root    int64
+	streams map[string]int64
+}
+
+// New returns the streams of master.
+func New(master int64) *Seeds {
+	return &Seeds{master: master, log: &streamLog{root: master, streams: map[string]int64{}}}
+}
+
+// EnvSeed returns the seed in $ARENA_SEED, or the current time when it is
+// unset or malformed.
+func EnvSeed() int64 {
+	if v := os.Getenv(EnvVar); v != "" {
+		if s, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return s
+		}
+		fmt.Fprintf(os.Stderr, "rng: ignoring malformed %s=%q\n", EnvVar, v)
+	}
+	return time.Now().UnixNano()
+}
+
+// FromEnv returns New(EnvSeed()) and prints how to reproduce the run.
+func FromEnv() *Seeds {
+	s := New(EnvSeed())
+	fmt.Printf("🎲 seed %d (rerun with %s=%d)\n", s.master, EnvVar, s.master)
+	return s
+}
+
+// Master returns the seed everything else is derived from.
+func (s *Seeds) Master() int64 { return s.master }
+
+// Seed returns the seed of the named stream. The same master and name
+// always give the same seed.
+func (s *Seeds) Seed(name string) int64 {
+	v := derive(s.master, name)
+	s.log.record(s.prefix+name, v)
+	return v
+}
+
+// Rand returns a generator for the named stream. Each call starts the
+// stream afresh; keep the result to draw a sequence.
+func (s *Seeds) Rand(name string) *rand.Rand {
+	return rand.New(rand.NewSource(s.Seed(name)))
+}
+
+// Sub returns a family of streams derived from the named seed, for one run
+// or variant inside a sweep. Its streams are recorded as "name/stream".
+func (s *Seeds) Sub(name string) *Seeds {
+	return &Seeds{master: s.Seed(name), prefix: s.prefix + name + "/", log: s.log}
+}
+
+// Subf is Sub with a name formatted from format and args.
+func (s *Seeds) Subf(format string, args ...any) *Seeds {
+	return s.Sub(fmt.Sprintf(format, args...))
+}
+
+// Streams returns every seed derived so far, keyed by full stream name.
+func (s *Seeds) Streams() map[string]int64 {
+	s.log.mu.Lock()
+	defer s.log.mu.Unlock()
+	out := make(map[string]int64, len(s.log.streams))
+	for k, v := range s.log.streams {
+		out[k] = v
+	}
+	return out
+}
+
+func (l *streamLog) record(name string, v int64) {
+	l.mu.Lock()
+	l.streams[name] = v
+	l.mu.Unlock()
+}
+
+// derive mixes the stream name into the master seed with splitmix64, so
+// neighbouring masters and similar names still give unrelated seeds.
+func derive(master int64, name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	x := uint64(master) ^ h.Sum64()
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return int64(x >> 1) // non-negative, as rand.NewSource users expect
+}
+
+// Manifest records the seeds of one run. RunID, when set, is the ID the
+// run's records carry in results.jsonl.
+type Manifest struct {
+	Time       time.Time        `json:"time"`
+	RunID      string           `json:"run_id,omitempty"`
+	Experiment string           `json:"experiment"`
+	Step       string           `json:"step,omitempty"`
+	Master     int64            `json:"master"`
+	Streams    map[string]int64 `json:"streams,omitempty"`
+}
+
+// Manifest returns the root master seed and every stream derived so far.
+func (s *Seeds) Manifest(experiment, step string) Manifest {
+	return Manifest{
+		Time:       time.Now().UTC(),
+		Experiment: experiment,
+		Step:       step,
+		Master:     s.log.root,
+		Streams:    s.Streams(),
+	}
+}
+
+// Save appends the manifest to ManifestFile.
+func (s *Seeds) Save(experiment, step string) error {
+	return AppendManifest(ManifestFile, s.Manifest(experiment, step))
+}
+
+// AppendManifest appends m to path as one JSON line.
+func AppendManifest(path string, m Manifest) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadManifests reads every manifest appended to path.
+func ReadManifests(path string) ([]Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []Manifest
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var m Manifest
+		if err := dec.Decode(&m); err != nil {
+			return out, fmt.Errorf("%s: manifest %d: %w", path, len(out)+1, err)
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
@@ -0,0 +1,120 @@
+package search
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Trial is one point trained for one budget.
+type Trial struct {
+	Time    time.Time `json:"time"`
+	Seed    int64     `json:"seed"` // the log's seed
+	Key     string    `json:"key"`
+	Point   Point     `json:"point"`
+	Budget  int       `json:"budget"`
+	Rung    int       `json:"rung"` // successive-halving round, 0 for Grid and Random
+	Score   float64   `json:"score"`
+	Err     string    `json:"err,omitempty"`
+	Seconds float64   `json:"seconds"`
+	Resumed bool      `json:"-"` // taken from the log instead of trained
+}
+
+// Log is the persistent trial log: one JSON trial per line, appended as
+// trials finish. Opening an existing log loads its successful trials so a
+// rerun can reuse them. It is safe for concurrent use.
+type Log struct {
+	mu   sync.Mutex
+	f    *os.File
+	seed int64
+	done map[string]Trial // by key@budget
+}
+
+// OpenLog opens or creates the trial log at path for a search run with
+// seed, the master seed its objective and sampler derive from. Trials are
+// only reusable under the seed that produced them, so a log written with
+// another seed is an error rather than a silent restart.
+func OpenLog(path string, seed int64) (*Log, error) {
+	l := &Log{seed: seed, done: map[string]Trial{}}
+	if f, err := os.Open(path); err == nil {
+		sc := bufio.NewScanner(f)
+		sc.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+		for line := 1; sc.Scan(); line++ {
+			if len(sc.Bytes()) == 0 {
+				continue
+			}
+			var t Trial
+			if err := json.Unmarshal(sc.Bytes(), &t); err != nil {
+				// A crash can leave the last line half-written.
+				fmt.Fprintf(os.Stderr, "search: %s:%d: skipping unreadable trial\n", path, line)
+				continue
+			}
+			if t.Seed != seed {
+				f.Close()
+				return nil, fmt.Errorf("search: %s was written with seed %d, not %d; resume with seed %d or use another log",
+					path, t.Seed, seed, t.Seed)
+			}
+			if t.Err == "" {
+				l.done[logKey(t.Key, t.Budget)] = t
+			}
+		}
+		err = sc.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	l.f = f
+	return l, nil
+}
+
+// Len returns the number of successful trials known to the log.
+func (l *Log) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.done)
+}
+
+// lookup returns the logged result of key at budget.
+func (l *Log) lookup(key string, budget int) (Trial, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, ok := l.done[logKey(key, budget)]
+	return t, ok
+}
+
+// add appends t as one line.
+func (l *Log) add(t Trial) error {
+	t.Seed = l.seed
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if t.Err == "" {
+		l.done[logKey(t.Key, t.Budget)] = t
+	}
+	return nil
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	return l.f.Close()
+}
+
+func logKey(key string, budget int) string {
+	return fmt.Sprintf("%s@%d", key, budget)
+}
@@ -0,0 +1,257 @@
+package search
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Objective trains p for budget units (epochs, usually) and returns a score
+// to maximise; return the negated loss to minimise a loss. It is called
+// from several goroutines at once.
+type Objective func(p Point, budget int) (float64, error)
+
+// Search runs trials of an objective.
+type Search struct {
+	Objective Objective
+	Workers   int         // trials in parallel; 0 means runtime.NumCPU()
+	Log       *Log        // optional; finished trials are appended and reused
+	OnTrial   func(Trial) // optional progress hook, called as trials finish
+}
+
+// Evaluate trains every point for budget and returns the trials in point
+// order. Points already in the log at that budget are not retrained.
+// Failed objectives are recorded in Trial.Err rather than stopping the
+// search; the error is only for a log that cannot be written.
+func (s *Search) Evaluate(points []Point, budget int) ([]Trial, error) {
+	return s.run(points, budget, 0)
+}
+
+func (s *Search) run(points []Point, budget, rung int) ([]Trial, error) {
+	trials := make([]Trial, len(points))
+	workers := s.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	jobs := make(chan int)
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		logErr error
+	)
+	for w := 0; w < min(workers, len(points)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				t := s.trial(points[i], budget, rung)
+				if s.Log != nil && !t.Resumed {
+					if err := s.Log.add(t); err != nil {
+						mu.Lock()
+						logErr = err
+						mu.Unlock()
+					}
+				}
+				trials[i] = t
+				if s.OnTrial != nil {
+					mu.Lock()
+					s.OnTrial(t)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range points {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if logErr != nil {
+		return trials, fmt.Errorf("writing trial log: %w", logErr)
+	}
+	return trials, nil
+}
+
+func (s *Search) trial(p Point, budget, rung int) Trial {
+	key := p.Key()
+	if s.Log != nil {
+		if t, ok := s.Log.lookup(key, budget); ok {
+			return Trial{Time: t.Time, Key: key, Point: p, Budget: budget, Rung: rung,
+				Score: t.Score, Seconds: t.Seconds, Resumed: true}
+		}
+	}
+	t := Trial{Key: key, Point: p, Budget: budget, Rung: rung}
+	start := time.Now()
+	score, err := s.Objective(p, budget)
+	t.Time, t.Seconds = time.Now().UTC(), time.Since(start).Seconds()
+	switch {
+	case err != nil:
+		t.Err = err.Error()
+	case math.IsNaN(score) || math.IsInf(score, 0):
+		t.Err = fmt.Sprintf("score is %v", score)
+	default:
+		t.Score = score
+	}
+	return t
+}
+
+// Grid trains every grid point of space (steps values per real dimension)
+// for budget.
+func (s *Search) Grid(space Space, steps, budget int) ([]Trial, error) {
+	if err := space.Validate(); err != nil {
+		return nil, err
+	}
+	return s.Evaluate(space.Grid(steps), budget)
+}
+
+// Random trains n points drawn from space with r for budget. Resuming needs
+// the same r seed so the same points are drawn.
+func (s *Search) Random(space Space, n, budget int, r *rand.Rand) ([]Trial, error) {
+	if err := space.Validate(); err != nil {
+		return nil, err
+	}
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = space.Sample(r)
+	}
+	return s.Evaluate(points, budget)
+}
+
+// SuccessiveHalving trains every point for minBudget, keeps the best 1/eta,
+// multiplies the budget by eta and repeats until one point is left or the
+// budget reaches maxBudget. Each round retrains from scratch, so the
+// objective need not checkpoint. It returns the trials of every round.
+func (s *Search) SuccessiveHalving(points []Point, minBudget, maxBudget, eta int) ([]Trial, error) {
+	if minBudget < 1 || maxBudget < minBudget || eta < 2 {
+		return nil, fmt.Errorf("successive halving needs 1 <= minBudget <= maxBudget and eta >= 2 (got %d, %d, %d)",
+			minBudget, maxBudget, eta)
+	}
+	var all []Trial
+	budget := minBudget
+	for rung := 0; len(points) > 0; rung++ {
+		trials, err := s.run(points, budget, rung)
+		all = append(all, trials...)
+		if err != nil {
+			return all, err
+		}
+		if len(points) == 1 || budget >= maxBudget {
+			return all, nil
+		}
+		ranked := rank(trials)
+		keep := max(1, len(ranked)/eta)
+		points = points[:0:0]
+		for _, t := range ranked[:min(keep, len(ranked))] {
+			points = append(points, t.Point)
+		}
+		budget = min(budget*eta, maxBudget)
+	}
+	return all, nil
+}
+
+// Hyperband runs successive halving in brackets that trade the number of
+// points against their starting budget (Li et al., 2018), drawing points
+// from space with r. maxBudget is the most any one trial gets.
+func (s *Search) Hyperband(space Space, maxBudget, eta int, r *rand.Rand) ([]Trial, error) {
+	if err := space.Validate(); err != nil {
+		return nil, err
+	}
+	if maxBudget < 1 || eta < 2 {
+		return nil, fmt.Errorf("hyperband needs maxBudget >= 1 and eta >= 2 (got %d, %d)", maxBudget, eta)
+	}
+	sMax := 0
+	for b := maxBudget; b >= eta; b /= eta {
+		sMax++
+	}
+	var all []Trial
+	for sb := sMax; sb >= 0; sb-- {
+		n := int(math.Ceil(float64(sMax+1) / float64(sb+1) * math.Pow(float64(eta), float64(sb))))
+		minBudget := max(1, maxBudget/int(math.Pow(float64(eta), float64(sb))))
+		points := make([]Point, n)
+		for i := range points {
+			points[i] = space.Sample(r)
+		}
+		trials, err := s.SuccessiveHalving(points, minBudget, maxBudget, eta)
+		all = append(all, trials...)
+		if err != nil {
+			return all, err
+		}
+	}
+	return all, nil
+}
+
+// rank returns the successful trials, best first.
+func rank(trials []Trial) []Trial {
+	var ok []Trial
+	for _, t := range trials {
+		if t.Err == "" {
+			ok = append(ok, t)
+		}
+	}
+	sort.SliceStable(ok, func(i, j int) bool { return ok[i].Score > ok[j].Score })
+	return ok
+}
+
+// Best returns the highest-scoring successful trial at the largest budget
+// any successful trial reached, so short trials that happened to score
+// well do not win over fully trained ones.
+func Best(trials []Trial) (Trial, bool) {
+	top := -1
+	for _, t := range trials {
+		if t.Err == "" && t.Budget > top {
+			top = t.Budget
+		}
+	}
+	var at []Trial
+	for _, t := range trials {
+		if t.Budget == top {
+			at = append(at, t)
+		}
+	}
+	ranked := rank(at)
+	if len(ranked) == 0 {
+		return Trial{}, false
+	}
+	return ranked[0], true
+}
+
+// WriteTrials renders the n best trials, largest budget first, as a table.
+// n <= 0 writes them all.
+func WriteTrials(w io.Writer, title string, trials []Trial, n int) error {
+	ranked := append([]Trial(nil), trials...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Budget != ranked[j].Budget {
+			return ranked[i].Budget > ranked[j].Budget
+		}
+		if (ranked[i].Err == "") != (ranked[j].Err == "") {
+			return ranked[i].Err == ""
+		}
+		return ranked[i].Score > ranked[j].Score
+	})
+	if n > 0 && n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	resumed := 0
+	for _, t := range trials {
+		if t.Resumed {
+			resumed++
+		}
+	}
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	fmt.Fprintf(w, "%d trials (%d from the trial log)\n", len(trials), resumed)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " Point\t Budget\t Rung\t Score\t Time\t")
+	for _, t := range ranked {
+		score := fmt.Sprintf("%.2f", t.Score)
+		if t.Err != "" {
+			score = "error: " + t.Err
+		}
+		fmt.Fprintf(tw, " %s\t %d\t %d\t %s\t %.0fs\t\n", t.Key, t.Budget, t.Rung, score, t.Seconds)
+	}
+	return tw.Flush()
+}
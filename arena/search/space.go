@@ -0,0 +1,274 @@
+// Package search tunes experiment settings over a typed parameter space.
+// Instead of nested loops that train every combination to completion, an
+// experiment describes its space, an objective that trains one point for a
+// budget (usually epochs) and returns a score, and a scheduler:
+//
+//	space := search.Space{
+//		search.Int("max_replay", 1, 4),
+//		search.Choice("phase", "before", "after"),
+//		search.Float("threshold", 0.3, 0.7),
+//	}
+//	s := &search.Search{Objective: train, Log: log}
+//	trials, err := s.Hyperband(space, 8, 3, seeds.Rand("search"))
+//	best, _ := search.Best(trials)
+//
+// Dimensions that only matter for some values of another are marked with
+// When and left out of the other points, so they neither multiply the grid
+// nor tell equivalent points apart:
+//
+//	search.Choice("mode", "static", "dynamic"),
+//	search.Float("threshold", 0.3, 0.7).When("mode", "dynamic"),
+//
+// Grid and Random train every point for the full budget; SuccessiveHalving
+// and Hyperband train many points briefly and only the best for long.
+// Trials run on a worker pool sized by CPU count, and every finished trial
+// is appended to the trial log with the search's seed, so an interrupted
+// search rerun with the same seed skips what it already did; the log
+// refuses to resume under another seed.
+package search
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of a dimension.
+type Kind int
+
+const (
+	IntKind    Kind = iota // integers in [Min, Max]
+	FloatKind              // reals in [Min, Max]
+	ChoiceKind             // one of Values
+)
+
+// Dim is one dimension of a space.
+type Dim struct {
+	Name     string
+	Kind     Kind
+	Min, Max float64
+	Log      bool  // sample and grid on a log scale (FloatKind)
+	Values   []any // ChoiceKind
+
+	// Set by When: the dimension only exists when Parent is one of
+	// ParentValues.
+	Parent       string
+	ParentValues []any
+}
+
+// Int is an integer dimension in [lo, hi].
+func Int(name string, lo, hi int) Dim {
+	return Dim{Name: name, Kind: IntKind, Min: float64(lo), Max: float64(hi)}
+}
+
+// Float is a real dimension in [lo, hi].
+func Float(name string, lo, hi float64) Dim {
+	return Dim{Name: name, Kind: FloatKind, Min: lo, Max: hi}
+}
+
+// LogFloat is a real dimension in [lo, hi] searched on a log scale, for
+// learning rates and the like. lo must be positive.
+func LogFloat(name string, lo, hi float64) Dim {
+	return Dim{Name: name, Kind: FloatKind, Min: lo, Max: hi, Log: true}
+}
+
+// Choice is a categorical dimension.
+func Choice(name string, values ...any) Dim {
+	return Dim{Name: name, Kind: ChoiceKind, Values: values}
+}
+
+// When makes d conditional on an earlier dimension: points include d only
+// when name takes one of values.
+func (d Dim) When(name string, values ...any) Dim {
+	d.Parent, d.ParentValues = name, values
+	return d
+}
+
+// active reports whether d belongs in p, given p's earlier dimensions.
+func (d Dim) active(p Point) bool {
+	if d.Parent == "" {
+		return true
+	}
+	v, ok := p[d.Parent]
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(d.ParentValues, func(w any) bool { return fmt.Sprint(w) == fmt.Sprint(v) })
+}
+
+// sample draws one value.
+func (d Dim) sample(r *rand.Rand) any {
+	switch d.Kind {
+	case IntKind:
+		return int(d.Min) + r.Intn(int(d.Max)-int(d.Min)+1)
+	case FloatKind:
+		if d.Log {
+			return math.Exp(math.Log(d.Min) + r.Float64()*(math.Log(d.Max)-math.Log(d.Min)))
+		}
+		return d.Min + r.Float64()*(d.Max-d.Min)
+	default:
+		return d.Values[r.Intn(len(d.Values))]
+	}
+}
+
+// grid returns every integer or choice, or steps evenly spaced reals
+// including both ends.
+func (d Dim) grid(steps int) []any {
+	var out []any
+	switch d.Kind {
+	case IntKind:
+		for v := int(d.Min); v <= int(d.Max); v++ {
+			out = append(out, v)
+		}
+	case FloatKind:
+		if steps < 2 || d.Min == d.Max {
+			return []any{d.Min}
+		}
+		for i := 0; i < steps; i++ {
+			t := float64(i) / float64(steps-1)
+			if d.Log {
+				out = append(out, math.Exp(math.Log(d.Min)+t*(math.Log(d.Max)-math.Log(d.Min))))
+			} else {
+				out = append(out, d.Min+t*(d.Max-d.Min))
+			}
+		}
+	default:
+		out = append(out, d.Values...)
+	}
+	return out
+}
+
+// Space is a list of dimensions.
+type Space []Dim
+
+// Validate checks names are unique, ranges are usable and conditions name
+// an earlier dimension.
+func (s Space) Validate() error {
+	seen := map[string]bool{}
+	for _, d := range s {
+		if d.Name == "" {
+			return fmt.Errorf("dimension without a name")
+		}
+		if seen[d.Name] {
+			return fmt.Errorf("dimension %s listed twice", d.Name)
+		}
+		if d.Parent != "" && !seen[d.Parent] {
+			return fmt.Errorf("%s: depends on %s, which is not listed before it", d.Name, d.Parent)
+		}
+		seen[d.Name] = true
+		switch {
+		case d.Kind == ChoiceKind && len(d.Values) == 0:
+			return fmt.Errorf("%s: no choices", d.Name)
+		case d.Kind != ChoiceKind && d.Min > d.Max:
+			return fmt.Errorf("%s: min %v above max %v", d.Name, d.Min, d.Max)
+		case d.Log && d.Min <= 0:
+			return fmt.Errorf("%s: log scale needs a positive min", d.Name)
+		}
+	}
+	return nil
+}
+
+// Sample draws one point uniformly (log-uniformly for LogFloat), leaving
+// out inactive dimensions.
+func (s Space) Sample(r *rand.Rand) Point {
+	p := Point{}
+	for _, d := range s {
+		if d.active(p) {
+			p[d.Name] = d.sample(r)
+		}
+	}
+	return p
+}
+
+// Grid returns every combination of the dimensions' grid values, real
+// dimensions contributing steps values each, in a stable order (last
+// dimension fastest). Inactive dimensions add no combinations.
+func (s Space) Grid(steps int) []Point {
+	values := make([][]any, len(s))
+	for i, d := range s {
+		values[i] = d.grid(steps)
+	}
+	out := []Point{{}}
+	for i, d := range s {
+		var next []Point
+		for _, p := range out {
+			if !d.active(p) {
+				next = append(next, p)
+				continue
+			}
+			for _, v := range values[i] {
+				q := p.clone()
+				q[d.Name] = v
+				next = append(next, q)
+			}
+		}
+		out = next
+	}
+	return out
+}
+
+// Point is one setting of every dimension.
+type Point map[string]any
+
+func (p Point) clone() Point {
+	q := make(Point, len(p))
+	for k, v := range p {
+		q[k] = v
+	}
+	return q
+}
+
+// Int returns an integer value; points read back from a trial log hold
+// float64s, which are converted.
+func (p Point) Int(name string) int {
+	switch v := p[name].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// Float returns a real value.
+func (p Point) Float(name string) float64 {
+	switch v := p[name].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return 0
+}
+
+// String returns a choice as a string.
+func (p Point) String(name string) string {
+	if v, ok := p[name]; ok {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// Key is a stable "name=value,..." label, used as the point's identity in
+// the trial log. Reals are written in full, so distinct points never share
+// a key.
+func (p Point) Key() string {
+	names := make([]string, 0, len(p))
+	for n := range p {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, n := range names {
+		v := p[n]
+		if f, ok := v.(float64); ok {
+			v = strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		parts[i] = fmt.Sprintf("%s=%v", n, v)
+	}
+	return strings.Join(parts, ",")
+}
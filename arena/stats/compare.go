@@ -0,0 +1,176 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+)
+
+// Sample is one group of runs, e.g. every dynamic-replay model of a
+// benchmark. Keys, when set, identify the seed of each value; groups that
+// share keys are also compared pairwise.
+type Sample struct {
+	Name   string
+	Values []float64
+	Keys   []int64
+}
+
+// Comparison of one sample against a baseline.
+type Comparison struct {
+	Base, Other   string
+	NBase, NOther int
+	MeanBase      float64
+	MeanOther     float64
+	CIOther       Interval // of the other sample's mean
+	Diff          float64  // MeanOther - MeanBase
+	DiffCI        Interval // Welch interval of Diff
+	Welch         Test
+	MannWhitney   Test
+	CohenD        float64 // other vs base
+	CliffsDelta   float64 // other vs base
+
+	// Set when the samples share at least two keys.
+	Pairs    int
+	PairedT  *Test
+	Wilcoxon *Test
+	PairedD  float64
+}
+
+// Compare tests other against base at the given confidence level.
+func Compare(base, other Sample, level float64) Comparison {
+	mb := Mean(base.Values)
+	mo, ci := MeanCI(other.Values, level)
+	c := Comparison{
+		Base: base.Name, Other: other.Name,
+		NBase: len(base.Values), NOther: len(other.Values),
+		MeanBase: mb, MeanOther: mo, CIOther: ci,
+		Diff:        mo - mb,
+		DiffCI:      WelchCI(other.Values, base.Values, level),
+		Welch:       Welch(other.Values, base.Values),
+		MannWhitney: MannWhitney(other.Values, base.Values),
+		CohenD:      CohenD(other.Values, base.Values),
+		CliffsDelta: CliffsDelta(other.Values, base.Values),
+		PairedD:     math.NaN(),
+	}
+	if a, b := Pair(other, base); len(a) >= 2 {
+		pt, w := PairedT(a, b), Wilcoxon(a, b)
+		c.Pairs, c.PairedT, c.Wilcoxon, c.PairedD = len(a), &pt, &w, PairedD(a, b)
+	}
+	return c
+}
+
+// Pair returns the values of a and b that share a key, aligned by key in
+// a's order. A key repeated within a sample pairs its first occurrence.
+func Pair(a, b Sample) ([]float64, []float64) {
+	if len(a.Keys) != len(a.Values) || len(b.Keys) != len(b.Values) {
+		return nil, nil
+	}
+	byKey := map[int64]float64{}
+	for i, k := range b.Keys {
+		if _, ok := byKey[k]; !ok {
+			byKey[k] = b.Values[i]
+		}
+	}
+	var xa, xb []float64
+	seen := map[int64]bool{}
+	for i, k := range a.Keys {
+		if v, ok := byKey[k]; ok && !seen[k] {
+			seen[k] = true
+			xa = append(xa, a.Values[i])
+			xb = append(xb, v)
+		}
+	}
+	return xa, xb
+}
+
+// Primary is the comparison's headline p-value: the paired t-test when the
+// samples share seeds, Welch's otherwise.
+func (c Comparison) Primary() float64 {
+	if c.PairedT != nil {
+		return c.PairedT.P
+	}
+	return c.Welch.P
+}
+
+// WriteComparisons renders every sample after the first against the first
+// as a table: mean with confidence interval, difference, Welch and
+// Mann-Whitney p-values, effect sizes, the paired test when seeds are
+// shared, and the primary p-values Holm-adjusted across the table's
+// comparisons. p-values below 0.05 are starred.
+func WriteComparisons(w io.Writer, title, metric string, samples []Sample, level float64) error {
+	fmt.Fprintf(w, "\n============== %s: %s ==============\n", title, metric)
+	if len(samples) == 0 {
+		_, err := fmt.Fprintln(w, "(no samples)")
+		return err
+	}
+	base := samples[0]
+	pct := fmt.Sprintf("%g%% CI", level*100)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, row("Model", "N", "Mean", pct, "Δ vs "+base.Name, "Δ "+pct,
+		"Welch p", "MWU p", "d", "δ", "paired p (n)", "Holm p"))
+
+	m, ci := MeanCI(base.Values, level)
+	fmt.Fprintln(tw, row(base.Name, fmt.Sprint(len(base.Values)), num(m), interval(ci),
+		"-", "-", "-", "-", "-", "-", "-", "-"))
+	cs := make([]Comparison, len(samples)-1)
+	ps := make([]float64, len(cs))
+	for i, s := range samples[1:] {
+		cs[i] = Compare(base, s, level)
+		ps[i] = cs[i].Primary()
+	}
+	holm := Holm(ps)
+	for i, c := range cs {
+		paired := "-"
+		if c.PairedT != nil {
+			paired = fmt.Sprintf("%s (%d)", pval(c.PairedT.P), c.Pairs)
+		}
+		fmt.Fprintln(tw, row(c.Other, fmt.Sprint(c.NOther), num(c.MeanOther), interval(c.CIOther),
+			signed(c.Diff), interval(c.DiffCI), pval(c.Welch.P), pval(c.MannWhitney.P),
+			num(c.CohenD), num(c.CliffsDelta), paired, pval(holm[i])))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, "d = Cohen's d, δ = Cliff's delta; paired = paired t-test over shared seeds;\n"+
+		"Holm = paired p (Welch p without shared seeds) adjusted for the table's comparisons")
+	return err
+}
+
+func row(cells ...string) string {
+	return " " + strings.Join(cells, "\t ") + "\t"
+}
+
+func num(x float64) string {
+	if math.IsNaN(x) {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", x)
+}
+
+func signed(x float64) string {
+	if math.IsNaN(x) {
+		return "-"
+	}
+	return fmt.Sprintf("%+.2f", x)
+}
+
+func interval(ci Interval) string {
+	if math.IsNaN(ci.Lo) {
+		return "-"
+	}
+	return fmt.Sprintf("[%.2f, %.2f]", ci.Lo, ci.Hi)
+}
+
+func pval(p float64) string {
+	switch {
+	case math.IsNaN(p):
+		return "-"
+	case p < 0.001:
+		return "<0.001*"
+	case p < 0.05:
+		return fmt.Sprintf("%.3f*", p)
+	}
+	return fmt.Sprintf("%.3f", p)
+}
@@ -0,0 +1,284 @@
+// Package stats answers whether one set of runs really beats another:
+// confidence intervals, Welch's t-test, the Mann-Whitney U test, effect
+// sizes, paired tests for runs that share seeds, and Holm's correction for
+// families of comparisons.
+//
+// All tests are two-sided. Rank tests use the normal approximation with tie
+// correction, which is adequate from about eight runs per group.
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Mean returns the arithmetic mean, or NaN for no values.
+func Mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	var s float64
+	for _, x := range xs {
+		s += x
+	}
+	return s / float64(len(xs))
+}
+
+// Variance returns the sample (n-1) variance, or NaN for fewer than two
+// values.
+func Variance(xs []float64) float64 {
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+	m := Mean(xs)
+	var ss float64
+	for _, x := range xs {
+		ss += (x - m) * (x - m)
+	}
+	return ss / float64(len(xs)-1)
+}
+
+// StdDev returns the sample standard deviation.
+func StdDev(xs []float64) float64 { return math.Sqrt(Variance(xs)) }
+
+// Interval is a two-sided confidence interval.
+type Interval struct {
+	Lo, Hi float64
+	Level  float64 // e.g. 0.95
+}
+
+// MeanCI returns the mean and its Student-t confidence interval.
+func MeanCI(xs []float64, level float64) (float64, Interval) {
+	m := Mean(xs)
+	n := float64(len(xs))
+	if len(xs) < 2 {
+		return m, Interval{math.NaN(), math.NaN(), level}
+	}
+	h := TQuantile(1-(1-level)/2, n-1) * StdDev(xs) / math.Sqrt(n)
+	return m, Interval{m - h, m + h, level}
+}
+
+// Test is the outcome of a hypothesis test.
+type Test struct {
+	Name string
+	Stat float64 // t, U, W or z depending on the test
+	DF   float64 // degrees of freedom, 0 when not applicable
+	P    float64 // two-sided p-value
+}
+
+// Significant reports whether P is below alpha.
+func (t Test) Significant(alpha float64) bool { return t.P < alpha }
+
+// Welch compares the means of a and b without assuming equal variances.
+func Welch(a, b []float64) Test {
+	na, nb := float64(len(a)), float64(len(b))
+	if len(a) < 2 || len(b) < 2 {
+		return Test{Name: "welch", Stat: math.NaN(), P: math.NaN()}
+	}
+	va, vb := Variance(a)/na, Variance(b)/nb
+	se := math.Sqrt(va + vb)
+	if se == 0 {
+		return degenerate("welch", Mean(a)-Mean(b))
+	}
+	t := (Mean(a) - Mean(b)) / se
+	df := (va + vb) * (va + vb) / (va*va/(na-1) + vb*vb/(nb-1))
+	return Test{Name: "welch", Stat: t, DF: df, P: 2 * (1 - TCDF(math.Abs(t), df))}
+}
+
+// WelchCI returns the confidence interval of mean(a) - mean(b) under the
+// Welch approximation.
+func WelchCI(a, b []float64, level float64) Interval {
+	w := Welch(a, b)
+	d := Mean(a) - Mean(b)
+	if math.IsNaN(w.Stat) || w.DF == 0 {
+		return Interval{math.NaN(), math.NaN(), level}
+	}
+	se := math.Sqrt(Variance(a)/float64(len(a)) + Variance(b)/float64(len(b)))
+	h := TQuantile(1-(1-level)/2, w.DF) * se
+	return Interval{d - h, d + h, level}
+}
+
+// MannWhitney tests whether values of a tend to be larger or smaller than
+// values of b. Stat is U for a.
+func MannWhitney(a, b []float64) Test {
+	na, nb := float64(len(a)), float64(len(b))
+	if len(a) == 0 || len(b) == 0 {
+		return Test{Name: "mann-whitney", Stat: math.NaN(), P: math.NaN()}
+	}
+	all := append(append([]float64(nil), a...), b...)
+	ranks, ties := rank(all)
+	var ra float64
+	for i := range a {
+		ra += ranks[i]
+	}
+	u := ra - na*(na+1)/2
+	n := na + nb
+	sigma := math.Sqrt(na * nb / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		return Test{Name: "mann-whitney", Stat: u, P: 1}
+	}
+	z := (math.Abs(u-na*nb/2) - 0.5) / sigma // continuity correction
+	return Test{Name: "mann-whitney", Stat: u, P: 2 * (1 - NormalCDF(math.Max(z, 0)))}
+}
+
+// PairedT tests whether the mean of a[i]-b[i] is zero.
+func PairedT(a, b []float64) Test {
+	d := diffs(a, b)
+	if len(d) < 2 {
+		return Test{Name: "paired-t", Stat: math.NaN(), P: math.NaN()}
+	}
+	se := StdDev(d) / math.Sqrt(float64(len(d)))
+	if se == 0 {
+		return degenerate("paired-t", Mean(d))
+	}
+	t := Mean(d) / se
+	df := float64(len(d) - 1)
+	return Test{Name: "paired-t", Stat: t, DF: df, P: 2 * (1 - TCDF(math.Abs(t), df))}
+}
+
+// Wilcoxon is the signed-rank test on a[i]-b[i]; zero differences are
+// dropped. Stat is W+, the rank sum of positive differences.
+func Wilcoxon(a, b []float64) Test {
+	var d, abs []float64
+	for _, x := range diffs(a, b) {
+		if x != 0 {
+			d = append(d, x)
+			abs = append(abs, math.Abs(x))
+		}
+	}
+	n := float64(len(d))
+	if len(d) == 0 {
+		return Test{Name: "wilcoxon", Stat: 0, P: 1}
+	}
+	ranks, ties := rank(abs)
+	var w float64
+	for i, x := range d {
+		if x > 0 {
+			w += ranks[i]
+		}
+	}
+	mu := n * (n + 1) / 4
+	sigma := math.Sqrt(n*(n+1)*(2*n+1)/24 - ties/48)
+	if sigma == 0 {
+		return Test{Name: "wilcoxon", Stat: w, P: 1}
+	}
+	z := (math.Abs(w-mu) - 0.5) / sigma
+	return Test{Name: "wilcoxon", Stat: w, P: 2 * (1 - NormalCDF(math.Max(z, 0)))}
+}
+
+// Holm adjusts a family of p-values for multiple comparisons with Holm's
+// step-down method, which controls the family-wise error rate like
+// Bonferroni but rejects more. The result is in the order of ps; NaNs are
+// left out of the family and kept.
+func Holm(ps []float64) []float64 {
+	var idx []int
+	for i, p := range ps {
+		if !math.IsNaN(p) {
+			idx = append(idx, i)
+		}
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return ps[idx[a]] < ps[idx[b]] })
+	out := make([]float64, len(ps))
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	run := 0.0
+	for rank, i := range idx {
+		run = math.Max(run, math.Min(1, float64(len(idx)-rank)*ps[i]))
+		out[i] = run
+	}
+	return out
+}
+
+// CohenD is the standardised mean difference of a and b using the pooled
+// standard deviation. Around 0.2 is small, 0.5 medium, 0.8 large.
+func CohenD(a, b []float64) float64 {
+	na, nb := float64(len(a)), float64(len(b))
+	if len(a) < 2 || len(b) < 2 {
+		return math.NaN()
+	}
+	pooled := math.Sqrt(((na-1)*Variance(a) + (nb-1)*Variance(b)) / (na + nb - 2))
+	if pooled == 0 {
+		return math.NaN()
+	}
+	return (Mean(a) - Mean(b)) / pooled
+}
+
+// HedgesG is CohenD with the small-sample bias correction.
+func HedgesG(a, b []float64) float64 {
+	n := float64(len(a) + len(b))
+	return CohenD(a, b) * (1 - 3/(4*n-9))
+}
+
+// CliffsDelta is P(a > b) - P(a < b) over all pairs, in [-1, 1]: the effect
+// size matching the Mann-Whitney test.
+func CliffsDelta(a, b []float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return math.NaN()
+	}
+	var gt, lt int
+	for _, x := range a {
+		for _, y := range b {
+			switch {
+			case x > y:
+				gt++
+			case x < y:
+				lt++
+			}
+		}
+	}
+	return float64(gt-lt) / float64(len(a)*len(b))
+}
+
+// PairedD is the mean of a[i]-b[i] over its standard deviation (Cohen's dz).
+func PairedD(a, b []float64) float64 {
+	d := diffs(a, b)
+	sd := StdDev(d)
+	if len(d) < 2 || sd == 0 {
+		return math.NaN()
+	}
+	return Mean(d) / sd
+}
+
+func diffs(a, b []float64) []float64 {
+	n := min(len(a), len(b))
+	d := make([]float64, n)
+	for i := range d {
+		d[i] = a[i] - b[i]
+	}
+	return d
+}
+
+// degenerate reports a test whose spread is zero: identical means are not
+// different at all, different means are as different as can be.
+func degenerate(name string, diff float64) Test {
+	if diff == 0 {
+		return Test{Name: name, Stat: 0, P: 1}
+	}
+	return Test{Name: name, Stat: math.Copysign(math.Inf(1), diff), P: 0}
+}
+
+// rank returns 1-based mid-ranks and the tie term sum(t^3 - t).
+func rank(xs []float64) ([]float64, float64) {
+	idx := make([]int, len(xs))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return xs[idx[i]] < xs[idx[j]] })
+	ranks := make([]float64, len(xs))
+	var ties float64
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && xs[idx[j+1]] == xs[idx[i]] {
+			j++
+		}
+		r := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			ranks[idx[k]] = r
+		}
+		t := float64(j - i + 1)
+		ties += t*t*t - t
+		i = j + 1
+	}
+	return ranks, ties
+}
@@ -0,0 +1,305 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"arena/results"
+	"arena/stats"
+)
+
+// Query selects stored records. Zero fields match everything.
+type Query struct {
+	Experiment string
+	Step       string
+	Commit     string            // prefix of the commit; dirty runs match too
+	Kind       string            // results.KindFinal when empty
+	Where      map[string]string // param = value, compared as printed
+	Since      time.Time
+}
+
+// Match reports whether r satisfies q.
+func (q Query) Match(r results.Record) bool {
+	kind := q.Kind
+	if kind == "" {
+		kind = results.KindFinal
+	}
+	if r.Kind != kind ||
+		q.Experiment != "" && r.Experiment != q.Experiment ||
+		q.Step != "" && r.Step != q.Step ||
+		q.Commit != "" && !sameCommit(r.Commit, q.Commit) ||
+		!q.Since.IsZero() && r.Time.Before(q.Since) {
+		return false
+	}
+	for k, want := range q.Where {
+		if paramString(r, k) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Query loads the matching records of every stored run, oldest run first.
+func (s *Store) Query(q Query) ([]results.Record, error) {
+	var out []results.Record
+	for _, e := range s.entries {
+		if q.Experiment != "" && e.Experiment != q.Experiment ||
+			q.Commit != "" && !sameCommit(e.Commit, q.Commit) ||
+			!q.Since.IsZero() && e.End.Before(q.Since) {
+			continue
+		}
+		recs, err := s.Records(e.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range recs {
+			if q.Match(r) {
+				out = append(out, r)
+			}
+		}
+	}
+	return out, nil
+}
+
+// LowerIsBetter reports whether smaller values of metric are better: the
+// losses, calibration errors, timings and compute counts.
+func LowerIsBetter(metric string) bool {
+	switch metric {
+	case "ece", "mce", "brier", "seconds":
+		return true
+	}
+	return strings.Contains(metric, "loss") || strings.HasSuffix(metric, "_macs")
+}
+
+// Standing is one configuration (experiment, step and model) aggregated
+// over every run that recorded it.
+type Standing struct {
+	Group                   string // the group-by param's value; "" without one
+	Experiment, Step, Model string
+	Params                  map[string]any // of the latest record
+	Metric                  string
+	N                       int // records
+	Runs                    int // distinct runs
+	Mean, Std               float64
+	Commit                  string // of the latest record
+	Last                    time.Time
+}
+
+// Best ranks configurations by their mean metric and keeps the top n of
+// each value of the groupBy param (all of them when groupBy is empty),
+// e.g. the best dynamic-replay setting per hidden depth. Records without
+// the metric, or without groupBy, are skipped.
+func Best(recs []results.Record, metric, groupBy string, n int) []Standing {
+	type acc struct {
+		s    Standing
+		vals []float64
+		runs map[string]bool
+	}
+	groups := map[string]*acc{}
+	var order []string
+	for _, r := range recs {
+		v, ok := value(r, metric)
+		if !ok {
+			continue
+		}
+		group := ""
+		if groupBy != "" {
+			if _, ok := r.Params[groupBy]; !ok {
+				continue
+			}
+			group = paramString(r, groupBy)
+		}
+		key := strings.Join([]string{group, r.Experiment, r.Step, r.Model}, "\x00")
+		a, ok := groups[key]
+		if !ok {
+			a = &acc{s: Standing{Group: group, Experiment: r.Experiment, Step: r.Step, Model: r.Model, Metric: metric},
+				runs: map[string]bool{}}
+			groups[key] = a
+			order = append(order, key)
+		}
+		a.vals = append(a.vals, v)
+		a.runs[r.RunID] = true
+		if !r.Time.Before(a.s.Last) {
+			a.s.Last, a.s.Commit, a.s.Params = r.Time, r.Commit, r.Params
+		}
+	}
+
+	byGroup := map[string][]Standing{}
+	var groupOrder []string
+	for _, key := range order {
+		a := groups[key]
+		a.s.N, a.s.Runs = len(a.vals), len(a.runs)
+		a.s.Mean, a.s.Std = stats.Mean(a.vals), 0
+		if len(a.vals) > 1 {
+			a.s.Std = stats.StdDev(a.vals)
+		}
+		if _, ok := byGroup[a.s.Group]; !ok {
+			groupOrder = append(groupOrder, a.s.Group)
+		}
+		byGroup[a.s.Group] = append(byGroup[a.s.Group], a.s)
+	}
+	sortGroups(groupOrder)
+
+	lower := LowerIsBetter(metric)
+	var out []Standing
+	for _, g := range groupOrder {
+		ss := byGroup[g]
+		sort.SliceStable(ss, func(i, j int) bool {
+			if lower {
+				return ss[i].Mean < ss[j].Mean
+			}
+			return ss[i].Mean > ss[j].Mean
+		})
+		if n > 0 && len(ss) > n {
+			ss = ss[:n]
+		}
+		out = append(out, ss...)
+	}
+	return out
+}
+
+// Change is how one configuration's metric moved from one commit to
+// another.
+type Change struct {
+	Experiment, Step, Model string
+	Metric                  string
+	NBase, NHead            int
+	MeanBase, MeanHead      float64
+	Diff                    float64 // head - base
+	P                       float64 // paired t-test when the runs share seeds, else Welch; NaN with too few runs
+	Worse                   bool    // in the metric's bad direction
+	Significant             bool    // P < alpha
+}
+
+// Regression reports a significant change for the worse.
+func (c Change) Regression() bool { return c.Worse && c.Significant }
+
+// Compare matches every configuration recorded at both commits and tests
+// the difference in metric. base and head are commit prefixes, as in
+// Query, and runs from a dirty work tree count as their commit's. Records
+// of other commits, or that match both, are ignored.
+func Compare(recs []results.Record, metric, base, head string, alpha float64) []Change {
+	type key struct{ exp, step, model string }
+	split := map[key][2][]results.Record{}
+	var order []key
+	for _, r := range recs {
+		isBase, isHead := sameCommit(r.Commit, base), sameCommit(r.Commit, head)
+		if isBase == isHead {
+			continue
+		}
+		side := 0
+		if isHead {
+			side = 1
+		}
+		k := key{r.Experiment, r.Step, r.Model}
+		sides, ok := split[k]
+		if !ok {
+			order = append(order, k)
+		}
+		sides[side] = append(sides[side], r)
+		split[k] = sides
+	}
+
+	lower := LowerIsBetter(metric)
+	var out []Change
+	for _, k := range order {
+		sides := split[k]
+		bs, hs := results.Samples(sides[0], metric), results.Samples(sides[1], metric)
+		if len(bs) == 0 || len(hs) == 0 {
+			continue
+		}
+		bs[0].Name, hs[0].Name = base, head
+		cmp := stats.Compare(bs[0], hs[0], 1-alpha)
+		c := Change{Experiment: k.exp, Step: k.step, Model: k.model, Metric: metric,
+			NBase: cmp.NBase, NHead: cmp.NOther, MeanBase: cmp.MeanBase, MeanHead: cmp.MeanOther,
+			Diff: cmp.Diff, P: math.NaN()}
+		switch {
+		case cmp.PairedT != nil:
+			c.P = cmp.PairedT.P
+		case cmp.NBase > 1 && cmp.NOther > 1:
+			c.P = cmp.Welch.P
+		}
+		c.Worse = c.Diff < 0 && !lower || c.Diff > 0 && lower
+		c.Significant = c.P < alpha
+		out = append(out, c)
+	}
+	return out
+}
+
+// LatestCommit returns the commit of the latest stored run of experiment,
+// without a "+dirty" suffix.
+func (s *Store) LatestCommit(experiment string) (string, bool) {
+	runs := s.Runs(experiment)
+	if len(runs) == 0 {
+		return "", false
+	}
+	return cleanCommit(runs[len(runs)-1].Commit), true
+}
+
+// PreviousCommit returns the commit of the latest run of experiment that
+// started before head's first run and was made at another commit, without
+// a "+dirty" suffix.
+func (s *Store) PreviousCommit(experiment, head string) (string, bool) {
+	runs := s.Runs(experiment)
+	for i, e := range runs {
+		if !sameCommit(e.Commit, head) {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if !sameCommit(runs[j].Commit, head) {
+				return cleanCommit(runs[j].Commit), true
+			}
+		}
+		break
+	}
+	return "", false
+}
+
+// cleanCommit drops the "+dirty" results.GitCommit adds for uncommitted
+// changes.
+func cleanCommit(c string) string { return strings.TrimSuffix(c, "+dirty") }
+
+// sameCommit reports whether commit, dirty or not, starts with prefix.
+func sameCommit(commit, prefix string) bool {
+	return prefix != "" && strings.HasPrefix(cleanCommit(commit), cleanCommit(prefix))
+}
+
+func value(r results.Record, metric string) (float64, bool) {
+	if metric == "adhd" {
+		if r.ADHD == nil {
+			return 0, false
+		}
+		return r.ADHD.Score, true
+	}
+	v, ok := r.Metrics[metric]
+	return v, ok
+}
+
+// paramString prints a param the same way whether it was written as an
+// int or read back from JSON as a float64.
+func paramString(r results.Record, name string) string {
+	v, ok := r.Params[name]
+	if !ok {
+		return ""
+	}
+	if f, ok := v.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return fmt.Sprint(int64(f))
+	}
+	return fmt.Sprint(v)
+}
+
+// sortGroups orders group values numerically when they all are numbers.
+func sortGroups(gs []string) {
+	sort.SliceStable(gs, func(i, j int) bool {
+		a, errA := strconv.ParseFloat(gs[i], 64)
+		b, errB := strconv.ParseFloat(gs[j], 64)
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return gs[i] < gs[j]
+	})
+}
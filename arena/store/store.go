@@ -0,0 +1,252 @@
+// Package store keeps every run's records in one place so results can be
+// compared across days and commits. It is plain files: an index of runs
+// (index.jsonl, one line per run) and the records of each run in
+// runs/<run-id>.jsonl. Experiments keep writing their own results files;
+// Import copies new runs in and is safe to repeat.
+//
+//	s, err := store.Open("runstore")
+//	added, err := s.Import("replay6/results.jsonl")
+//	recs, err := s.Query(store.Query{Experiment: "replay6", Where: map[string]string{"replay": "dynamic"}})
+//	best := store.Best(recs, "accuracy", "hidden")
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"arena/results"
+)
+
+const (
+	indexFile = "index.jsonl"
+	runsDir   = "runs"
+)
+
+// Entry describes one stored run.
+type Entry struct {
+	ID         string    `json:"id"`
+	Experiment string    `json:"experiment"`
+	Commit     string    `json:"commit,omitempty"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Steps      []string  `json:"steps,omitempty"`
+	Models     int       `json:"models"`  // distinct step/model pairs with a final record
+	Records    int       `json:"records"` // all records, epochs included
+	Source     string    `json:"source"`  // the results file it was imported from
+}
+
+// Store is an opened run store. It is not safe for concurrent use.
+type Store struct {
+	dir     string
+	entries []Entry // by Start
+	byID    map[string]int
+}
+
+// Open opens the store in dir, creating it if needed.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, runsDir), 0755); err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	s := &Store{dir: dir, byID: map[string]int{}}
+	f, err := os.Open(filepath.Join(dir, indexFile))
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("store: %s line %d: %w", indexFile, line, err)
+		}
+		s.entries = append(s.entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	s.sortEntries()
+	return s, nil
+}
+
+// Dir is the store's directory.
+func (s *Store) Dir() string { return s.dir }
+
+// Runs returns the stored runs of experiment ("" for all), oldest first.
+func (s *Store) Runs(experiment string) []Entry {
+	var out []Entry
+	for _, e := range s.entries {
+		if experiment == "" || e.Experiment == experiment {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Run returns the entry of a run ID.
+func (s *Store) Run(id string) (Entry, bool) {
+	i, ok := s.byID[id]
+	if !ok {
+		return Entry{}, false
+	}
+	return s.entries[i], true
+}
+
+// Import adds the runs in a results file (JSONL or CSV). A run already
+// stored is replaced only when the file holds more of its records, e.g.
+// because it was still going at the last import. It returns the runs
+// added or updated.
+func (s *Store) Import(path string) ([]Entry, error) {
+	recs, err := results.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	byRun := map[string][]results.Record{}
+	var order []string
+	for _, r := range recs {
+		if r.RunID == "" {
+			continue
+		}
+		if _, ok := byRun[r.RunID]; !ok {
+			order = append(order, r.RunID)
+		}
+		byRun[r.RunID] = append(byRun[r.RunID], r)
+	}
+
+	var changed []Entry
+	for _, id := range order {
+		rs := byRun[id]
+		if old, ok := s.Run(id); ok && old.Records >= len(rs) {
+			continue
+		}
+		if err := s.writeRun(id, rs); err != nil {
+			return changed, err
+		}
+		e := entryOf(id, rs, path)
+		if i, ok := s.byID[id]; ok {
+			s.entries[i] = e
+		} else {
+			s.entries = append(s.entries, e)
+		}
+		s.sortEntries()
+		changed = append(changed, e)
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	return changed, s.writeIndex()
+}
+
+// Records returns the stored records of one run.
+func (s *Store) Records(id string) ([]results.Record, error) {
+	if _, ok := s.byID[id]; !ok {
+		return nil, fmt.Errorf("store: no run %q", id)
+	}
+	f, err := os.Open(s.runPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	defer f.Close()
+	recs, err := results.ReadJSONL(f)
+	if err != nil {
+		return nil, fmt.Errorf("store: run %s: %w", id, err)
+	}
+	return recs, nil
+}
+
+func entryOf(id string, recs []results.Record, source string) Entry {
+	e := Entry{ID: id, Experiment: recs[0].Experiment, Commit: recs[0].Commit,
+		Start: recs[0].Time, End: recs[0].Time, Records: len(recs), Source: source}
+	steps := map[string]bool{}
+	models := map[[2]string]bool{}
+	for _, r := range recs {
+		if r.Time.Before(e.Start) {
+			e.Start = r.Time
+		}
+		if r.Time.After(e.End) {
+			e.End = r.Time
+		}
+		if !steps[r.Step] {
+			steps[r.Step] = true
+			e.Steps = append(e.Steps, r.Step)
+		}
+		if r.Kind == results.KindFinal {
+			models[[2]string{r.Step, r.Model}] = true
+		}
+	}
+	e.Models = len(models)
+	return e
+}
+
+func (s *Store) runPath(id string) string {
+	return filepath.Join(s.dir, runsDir, id+".jsonl")
+}
+
+func (s *Store) sortEntries() {
+	sort.SliceStable(s.entries, func(i, j int) bool { return s.entries[i].Start.Before(s.entries[j].Start) })
+	s.byID = make(map[string]int, len(s.entries))
+	for i, e := range s.entries {
+		s.byID[e.ID] = i
+	}
+}
+
+// writeRun replaces a run's records file.
+func (s *Store) writeRun(id string, recs []results.Record) error {
+	return writeAtomic(s.runPath(id), func(w *bufio.Writer) error {
+		enc := json.NewEncoder(w)
+		for _, r := range recs {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) writeIndex() error {
+	return writeAtomic(filepath.Join(s.dir, indexFile), func(w *bufio.Writer) error {
+		enc := json.NewEncoder(w)
+		for _, e := range s.entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeAtomic writes path through a temporary file, so a crash never
+// leaves a half-written index or run behind.
+func writeAtomic(path string, fill func(*bufio.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	w := bufio.NewWriter(tmp)
+	err = fill(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteRuns lists stored runs, one row each.
+func WriteRuns(w io.Writer, title string, runs []Entry) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " Run\t Experiment\t Commit\t Started\t Models\t Records\t Steps\t")
+	for _, e := range runs {
+		fmt.Fprintf(tw, " %s\t %s\t %s\t %s\t %d\t %d\t %s\t\n", e.ID, e.Experiment, orDash(e.Commit),
+			e.Start.Local().Format("2006-01-02 15:04"), e.Models, e.Records, strings.Join(e.Steps, ", "))
+	}
+	return tw.Flush()
+}
+
+// WriteBest renders a leaderboard from Best, with a rank within each
+// group.
+func WriteBest(w io.Writer, title, groupBy string, ss []Standing) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	if len(ss) == 0 {
+		_, err := fmt.Fprintln(w, "(no results)")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	head := " #\t Experiment\t Step\t Model\t " + ss[0].Metric + "\t N\t Runs\t Commit\t Params\t"
+	if groupBy != "" {
+		head = " " + groupBy + "\t" + head
+	}
+	fmt.Fprintln(tw, head)
+	rank, group := 0, ""
+	for i, s := range ss {
+		if i == 0 || s.Group != group {
+			rank, group = 0, s.Group
+		}
+		rank++
+		row := fmt.Sprintf(" %d\t %s\t %s\t %s\t %s\t %d\t %d\t %s\t %s\t", rank, s.Experiment, orDash(s.Step), s.Model,
+			meanStd(s.Mean, s.Std, s.N), s.N, s.Runs, orDash(s.Commit), params(s.Params))
+		if groupBy != "" {
+			row = " " + s.Group + "\t" + row
+		}
+		fmt.Fprintln(tw, row)
+	}
+	return tw.Flush()
+}
+
+// WriteChanges renders Compare's result. Unless all is set only changes
+// for the worse are listed; significant ones are marked.
+func WriteChanges(w io.Writer, title string, cs []Change, all bool) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " Experiment\t Step\t Model\t Base\t Head\t Δ\t p\t \t")
+	shown, regressions := 0, 0
+	for _, c := range cs {
+		if c.Regression() {
+			regressions++
+		}
+		if !all && !c.Worse {
+			continue
+		}
+		shown++
+		mark := ""
+		switch {
+		case c.Regression():
+			mark = "❌ regression"
+		case c.Significant:
+			mark = "✅ improvement"
+		}
+		p := "-"
+		if !math.IsNaN(c.P) {
+			p = fmt.Sprintf("%.3g", c.P)
+		}
+		fmt.Fprintf(tw, " %s\t %s\t %s\t %.3f (n=%d)\t %.3f (n=%d)\t %+.3f\t %s\t %s\t\n", c.Experiment, orDash(c.Step), c.Model,
+			c.MeanBase, c.NBase, c.MeanHead, c.NHead, c.Diff, p, mark)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d configurations compared, %d shown, %d significant regressions\n", len(cs), shown, regressions)
+	return err
+}
+
+func meanStd(mean, std float64, n int) string {
+	if n <= 1 {
+		return fmt.Sprintf("%.3f", mean)
+	}
+	return fmt.Sprintf("%.3f ± %.3f", mean, std)
+}
+
+func params(p map[string]any) string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, p[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
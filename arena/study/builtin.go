@@ -0,0 +1,48 @@
+package study
+
+import (
+	"math/rand"
+
+	"arena/datasets/cache"
+	"arena/datasets/mnist"
+	"arena/datasets/uci"
+	"arena/tasks"
+)
+
+// MNISTDir is where the mnist dataset unpacks its IDX files, relative to
+// the working directory, as in the MNIST experiments.
+const MNISTDir = "mnist_data"
+
+// SyntheticRows is how many samples each synthetic task generates.
+const SyntheticRows = 4000
+
+func init() {
+	Register(Dataset{Name: "mnist", Doc: "MNIST digits, 28x28, its own test split", Load: func(*rand.Rand) (Rows, error) {
+		if err := mnist.Ensure(MNISTDir); err != nil {
+			return Rows{}, err
+		}
+		x, y, err := mnist.Load(MNISTDir, mnist.Train, mnist.OneHot)
+		if err != nil {
+			return Rows{}, err
+		}
+		tx, ty, err := mnist.Load(MNISTDir, mnist.Test, mnist.OneHot)
+		if err != nil {
+			return Rows{}, err
+		}
+		return Rows{X: x, Y: y, TestX: tx, TestY: ty}, nil
+	}})
+	Register(Dataset{Name: "eeg-eye-state", Doc: "UCI EEG eye state, 14 channels, eyes open or closed", Load: func(*rand.Rand) (Rows, error) {
+		x, y, err := uci.EEGEyeState(cache.Default())
+		return Rows{X: x, Y: y}, err
+	}})
+	Register(Dataset{Name: "bank-marketing", Doc: "UCI bank marketing, 7 numeric columns, subscribed or not", Load: func(*rand.Rand) (Rows, error) {
+		x, y, err := uci.Bank(cache.Default())
+		return Rows{X: x, Y: y}, err
+	}})
+	for _, t := range tasks.Defaults() {
+		Register(Dataset{Name: "synthetic/" + t.Name(), Doc: t.Doc(), Load: func(r *rand.Rand) (Rows, error) {
+			x, y := t.Generate(SyntheticRows, r)
+			return Rows{X: x, Y: y}, nil
+		}})
+	}
+}
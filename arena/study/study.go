@@ -0,0 +1,265 @@
+// Package study applies one replay configuration matrix to every
+// registered dataset under one protocol: the same splits, caps, class
+// balancing, normalisation, network shape, training budget and seeds. It
+// answers whether a replay result holds beyond the dataset it was found
+// on.
+//
+//	scores, skipped, err := study.Run(study.Datasets(), tasks.DefaultModes(), study.Protocol{
+//		Options: tasks.Options{Seeds: seeds, Recorder: rec},
+//	})
+//	tasks.WriteTable(os.Stdout, "accuracy", "accuracy", scores)
+//	study.WriteVerdict(os.Stdout, "generality", "accuracy", scores, 0.05)
+//
+// MNIST, EEG eye state, bank marketing and the synthetic tasks are
+// registered; experiments can Register more.
+package study
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"arena/parallel"
+	"arena/rng"
+	"arena/tasks"
+	"paragon"
+)
+
+// Dataset is a registered dataset.
+type Dataset struct {
+	Name, Doc string
+	// Load returns the labelled rows: inputs [height][width] and one-hot
+	// targets [1][classes]. r is for generated data.
+	Load func(r *rand.Rand) (Rows, error)
+}
+
+// Rows is a loaded dataset. TestX is nil unless the dataset comes with a
+// fixed test split; the protocol splits one off otherwise.
+type Rows struct {
+	X, Y         [][][]float64
+	TestX, TestY [][][]float64
+}
+
+var registry []Dataset
+
+// Register adds a dataset. Names must be unique.
+func Register(d Dataset) {
+	if _, ok := Lookup(d.Name); ok {
+		panic("study: dataset registered twice: " + d.Name)
+	}
+	registry = append(registry, d)
+}
+
+// Datasets returns the registered datasets in registration order.
+func Datasets() []Dataset {
+	return append([]Dataset(nil), registry...)
+}
+
+// Lookup finds a dataset by name.
+func Lookup(name string) (Dataset, bool) {
+	for _, d := range registry {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Dataset{}, false
+}
+
+// Protocol is what every dataset goes through. Zero fields take the
+// defaults noted, and the training fields of Options take tasks'
+// defaults.
+type Protocol struct {
+	TestFraction float64 // held out when there is no fixed test split; 0.2
+	// MaxTrain and MaxTest cap the splits after balancing; 2000 and 1000.
+	MaxTrain, MaxTest int
+	// KeepImbalance skips downsampling every class to the rarest one.
+	KeepImbalance bool
+	// KeepScale skips min-max scaling each input with the training
+	// split's range.
+	KeepScale bool
+	tasks.Options
+}
+
+func (p *Protocol) defaults() {
+	if p.TestFraction == 0 {
+		p.TestFraction = 0.2
+	}
+	if p.MaxTrain == 0 {
+		p.MaxTrain = 2000
+	}
+	if p.MaxTest == 0 {
+		p.MaxTest = 1000
+	}
+	p.Options.Defaults()
+}
+
+// Prepare turns loaded rows into the protocol's train and test sets,
+// drawing splits and subsamples from streams.
+func Prepare(name string, rows Rows, p Protocol, streams *rng.Seeds) (tasks.Data, error) {
+	p.defaults()
+	if len(rows.X) == 0 || len(rows.X) != len(rows.Y) || len(rows.TestX) != len(rows.TestY) {
+		return tasks.Data{}, fmt.Errorf("study: %s: %d inputs, %d targets", name, len(rows.X), len(rows.Y))
+	}
+	r := streams.Rand(rng.Split)
+	trainX, trainY, testX, testY := rows.X, rows.Y, rows.TestX, rows.TestY
+	if testX == nil {
+		perm := r.Perm(len(trainX))
+		nTest := int(p.TestFraction * float64(len(perm)))
+		testX, testY = pick(rows.X, rows.Y, perm[:nTest])
+		trainX, trainY = pick(rows.X, rows.Y, perm[nTest:])
+	}
+	if !p.KeepImbalance {
+		trainX, trainY = balance(trainX, trainY, r)
+		testX, testY = balance(testX, testY, r)
+	}
+	trainX, trainY = limit(trainX, trainY, p.MaxTrain, r)
+	testX, testY = limit(testX, testY, p.MaxTest, r)
+	if len(trainX) == 0 || len(testX) == 0 {
+		return tasks.Data{}, fmt.Errorf("study: %s: empty split (%d train, %d test)", name, len(trainX), len(testX))
+	}
+	if !p.KeepScale {
+		trainX, testX = scale(trainX, testX)
+	}
+	return tasks.Data{Name: name, Width: len(trainX[0][0]), Height: len(trainX[0]), Classes: len(trainY[0][0]),
+		TrainX: trainX, TrainY: trainY, TestX: testX, TestY: testY}, nil
+}
+
+// Skipped is a dataset Run could not load or split.
+type Skipped struct {
+	Dataset string
+	Err     error
+}
+
+// Run loads each dataset once and trains every mode on it p.Runs times.
+// Run r of a dataset splits and initialises from
+// p.Seeds.Sub("dataset=<name>/run=<r>"), shared by every mode. Scores come
+// back ordered by dataset, mode and run, named after the dataset. A dataset
+// that fails to load or split is skipped, not fatal: it has no scores and
+// is reported in skipped.
+func Run(ds []Dataset, modes []tasks.Mode, p Protocol) (scores []tasks.Score, skipped []Skipped, err error) {
+	if p.Seeds == nil {
+		return nil, nil, errors.New("study: Protocol.Seeds is required")
+	}
+	p.defaults()
+	opts := p.Options
+
+	// data and streams hold the runs of the datasets that loaded and
+	// split: run r of the k-th is data[k*opts.Runs+r].
+	var data []tasks.Data
+	var streams []*rng.Seeds
+	for _, d := range ds {
+		fmt.Printf("📥 Loading %s …\n", d.Name)
+		rows, err := d.Load(p.Seeds.Subf("dataset=%s", d.Name).Rand(rng.Noise))
+		if err != nil {
+			fmt.Printf("⚠️ Skipping %s: %v\n", d.Name, err)
+			skipped = append(skipped, Skipped{d.Name, fmt.Errorf("study: %s: %w", d.Name, err)})
+			continue
+		}
+		// split every run before training any, so a bad split skips the
+		// whole dataset rather than some of its runs
+		runs := make([]tasks.Data, opts.Runs)
+		runStreams := make([]*rng.Seeds, opts.Runs)
+		for run := range runs {
+			runStreams[run] = p.Seeds.Subf("dataset=%s/run=%d", d.Name, run)
+			if runs[run], err = Prepare(d.Name, rows, p, runStreams[run]); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			fmt.Printf("⚠️ Skipping %s: %v\n", d.Name, err)
+			skipped = append(skipped, Skipped{d.Name, err})
+			continue
+		}
+		data = append(data, runs...)
+		streams = append(streams, runStreams...)
+	}
+
+	scores = make([]tasks.Score, len(data)*len(modes))
+	err = parallel.Do(len(scores), opts.Workers, func(i int) (err error) {
+		run, mi, k := i%opts.Runs, i/opts.Runs%len(modes), i/opts.Runs/len(modes)
+		j := k*opts.Runs + run
+		scores[i], err = tasks.Fit(data[j], modes[mi], run, streams[j], opts)
+		return err
+	})
+	return scores, skipped, err
+}
+
+func pick(xs, ys [][][]float64, idx []int) ([][][]float64, [][][]float64) {
+	px, py := make([][][]float64, len(idx)), make([][][]float64, len(idx))
+	for i, j := range idx {
+		px[i], py[i] = xs[j], ys[j]
+	}
+	return px, py
+}
+
+// balance downsamples every class to the size of the rarest present one,
+// in random order.
+func balance(xs, ys [][][]float64, r *rand.Rand) ([][][]float64, [][][]float64) {
+	byClass := map[int][]int{}
+	var order []int
+	for i, y := range ys {
+		c := paragon.ArgMax(y[0])
+		if _, ok := byClass[c]; !ok {
+			order = append(order, c)
+		}
+		byClass[c] = append(byClass[c], i)
+	}
+	n := len(ys)
+	for _, idx := range byClass {
+		n = min(n, len(idx))
+	}
+	var keep []int
+	for _, c := range order {
+		idx := byClass[c]
+		r.Shuffle(len(idx), func(i, j int) { idx[i], idx[j] = idx[j], idx[i] })
+		keep = append(keep, idx[:n]...)
+	}
+	r.Shuffle(len(keep), func(i, j int) { keep[i], keep[j] = keep[j], keep[i] })
+	return pick(xs, ys, keep)
+}
+
+// limit keeps a random n rows when there are more.
+func limit(xs, ys [][][]float64, n int, r *rand.Rand) ([][][]float64, [][][]float64) {
+	if len(xs) <= n {
+		return xs, ys
+	}
+	return pick(xs, ys, r.Perm(len(xs))[:n])
+}
+
+// scale min-max scales every input cell into [0,1] by the training
+// split's range, applied unchanged to the test split. The rows are
+// copied; loaded data stays untouched for the next run.
+func scale(train, test [][][]float64) ([][][]float64, [][][]float64) {
+	h, w := len(train[0]), len(train[0][0])
+	lo, hi := make([]float64, h*w), make([]float64, h*w)
+	for i, x := range train {
+		for y, row := range x {
+			for c, v := range row {
+				k := y*w + c
+				if i == 0 || v < lo[k] {
+					lo[k] = v
+				}
+				if i == 0 || v > hi[k] {
+					hi[k] = v
+				}
+			}
+		}
+	}
+	apply := func(xs [][][]float64) [][][]float64 {
+		out := make([][][]float64, len(xs))
+		for i, x := range xs {
+			out[i] = make([][]float64, len(x))
+			for y, row := range x {
+				out[i][y] = make([]float64, len(row))
+				for c, v := range row {
+					k := y*w + c
+					if span := hi[k] - lo[k]; span > 0 {
+						out[i][y][c] = (v - lo[k]) / span
+					}
+				}
+			}
+		}
+		return out
+	}
+	return apply(train), apply(test)
+}
@@ -0,0 +1,121 @@
+package study
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"arena/stats"
+	"arena/tasks"
+)
+
+// Verdict is how one mode did against the first mode across datasets.
+type Verdict struct {
+	Mode, Base         string
+	Better, Tie, Worse []string // datasets, by Holm-adjusted paired t-test at alpha
+	MeanGain           float64  // mean over datasets of the mean difference
+	MinGain, MaxGain   float64
+}
+
+// General reports whether the mode was significantly better everywhere.
+func (v Verdict) General() bool { return len(v.Tie) == 0 && len(v.Worse) == 0 && len(v.Better) > 0 }
+
+// Verdicts compares every mode with the first one on each dataset, pairing
+// runs by index (Run shares data and seeds across modes). The paired
+// t-test p-values are Holm-adjusted over every mode and dataset before
+// they are held against alpha, so more modes or datasets do not buy more
+// chances at a win.
+func Verdicts(metric string, scores []tasks.Score, alpha float64) []Verdict {
+	var datasets, modes []string
+	vals := map[[2]string][]float64{}
+	for _, s := range scores {
+		if !slices.Contains(datasets, s.Task) {
+			datasets = append(datasets, s.Task)
+		}
+		if !slices.Contains(modes, s.Mode) {
+			modes = append(modes, s.Mode)
+		}
+		k := [2]string{s.Task, s.Mode}
+		vals[k] = append(vals[k], s.Metrics[metric])
+	}
+
+	type test struct {
+		verdict int // index into out
+		dataset string
+		gain, p float64
+	}
+	var tests []test
+	var ps []float64
+	var out []Verdict
+	for _, mode := range modes[min(1, len(modes)):] {
+		v := Verdict{Mode: mode, Base: modes[0], MinGain: math.Inf(1), MaxGain: math.Inf(-1)}
+		var gains []float64
+		for _, d := range datasets {
+			base, xs := vals[[2]string{d, modes[0]}], vals[[2]string{d, mode}]
+			if len(base) == 0 || len(xs) == 0 {
+				continue
+			}
+			gain := stats.Mean(xs) - stats.Mean(base)
+			gains = append(gains, gain)
+			v.MinGain, v.MaxGain = math.Min(v.MinGain, gain), math.Max(v.MaxGain, gain)
+			p := math.NaN()
+			if len(xs) == len(base) && len(xs) > 1 {
+				p = stats.PairedT(base, xs).P
+			}
+			tests = append(tests, test{len(out), d, gain, p})
+			ps = append(ps, p)
+		}
+		if len(gains) > 0 {
+			v.MeanGain = stats.Mean(gains)
+		}
+		out = append(out, v)
+	}
+
+	holm := stats.Holm(ps)
+	for i, t := range tests {
+		v := &out[t.verdict]
+		switch p := holm[i]; {
+		case p < alpha && t.gain > 0:
+			v.Better = append(v.Better, t.dataset)
+		case p < alpha && t.gain < 0:
+			v.Worse = append(v.Worse, t.dataset)
+		default:
+			v.Tie = append(v.Tie, t.dataset)
+		}
+	}
+	return out
+}
+
+// WriteVerdict renders Verdicts: per mode, on how many datasets it beat,
+// matched or lost to the first mode, and which.
+func WriteVerdict(w io.Writer, title, metric string, scores []tasks.Score, alpha float64) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintf(tw, " Mode\t Better\t Tie\t Worse\t Mean gain\t Range\t Worse on\t\n")
+	vs := Verdicts(metric, scores, alpha)
+	for _, v := range vs {
+		worse := strings.Join(v.Worse, ", ")
+		if worse == "" {
+			worse = "-"
+		}
+		fmt.Fprintf(tw, " %s\t %d\t %d\t %d\t %+.2f\t %+.2f … %+.2f\t %s\t\n", v.Mode,
+			len(v.Better), len(v.Tie), len(v.Worse), v.MeanGain, v.MinGain, v.MaxGain, worse)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	general := false
+	for _, v := range vs {
+		if v.General() {
+			general = true
+			fmt.Fprintf(w, "%s beats %s on every dataset (Holm p < %.2f).\n", v.Mode, v.Base, alpha)
+		}
+	}
+	if !general && len(vs) > 0 {
+		fmt.Fprintf(w, "No mode beats %s on every dataset (Holm p < %.2f).\n", vs[0].Base, alpha)
+	}
+	return nil
+}
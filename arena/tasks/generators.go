@@ -0,0 +1,189 @@
+package tasks
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// SparseParity is replay3's hard sparse XOR, generalised: Bits cells of
+// the grid carry random bits and the label is their parity. Distractors
+// more cells carry random bits that do not count. The cells are fixed,
+// spread evenly over the grid in row-major order, parity bits first: for
+// the defaults (0,10), (2,6), (4,3), … as (row, column).
+type SparseParity struct {
+	Width, Height int
+	Bits          int
+	Distractors   int
+}
+
+func (t SparseParity) Name() string { return "sparse-parity" }
+
+func (t SparseParity) Doc() string {
+	return fmt.Sprintf("parity of %d fixed cells of a %dx%d grid, %d distractor cells", t.Bits, t.Width, t.Height, t.Distractors)
+}
+
+func (t SparseParity) Shape() (int, int, int) { return t.Width, t.Height, 2 }
+
+func (t SparseParity) Generate(n int, r *rand.Rand) ([][][]float64, [][][]float64) {
+	cells := t.Bits + t.Distractors
+	xs, ys := make([][][]float64, n), make([][][]float64, n)
+	for i := range xs {
+		in := grid(t.Width, t.Height)
+		label := 0
+		for c := 0; c < cells; c++ {
+			// Spread the cells over the grid, parity bits first.
+			p := (c*2 + 1) * t.Width * t.Height / (2 * cells)
+			bit := r.Intn(2)
+			in[p/t.Width][p%t.Width] = float64(bit)
+			if c < t.Bits {
+				label ^= bit
+			}
+		}
+		xs[i], ys[i] = in, oneHot(label, 2)
+	}
+	return xs, ys
+}
+
+// DelayedEcho is replay3's temporal echo as a detection task: a 1 x
+// Length sequence of noise holds a pulse and a second pulse later. The
+// label is 1 when the second pulse follows exactly Delay steps after the
+// first and 0 when it is off by one or two either way.
+type DelayedEcho struct {
+	Length int
+	Delay  int
+	Noise  float64 // amplitude of uniform background noise
+}
+
+func (t DelayedEcho) Name() string { return "delayed-echo" }
+
+func (t DelayedEcho) Doc() string {
+	return fmt.Sprintf("is the echo of a pulse exactly %d steps later, in %d steps of noise %.1f", t.Delay, t.Length, t.Noise)
+}
+
+func (t DelayedEcho) Shape() (int, int, int) { return t.Length, 1, 2 }
+
+func (t DelayedEcho) Generate(n int, r *rand.Rand) ([][][]float64, [][][]float64) {
+	xs, ys := make([][][]float64, n), make([][][]float64, n)
+	for i := range xs {
+		in := grid(t.Length, 1)
+		for j := range in[0] {
+			in[0][j] = r.Float64() * t.Noise
+		}
+		label := r.Intn(2)
+		delay := t.Delay
+		if label == 0 {
+			off := []int{-2, -1, 1, 2}[r.Intn(4)]
+			delay = max(1, delay+off)
+			if delay == t.Delay {
+				delay++
+			}
+		}
+		start := r.Intn(max(1, t.Length-delay))
+		in[0][start] = 1
+		in[0][min(start+delay, t.Length-1)] = 1
+		xs[i], ys[i] = in, oneHot(label, 2)
+	}
+	return xs, ys
+}
+
+// CopyRecall shows a sequence of Length symbols, one-hot per column, and
+// marks one position in an extra row; the label is the symbol at the
+// marked position.
+type CopyRecall struct {
+	Length  int
+	Symbols int
+}
+
+func (t CopyRecall) Name() string { return "copy-recall" }
+
+func (t CopyRecall) Doc() string {
+	return fmt.Sprintf("recall the marked one of %d symbols from an alphabet of %d", t.Length, t.Symbols)
+}
+
+func (t CopyRecall) Shape() (int, int, int) { return t.Length, t.Symbols + 1, t.Symbols }
+
+func (t CopyRecall) Generate(n int, r *rand.Rand) ([][][]float64, [][][]float64) {
+	xs, ys := make([][][]float64, n), make([][][]float64, n)
+	for i := range xs {
+		in := grid(t.Length, t.Symbols+1)
+		seq := make([]int, t.Length)
+		for j := range seq {
+			seq[j] = r.Intn(t.Symbols)
+			in[seq[j]][j] = 1
+		}
+		q := r.Intn(t.Length)
+		in[t.Symbols][q] = 1
+		xs[i], ys[i] = in, oneHot(seq[q], t.Symbols)
+	}
+	return xs, ys
+}
+
+// NoisyMajority hides Bits random ±1 values under Gaussian noise of
+// standard deviation Sigma; the label is the majority sign of the clean
+// values. Bits should be odd.
+type NoisyMajority struct {
+	Bits  int
+	Sigma float64
+}
+
+func (t NoisyMajority) Name() string { return "noisy-majority" }
+
+func (t NoisyMajority) Doc() string {
+	return fmt.Sprintf("majority of %d ±1 values under noise σ=%.1f", t.Bits, t.Sigma)
+}
+
+func (t NoisyMajority) Shape() (int, int, int) { return t.Bits, 1, 2 }
+
+func (t NoisyMajority) Generate(n int, r *rand.Rand) ([][][]float64, [][][]float64) {
+	xs, ys := make([][][]float64, n), make([][][]float64, n)
+	for i := range xs {
+		in := grid(t.Bits, 1)
+		sum := 0
+		for j := range in[0] {
+			v := 2*r.Intn(2) - 1
+			sum += v
+			in[0][j] = float64(v) + r.NormFloat64()*t.Sigma
+		}
+		label := 0
+		if sum > 0 {
+			label = 1
+		}
+		xs[i], ys[i] = in, oneHot(label, 2)
+	}
+	return xs, ys
+}
+
+// DistractorCount scatters between 0 and Max targets (value 1) and
+// Distractors half-bright cells (value 0.5) over a grid; the label is
+// the number of targets.
+type DistractorCount struct {
+	Width, Height int
+	Max           int
+	Distractors   int
+}
+
+func (t DistractorCount) Name() string { return "distractor-count" }
+
+func (t DistractorCount) Doc() string {
+	return fmt.Sprintf("count up to %d bright cells among %d dim ones on a %dx%d grid", t.Max, t.Distractors, t.Width, t.Height)
+}
+
+func (t DistractorCount) Shape() (int, int, int) { return t.Width, t.Height, t.Max + 1 }
+
+func (t DistractorCount) Generate(n int, r *rand.Rand) ([][][]float64, [][][]float64) {
+	xs, ys := make([][][]float64, n), make([][][]float64, n)
+	for i := range xs {
+		in := grid(t.Width, t.Height)
+		label := r.Intn(t.Max + 1)
+		cells := r.Perm(t.Width * t.Height)
+		for j, p := range cells[:min(len(cells), label+t.Distractors)] {
+			v := 0.5
+			if j < label {
+				v = 1
+			}
+			in[p/t.Width][p%t.Width] = v
+		}
+		xs[i], ys[i] = in, oneHot(label, t.Max+1)
+	}
+	return xs, ys
+}
@@ -0,0 +1,288 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"arena/compute"
+	"arena/eval"
+	"arena/gates"
+	"arena/parallel"
+	"arena/results"
+	"arena/rng"
+	"arena/stats"
+	"paragon"
+)
+
+// Mode configures replay on the first hidden layer of a fresh network.
+type Mode struct {
+	Name  string
+	Apply func(layer *paragon.Grid[float32]) error
+}
+
+// Baseline leaves the network without replay.
+func Baseline() Mode {
+	return Mode{Name: "baseline", Apply: func(*paragon.Grid[float32]) error { return nil }}
+}
+
+// Static replays the previous layer maxReplay times after the normal pass,
+// as replay6's static networks do.
+func Static(maxReplay int) Mode {
+	return Mode{Name: fmt.Sprintf("static-%d", maxReplay), Apply: func(layer *paragon.Grid[float32]) error {
+		layer.ReplayOffset, layer.ReplayPhase, layer.MaxReplay = -1, "after", maxReplay
+		return nil
+	}}
+}
+
+// Dynamic replays up to budget times when the named gate scores above
+// threshold.
+func Dynamic(gate string, threshold float64, budget int) Mode {
+	return Mode{Name: fmt.Sprintf("%s-%.1f-%d", gate, threshold, budget), Apply: func(layer *paragon.Grid[float32]) error {
+		return gates.Apply(layer, gate, gates.Threshold(threshold, budget), budget)
+	}}
+}
+
+// DefaultModes is the standard comparison: no replay, static replay and
+// entropy-gated dynamic replay.
+func DefaultModes() []Mode {
+	return []Mode{Baseline(), Static(1), Dynamic("entropy", 0.5, 2)}
+}
+
+// Options configures Run. Zero fields take the defaults noted.
+type Options struct {
+	Train, Test  int                           // samples per task; 2000 and 1000
+	Hidden       []struct{ Width, Height int } // hidden layers; one 8x8
+	Epochs       int                           // 5
+	LearningRate float64                       // 0.01
+	Runs         int                           // runs per task and mode; 3
+	Workers      int                           // concurrent trainings; 80% of the CPUs
+	// Seeds is required. Run r of a task draws its data and initial
+	// weights from Seeds.Sub("task=<name>/run=<r>"), so every mode sees
+	// the same data and starts from the same weights.
+	Seeds *rng.Seeds
+	// Recorder, when set, gets one final record per run, model
+	// "<task>/<mode>" with params task, mode and run.
+	Recorder *results.Recorder
+}
+
+// Defaults fills the zero fields of o with the defaults noted.
+func (o *Options) Defaults() {
+	if o.Train == 0 {
+		o.Train = 2000
+	}
+	if o.Test == 0 {
+		o.Test = 1000
+	}
+	if len(o.Hidden) == 0 {
+		o.Hidden = []struct{ Width, Height int }{{8, 8}}
+	}
+	if o.Epochs == 0 {
+		o.Epochs = 5
+	}
+	if o.LearningRate == 0 {
+		o.LearningRate = 0.01
+	}
+	if o.Runs == 0 {
+		o.Runs = parallel.Runs
+	}
+	o.Workers = parallel.Workers(o.Workers)
+}
+
+// Score is one trained network's result.
+type Score struct {
+	Task, Mode string
+	Run        int
+	Seed       int64
+	Result     eval.Result
+	Metrics    map[string]float64 // Result.Metrics plus compute counts
+}
+
+// Run trains every mode on every task opts.Runs times and scores each
+// network on held-out samples. Scores come back ordered by task, mode and
+// run.
+func Run(ts []Task, modes []Mode, opts Options) ([]Score, error) {
+	if opts.Seeds == nil {
+		return nil, errors.New("tasks: Options.Seeds is required")
+	}
+	opts.Defaults()
+
+	// Every mode of task t's run r trains on data[t*opts.Runs+r].
+	data := make([]Data, len(ts)*opts.Runs)
+	streams := make([]*rng.Seeds, len(data))
+	for ti, task := range ts {
+		for run := 0; run < opts.Runs; run++ {
+			j := ti*opts.Runs + run
+			streams[j] = opts.Seeds.Subf("task=%s/run=%d", task.Name(), run)
+			data[j] = Sample(task, opts.Train, opts.Test, streams[j])
+		}
+	}
+	scores := make([]Score, len(ts)*len(modes)*opts.Runs)
+	err := parallel.Do(len(scores), opts.Workers, func(i int) (err error) {
+		run, mi, ti := i%opts.Runs, i/opts.Runs%len(modes), i/opts.Runs/len(modes)
+		j := ti*opts.Runs + run
+		scores[i], err = Fit(data[j], modes[mi], run, streams[j], opts)
+		return err
+	})
+	return scores, err
+}
+
+// Data is a fixed train/test split of one problem.
+type Data struct {
+	Name                         string
+	Width, Height, Classes       int
+	TrainX, TrainY, TestX, TestY [][][]float64
+}
+
+// Sample draws a task's training and test sets from streams.
+func Sample(task Task, train, test int, streams *rng.Seeds) Data {
+	d := Data{Name: task.Name()}
+	d.Width, d.Height, d.Classes = task.Shape()
+	d.TrainX, d.TrainY = task.Generate(train, streams.Rand("train"))
+	d.TestX, d.TestY = task.Generate(test, streams.Rand("test"))
+	return d
+}
+
+// Fit trains one network in mode on data and scores it on the test set:
+// input, opts.Hidden and a softmax output, fully connected, with initial
+// weights and sample order from streams. Only the training fields of
+// opts are used; Runs, Workers and Seeds are not.
+func Fit(data Data, mode Mode, run int, streams *rng.Seeds, opts Options) (Score, error) {
+	opts.Defaults()
+	layers := append([]struct{ Width, Height int }{{data.Width, data.Height}}, opts.Hidden...)
+	layers = append(layers, struct{ Width, Height int }{data.Classes, 1})
+	acts := make([]string, len(layers))
+	fc := make([]bool, len(layers))
+	for i := range acts {
+		acts[i], fc[i] = "leaky_relu", true
+	}
+	acts[len(acts)-1] = "softmax"
+
+	seed := streams.Seed(rng.Init)
+	net := paragon.NewNetwork[float32](layers, acts, fc, seed)
+	if err := mode.Apply(&net.Layers[1]); err != nil {
+		return Score{}, fmt.Errorf("tasks: %s/%s: %w", data.Name, mode.Name, err)
+	}
+	shX := make([][][]float64, len(data.TrainX))
+	shY := make([][][]float64, len(data.TrainY))
+	for i, p := range streams.Rand(rng.Shuffle).Perm(len(data.TrainX)) {
+		shX[i], shY[i] = data.TrainX[p], data.TrainY[p]
+	}
+
+	counter := compute.Attach(net)
+	counter.Train(len(shX), opts.Epochs)
+	net.Train(shX, shY, opts.Epochs, opts.LearningRate, true, 5, -5)
+	counter.Infer(len(data.TestX))
+	res := eval.Evaluate(net, data.TestX, data.TestY)
+	counter.Detach()
+
+	s := Score{Task: data.Name, Mode: mode.Name, Run: run, Seed: seed, Result: res, Metrics: res.Metrics()}
+	for k, v := range counter.Metrics(res.Accuracy) {
+		s.Metrics[k] = v
+	}
+	if opts.Recorder != nil {
+		err := opts.Recorder.Write(results.Record{
+			Kind:    results.KindFinal,
+			Model:   data.Name + "/" + mode.Name,
+			Params:  map[string]any{"task": data.Name, "mode": mode.Name, "run": run},
+			Seed:    seed,
+			ADHD:    res.ADHD,
+			Metrics: s.Metrics,
+		})
+		if err != nil {
+			return s, fmt.Errorf("tasks: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// WriteTable renders one row per task and one column per mode with the
+// mean ± std of metric over runs and the best mode, then each mode's gain
+// over the first mode on each task. Those comparisons are fixed before the
+// results are seen: a paired t-test each (runs share data and seeds), with
+// Holm-adjusted p-values over every comparison in the table.
+func WriteTable(w io.Writer, title, metric string, scores []Score) error {
+	var taskOrder, modeOrder []string
+	vals := map[[2]string][]float64{}
+	for _, s := range scores {
+		k := [2]string{s.Task, s.Mode}
+		if !slices.Contains(taskOrder, s.Task) {
+			taskOrder = append(taskOrder, s.Task)
+		}
+		if !slices.Contains(modeOrder, s.Mode) {
+			modeOrder = append(modeOrder, s.Mode)
+		}
+		vals[k] = append(vals[k], s.Metrics[metric])
+	}
+
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintf(tw, " Task\t %s\t Best\t\n", strings.Join(modeOrder, "\t "))
+	for _, task := range taskOrder {
+		row := " " + task
+		best, bestMean := "", 0.0
+		for _, mode := range modeOrder {
+			xs := vals[[2]string{task, mode}]
+			if len(xs) == 0 {
+				row += "\t -"
+				continue
+			}
+			m, sd := stats.Mean(xs), 0.0
+			if len(xs) > 1 {
+				sd = stats.StdDev(xs)
+			}
+			row += fmt.Sprintf("\t %.2f ± %.2f", m, sd)
+			if best == "" || m > bestMean {
+				best, bestMean = mode, m
+			}
+		}
+		fmt.Fprintf(tw, "%s\t %s\t\n", row, best)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(modeOrder) < 2 {
+		return nil
+	}
+
+	type comparison struct {
+		task, mode string
+		gain, p    float64
+	}
+	var cs []comparison
+	var ps []float64
+	for _, task := range taskOrder {
+		base := vals[[2]string{task, modeOrder[0]}]
+		for _, mode := range modeOrder[1:] {
+			xs := vals[[2]string{task, mode}]
+			if len(xs) == 0 || len(base) == 0 {
+				continue
+			}
+			c := comparison{task, mode, stats.Mean(xs) - stats.Mean(base), math.NaN()}
+			if len(xs) == len(base) {
+				c.p = stats.PairedT(base, xs).P
+			}
+			cs = append(cs, c)
+			ps = append(ps, c.p)
+		}
+	}
+	holm := stats.Holm(ps)
+	fmt.Fprintf(w, "\n============== %s: VS %s ==============\n", title, modeOrder[0])
+	tw = tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " Task\t Mode\t Gain\t p\t Holm p\t")
+	for i, c := range cs {
+		fmt.Fprintf(tw, " %s\t %s\t %+.2f\t %s\t %s\t\n", c.task, c.mode, c.gain, pval(c.p), pval(holm[i]))
+	}
+	return tw.Flush()
+}
+
+func pval(p float64) string {
+	if math.IsNaN(p) {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", p)
+}
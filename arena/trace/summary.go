@@ -0,0 +1,328 @@
+package trace
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"arena/stats"
+)
+
+// ScoreBins is the number of equal-width bins over [0,1] in a Summary's
+// score histogram.
+const ScoreBins = 10
+
+// Summary aggregates the decisions of one layer in one phase.
+type Summary struct {
+	Phase     string
+	Layer     int
+	Decisions int
+	Samples   int // distinct samples per epoch, summed: sample passes
+	Replays   int // sum of Reps
+	Fired     int // decisions with Reps > 0
+	MeanScore float64
+	Budget    int // largest ReplayBudget seen
+	// BudgetUse is Replays over Decisions·Budget: the share of the
+	// allowed replays actually taken; NaN without a budget.
+	BudgetUse float64
+	Scores    [ScoreBins]int // scores clamped into [0,1]
+	Reps      map[int]int    // reps -> decisions
+}
+
+// MeanReps is the mean replays per decision.
+func (s Summary) MeanReps() float64 { return ratio(s.Replays, s.Decisions) }
+
+// Summaries aggregates the events per phase and layer, in the order the
+// phases first appear.
+func (t *Tracer) Summaries() []Summary {
+	type key struct {
+		phase string
+		layer int
+	}
+	byKey := map[key]*Summary{}
+	samples := map[key]map[[2]int]bool{}
+	var order []key
+	for _, e := range t.Events() {
+		k := key{e.Phase, e.Layer}
+		s, ok := byKey[k]
+		if !ok {
+			s = &Summary{Phase: e.Phase, Layer: e.Layer, Reps: map[int]int{}}
+			byKey[k], samples[k] = s, map[[2]int]bool{}
+			order = append(order, k)
+		}
+		s.Decisions++
+		s.Replays += e.Reps
+		if e.Reps > 0 {
+			s.Fired++
+		}
+		s.MeanScore += e.Score
+		s.Budget = max(s.Budget, e.Budget)
+		s.Scores[bin(e.Score)]++
+		s.Reps[e.Reps]++
+		samples[k][[2]int{e.Epoch, e.Sample}] = true
+	}
+	phases := map[string]int{}
+	for _, k := range order {
+		if _, ok := phases[k.phase]; !ok {
+			phases[k.phase] = len(phases)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].phase != order[j].phase {
+			return phases[order[i].phase] < phases[order[j].phase]
+		}
+		return order[i].layer < order[j].layer
+	})
+	out := make([]Summary, len(order))
+	for i, k := range order {
+		s := byKey[k]
+		s.Samples = len(samples[k])
+		s.MeanScore /= float64(s.Decisions)
+		s.BudgetUse = math.NaN()
+		if s.Budget > 0 {
+			s.BudgetUse = float64(s.Replays) / float64(s.Decisions*s.Budget)
+		}
+		out[i] = *s
+	}
+	return out
+}
+
+// RepsBucket is the samples that received one total replay count.
+type RepsBucket struct {
+	Reps     int // summed over the traced layers
+	N        int
+	Correct  int
+	Accuracy float64 // percent
+}
+
+// Outcomes relates how often each sample was replayed to whether it was
+// classified correctly.
+type Outcomes struct {
+	Phase       string
+	N           int // samples with both decisions and an outcome
+	Buckets     []RepsBucket
+	RepsCorrect float64 // mean replays of correct samples
+	RepsWrong   float64 // mean replays of misclassified samples
+	// Correlation is the point-biserial correlation of a sample's replays
+	// with being correct: negative when the gates replay the hard samples.
+	// NaN when either side is constant.
+	Correlation float64
+}
+
+// Outcomes joins phase's decisions with the outcomes recorded for it (by
+// Evaluate for Eval).
+func (t *Tracer) Outcomes(phase string) Outcomes {
+	reps := map[int]int{}
+	for _, e := range t.Events() {
+		if e.Phase == phase {
+			reps[e.Sample] += e.Reps
+		}
+	}
+	t.mu.Lock()
+	correct := t.correct[phase]
+	t.mu.Unlock()
+
+	o := Outcomes{Phase: phase, Correlation: math.NaN()}
+	byReps := map[int]*RepsBucket{}
+	var xs, ys []float64
+	var onRight, onWrong []float64
+	for i, r := range reps {
+		ok, known := correct[i]
+		if !known {
+			continue
+		}
+		b := byReps[r]
+		if b == nil {
+			b = &RepsBucket{Reps: r}
+			byReps[r] = b
+		}
+		b.N++
+		y := 0.0
+		if ok {
+			b.Correct++
+			y = 1
+			onRight = append(onRight, float64(r))
+		} else {
+			onWrong = append(onWrong, float64(r))
+		}
+		xs, ys = append(xs, float64(r)), append(ys, y)
+	}
+	o.N = len(xs)
+	for _, b := range byReps {
+		b.Accuracy = 100 * ratio(b.Correct, b.N)
+		o.Buckets = append(o.Buckets, *b)
+	}
+	sort.Slice(o.Buckets, func(i, j int) bool { return o.Buckets[i].Reps < o.Buckets[j].Reps })
+	o.RepsCorrect, o.RepsWrong = mean(onRight), mean(onWrong)
+	o.Correlation = pearson(xs, ys)
+	return o
+}
+
+// Metrics condenses a trace into numbers for a results record:
+// replays per sample and budget use for each phase, and the eval-phase
+// correlation of replays with correctness.
+func (t *Tracer) Metrics() map[string]float64 {
+	m := map[string]float64{}
+	type acc struct{ replays, samples, decisions, allowed int }
+	byPhase := map[string]*acc{}
+	for _, s := range t.Summaries() {
+		a := byPhase[s.Phase]
+		if a == nil {
+			a = &acc{}
+			byPhase[s.Phase] = a
+		}
+		a.replays += s.Replays
+		a.decisions += s.Decisions
+		a.allowed += s.Decisions * s.Budget
+		a.samples = max(a.samples, s.Samples)
+	}
+	for phase, a := range byPhase {
+		m["replays_per_sample_"+phase] = ratio(a.replays, a.samples)
+		if a.allowed > 0 {
+			m["budget_use_"+phase] = ratio(a.replays, a.allowed)
+		}
+	}
+	if o := t.Outcomes(Eval); o.N > 0 && !math.IsNaN(o.Correlation) {
+		m["replay_correct_corr"] = o.Correlation
+	}
+	if n := t.Dropped(); n > 0 {
+		m["trace_dropped"] = float64(n)
+	}
+	return m
+}
+
+// WriteSummary renders one row per phase and layer.
+func WriteSummary(w io.Writer, title string, ss []Summary) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " Phase\t Layer\t Decisions\t Samples\t Fired%\t Mean score\t Mean reps\t Replays\t Budget\t Budget use%\t")
+	for _, s := range ss {
+		use := "-"
+		if !math.IsNaN(s.BudgetUse) {
+			use = fmt.Sprintf("%.1f", 100*s.BudgetUse)
+		}
+		fmt.Fprintf(tw, " %s\t %d\t %d\t %d\t %.1f\t %.4f\t %.3f\t %d\t %d\t %s\t\n",
+			s.Phase, s.Layer, s.Decisions, s.Samples, 100*ratio(s.Fired, s.Decisions),
+			s.MeanScore, s.MeanReps(), s.Replays, s.Budget, use)
+	}
+	return tw.Flush()
+}
+
+// WriteHistograms renders each summary's score and reps histograms as
+// percentages of its decisions.
+func WriteHistograms(w io.Writer, ss []Summary) error {
+	for _, s := range ss {
+		fmt.Fprintf(w, "%s, layer %d — gate scores:\n", s.Phase, s.Layer)
+		tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.AlignRight|tabwriter.Debug)
+		var head, row []string
+		for b, n := range s.Scores {
+			head = append(head, fmt.Sprintf("%.1f-%.1f", float64(b)/ScoreBins, float64(b+1)/ScoreBins))
+			row = append(row, fmt.Sprintf("%.1f", 100*ratio(n, s.Decisions)))
+		}
+		fmt.Fprintln(tw, strings.Join(head, "\t")+"\t")
+		fmt.Fprintln(tw, strings.Join(row, "\t")+"\t")
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s, layer %d — replays:\n", s.Phase, s.Layer)
+		reps := make([]int, 0, len(s.Reps))
+		for r := range s.Reps {
+			reps = append(reps, r)
+		}
+		sort.Ints(reps)
+		tw = tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.AlignRight|tabwriter.Debug)
+		head, row = head[:0], row[:0]
+		for _, r := range reps {
+			head = append(head, fmt.Sprint(r))
+			row = append(row, fmt.Sprintf("%.1f", 100*ratio(s.Reps[r], s.Decisions)))
+		}
+		fmt.Fprintln(tw, strings.Join(head, "\t")+"\t")
+		fmt.Fprintln(tw, strings.Join(row, "\t")+"\t")
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteOutcomes renders accuracy per total replay count.
+func WriteOutcomes(w io.Writer, title string, o Outcomes) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	fmt.Fprintf(w, "%s: N=%d  mean replays correct %.3f  wrong %.3f  correlation %s\n",
+		o.Phase, o.N, o.RepsCorrect, o.RepsWrong, num(o.Correlation))
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " Replays\t Samples\t Share%\t Correct\t Acc%\t")
+	for _, b := range o.Buckets {
+		fmt.Fprintf(tw, " %d\t %d\t %.1f\t %d\t %.2f\t\n", b.Reps, b.N, 100*ratio(b.N, o.N), b.Correct, b.Accuracy)
+	}
+	return tw.Flush()
+}
+
+// WriteTable renders several traces side by side, one row per name, from
+// their Metrics and eval Outcomes.
+func WriteTable(w io.Writer, title string, names []string, ts []*Tracer) error {
+	fmt.Fprintf(w, "\n============== %s ==============\n", title)
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, " Model\t Reps/sample train\t Reps/sample eval\t Budget use% eval\t Reps correct\t Reps wrong\t Corr\t")
+	for i, t := range ts {
+		m := t.Metrics()
+		o := t.Outcomes(Eval)
+		use := math.NaN()
+		if v, ok := m["budget_use_"+Eval]; ok {
+			use = 100 * v
+		}
+		fmt.Fprintf(tw, " %s\t %.3f\t %.3f\t %s\t %s\t %s\t %s\t\n", names[i],
+			m["replays_per_sample_"+Train], m["replays_per_sample_"+Eval], num(use),
+			num(o.RepsCorrect), num(o.RepsWrong), num(o.Correlation))
+	}
+	return tw.Flush()
+}
+
+func bin(score float64) int {
+	b := int(score * ScoreBins)
+	return max(0, min(b, ScoreBins-1))
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	return stats.Mean(xs)
+}
+
+// pearson is the correlation of xs and ys; NaN when either is constant.
+func pearson(xs, ys []float64) float64 {
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+	mx, my := stats.Mean(xs), stats.Mean(ys)
+	var sxy, sxx, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+func num(x float64) string {
+	if math.IsNaN(x) {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", x)
+}
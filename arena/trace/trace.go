@@ -0,0 +1,221 @@
+// Package trace records what dynamic replay decides: for every gated
+// layer and every pass, the gate's score, the replays the policy chose and
+// the layer's budget. It wraps the layers' ReplayGateToReps, so any gate
+// and policy can be traced without touching paragon.
+//
+//	tr := trace.Attach(net)
+//	tr.Order(perm) // shX[k] is trainX[perm[k]]
+//	for e := 1; e <= epochs; e++ {
+//		tr.Begin(trace.Train, e)
+//		net.Train(shX, shY, 1, lr, true, 5, -5)
+//	}
+//	res := trace.Evaluate(tr, net, testX, testY, 3)
+//	trace.WriteSummary(os.Stdout, "dynamic", tr.Summaries())
+//	trace.WriteOutcomes(os.Stdout, "dynamic", tr.Outcomes(trace.Eval))
+//
+// Inside Train the sample a decision belongs to is not visible; it is
+// counted per layer from Begin, which is the position in the order the
+// samples were fed as long as each pass asks each gate once; Order maps
+// that position back to the dataset index when the set was shuffled.
+// Begin therefore belongs before every epoch. Evaluate tags every pass with
+// its sample index explicitly.
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"arena/eval"
+	"arena/results"
+	"paragon"
+)
+
+// Phases passed to Begin.
+const (
+	Train = "train"
+	Eval  = "eval"
+)
+
+// Event is one replay decision.
+type Event struct {
+	Phase  string  `json:"phase"`
+	Epoch  int     `json:"epoch"`
+	Sample int     `json:"sample"`
+	Layer  int     `json:"layer"`
+	Score  float64 `json:"score"`
+	Reps   int     `json:"reps"`
+	Budget int     `json:"budget"` // the layer's ReplayBudget
+}
+
+// Tracer collects the events of one network. It is safe for concurrent
+// use, but the sample counters assume one pass at a time.
+type Tracer struct {
+	mu      sync.Mutex
+	phase   string
+	epoch   int
+	sample  int         // set by Sample; -1 to count per layer
+	counts  map[int]int // layer -> decisions since Begin
+	order   []int       // fed position -> dataset index, set by Order
+	events  []Event
+	dropped int                     // decisions past MaxEvents
+	correct map[string]map[int]bool // phase -> sample -> correct
+	restore []func()
+}
+
+// MaxEvents bounds the events a Tracer keeps; later decisions advance the
+// sample counters but are dropped, counted by Dropped and reported by Save
+// and Metrics. 0 keeps everything.
+var MaxEvents = 5_000_000
+
+// ErrTruncated is returned by Save when decisions were dropped.
+var ErrTruncated = errors.New("trace truncated at MaxEvents")
+
+// Attach traces every layer of net that has a ReplayGateToReps. Layers
+// without one replay a fixed MaxReplay and have nothing to decide.
+func Attach[T paragon.Numeric](net *paragon.Network[T]) *Tracer {
+	t := &Tracer{phase: Train, sample: -1, counts: map[int]int{},
+		correct: map[string]map[int]bool{}}
+	for l := range net.Layers {
+		layer := &net.Layers[l]
+		policy := layer.ReplayGateToReps
+		if policy == nil {
+			continue
+		}
+		layer.ReplayGateToReps = func(score float64) int {
+			reps := policy(score)
+			t.record(l, score, reps, layer.ReplayBudget)
+			return reps
+		}
+		t.restore = append(t.restore, func() { layer.ReplayGateToReps = policy })
+	}
+	return t
+}
+
+// Detach puts back the layers' own policies.
+func (t *Tracer) Detach() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range t.restore {
+		r()
+	}
+	t.restore = nil
+}
+
+// Begin starts a phase (Train, Eval or any other name) of an epoch and
+// restarts the per-layer sample counters.
+func (t *Tracer) Begin(phase string, epoch int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.phase, t.epoch, t.sample = phase, epoch, -1
+	t.counts = map[int]int{}
+}
+
+// Order maps counted sample positions to dataset indices: the k-th sample
+// fed after each Begin is idx[k], as when training on a copy shuffled by
+// the permutation idx. It holds until the next Order; nil counts positions.
+func (t *Tracer) Order(idx []int) {
+	t.mu.Lock()
+	t.order = idx
+	t.mu.Unlock()
+}
+
+// Sample tags the following decisions with sample i; -1 goes back to
+// counting.
+func (t *Tracer) Sample(i int) {
+	t.mu.Lock()
+	t.sample = i
+	t.mu.Unlock()
+}
+
+// Outcome records whether sample i of phase was classified correctly.
+func (t *Tracer) Outcome(phase string, i int, correct bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.correct[phase] == nil {
+		t.correct[phase] = map[int]bool{}
+	}
+	t.correct[phase][i] = correct
+}
+
+// Dropped is the number of decisions not kept because of MaxEvents.
+func (t *Tracer) Dropped() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.dropped
+}
+
+// Events returns a copy of the recorded events.
+func (t *Tracer) Events() []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Event(nil), t.events...)
+}
+
+func (t *Tracer) record(layer int, score float64, reps, budget int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sample := t.sample
+	if sample < 0 {
+		sample = t.counts[layer]
+		if sample < len(t.order) {
+			sample = t.order[sample]
+		}
+	}
+	t.counts[layer]++
+	if MaxEvents > 0 && len(t.events) >= MaxEvents {
+		t.dropped++
+		return
+	}
+	t.events = append(t.events, Event{Phase: t.phase, Epoch: t.epoch, Sample: sample,
+		Layer: layer, Score: score, Reps: reps, Budget: budget})
+}
+
+// Evaluate is eval.Evaluate with every pass tagged with its sample index
+// under the Eval phase, and each sample's outcome recorded for Outcomes.
+func Evaluate[T paragon.Numeric](t *Tracer, net *paragon.Network[T], inputs, targets [][][]float64, topK ...int) eval.Result {
+	t.mu.Lock()
+	epoch := t.epoch
+	t.mu.Unlock()
+	t.Begin(Eval, epoch)
+	outputs := make([][]float64, len(inputs))
+	for i, in := range inputs {
+		t.Sample(i)
+		net.Forward(in)
+		outputs[i] = net.ExtractOutput()
+	}
+	t.Sample(-1)
+	labels := eval.Labels(targets)
+	res := eval.FromOutputs(outputs, labels, topK...)
+	net.EvaluateModel(res.Expected, res.Predicted)
+	res.ADHD = results.ADHDOf(net)
+	for i, l := range labels {
+		t.Outcome(Eval, i, int(res.Predicted[i]) == l)
+	}
+	return res
+}
+
+// Save writes the events as JSONL. If decisions were dropped the kept
+// events are still written and the error wraps ErrTruncated.
+func (t *Tracer) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("trace: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range t.Events() {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return fmt.Errorf("trace: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("trace: %w", err)
+	}
+	if n := t.Dropped(); n > 0 {
+		return fmt.Errorf("trace: %s lacks the last %d decisions: %w", path, n, ErrTruncated)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package train
+
+import (
+	"fmt"
+
+	"paragon"
+)
+
+// Weights is an in-memory copy of a network's biases and connection
+// weights. Unlike a SaveJSON round trip it leaves the network itself alone,
+// so replay gates and other closures bound to its layers keep working
+// after Restore.
+type Weights[T paragon.Numeric] struct {
+	values []T // per neuron: bias, then each input weight
+}
+
+// Snapshot copies net's current weights.
+func Snapshot[T paragon.Numeric](net *paragon.Network[T]) Weights[T] {
+	var w Weights[T]
+	eachNeuron(net, func(n *paragon.Neuron[T]) {
+		w.values = append(w.values, n.Bias)
+		for _, c := range n.Inputs {
+			w.values = append(w.values, c.Weight)
+		}
+	})
+	return w
+}
+
+// Restore writes the snapshot back into net, which must have the shape the
+// snapshot was taken from.
+func (w Weights[T]) Restore(net *paragon.Network[T]) error {
+	size := 0
+	eachNeuron(net, func(n *paragon.Neuron[T]) { size += 1 + len(n.Inputs) })
+	if size != len(w.values) {
+		return fmt.Errorf("checkpoint has %d values, network has %d", len(w.values), size)
+	}
+	i := 0
+	eachNeuron(net, func(n *paragon.Neuron[T]) {
+		n.Bias = w.values[i]
+		i++
+		for c := range n.Inputs {
+			n.Inputs[c].Weight = w.values[i]
+			i++
+		}
+	})
+	return nil
+}
+
+// Len is the number of values (biases plus weights) in the snapshot.
+func (w Weights[T]) Len() int { return len(w.values) }
+
+func eachNeuron[T paragon.Numeric](net *paragon.Network[T], fn func(*paragon.Neuron[T])) {
+	for l := range net.Layers {
+		for _, row := range net.Layers[l].Neurons {
+			for _, n := range row {
+				if n != nil {
+					fn(n)
+				}
+			}
+		}
+	}
+}
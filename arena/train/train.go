@@ -0,0 +1,233 @@
+// Package train runs paragon's Train one epoch at a time so the learning
+// curve is visible: after each epoch the network is scored on a held-out
+// set, the metrics go to the results sink, and training can stop early and
+// roll back to its best epoch.
+//
+//	hist, err := train.Fit(net, trainX, trainY, train.Options[float32]{
+//		Epochs: 20, LearningRate: 0.001, ClipUpper: 5, ClipLower: -5,
+//		ValX: valX, ValY: valY,
+//		Patience: 3, RestoreBest: true,
+//		Recorder: rec, Model: "dynamic",
+//	})
+//
+// replaces net.Train(trainX, trainY, 20, 0.001, true, 5, -5).
+package train
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"arena/eval"
+	"arena/results"
+	"paragon"
+)
+
+// Monitored metrics.
+const (
+	Loss     = "loss"     // validation log-loss, lower is better
+	Accuracy = "accuracy" // validation accuracy, higher is better
+	ADHD     = "adhd"     // validation ADHD score, higher is better
+)
+
+// Options configures Fit. The first five fields are the arguments of
+// paragon's Train.
+type Options[T paragon.Numeric] struct {
+	Epochs                  int
+	LearningRate            float64
+	EarlyStopOnNegativeLoss bool
+	ClipUpper, ClipLower    T
+
+	// Schedule, when set, gives the learning rate of each epoch (0-based)
+	// instead of LearningRate.
+	Schedule func(epoch int) float64
+	// BeforeEpoch runs before each epoch (0-based), e.g. to change replay
+	// budgets as training goes on.
+	BeforeEpoch func(epoch int)
+	// Shuffle, when set, reorders the training set before every epoch.
+	Shuffle *rand.Rand
+
+	// Held-out set scored after every epoch. Without it only the epoch
+	// times are recorded and early stopping is off.
+	ValX, ValY [][][]float64
+	// TrainSubset also scores the first TrainSubset training samples, for
+	// train-vs-validation curves. 0 skips it.
+	TrainSubset int
+
+	Monitor     string  // Loss (default), Accuracy or ADHD
+	Patience    int     // stop after this many epochs without improvement; 0 never stops
+	MinDelta    float64 // smallest change that counts as an improvement
+	RestoreBest bool    // roll the weights back to the best epoch at the end
+
+	// Recorder, when set, receives one KindEpoch record per epoch for Model.
+	Recorder *results.Recorder
+	Model    string
+	Params   map[string]any
+	Seed     int64
+
+	// OnEpoch, when set, is called after each epoch is scored.
+	OnEpoch func(Epoch)
+}
+
+// Epoch is the state after one epoch (1-based).
+type Epoch struct {
+	Epoch        int
+	LearningRate float64
+	Seconds      float64
+	Val          *eval.Result // nil without a validation set
+	Train        *eval.Result // nil without TrainSubset
+}
+
+// Metrics flattens the epoch for a results record: val_loss, val_accuracy,
+// val_adhd, val_ece, train_loss, train_accuracy, lr and seconds.
+func (e Epoch) Metrics() map[string]float64 {
+	m := map[string]float64{"lr": e.LearningRate, "seconds": e.Seconds}
+	for prefix, r := range map[string]*eval.Result{"val_": e.Val, "train_": e.Train} {
+		if r == nil {
+			continue
+		}
+		m[prefix+"loss"] = r.LogLoss
+		m[prefix+"accuracy"] = r.Accuracy
+		m[prefix+"ece"] = r.ECE
+		if r.ADHD != nil {
+			m[prefix+"adhd"] = r.ADHD.Score
+		}
+	}
+	return m
+}
+
+// History is the learning curve of one Fit.
+type History struct {
+	Epochs   []Epoch
+	Best     int  // 1-based epoch with the best monitored metric; 0 without validation
+	Stopped  bool // early stopping ended training before Epochs
+	Restored bool // the weights were rolled back to Best
+}
+
+// BestEpoch returns the epoch the weights were restored to or, without
+// RestoreBest, the best one seen.
+func (h History) BestEpoch() (Epoch, bool) {
+	if h.Best == 0 {
+		return Epoch{}, false
+	}
+	return h.Epochs[h.Best-1], true
+}
+
+// Fit trains net on x, y for up to o.Epochs epochs. Errors come from
+// writing records or restoring the checkpoint; the network is trained
+// either way.
+func Fit[T paragon.Numeric](net *paragon.Network[T], x, y [][][]float64, o Options[T]) (History, error) {
+	monitor := o.Monitor
+	if monitor == "" {
+		monitor = Loss
+	}
+	sign, err := direction(monitor)
+	if err != nil {
+		return History{}, err
+	}
+	validate := len(o.ValX) > 0
+
+	var (
+		hist     History
+		best     = math.Inf(-1) // of sign·metric
+		bestW    Weights[T]
+		wait     int
+		firstErr error
+	)
+	for e := 0; e < o.Epochs; e++ {
+		if o.BeforeEpoch != nil {
+			o.BeforeEpoch(e)
+		}
+		lr := o.LearningRate
+		if o.Schedule != nil {
+			lr = o.Schedule(e)
+		}
+		ex, ey := x, y
+		if o.Shuffle != nil {
+			ex, ey = shuffled(x, y, o.Shuffle)
+		}
+
+		start := time.Now()
+		net.Train(ex, ey, 1, lr, o.EarlyStopOnNegativeLoss, o.ClipUpper, o.ClipLower)
+		ep := Epoch{Epoch: e + 1, LearningRate: lr, Seconds: time.Since(start).Seconds()}
+		if validate {
+			r := eval.Evaluate(net, o.ValX, o.ValY)
+			ep.Val = &r
+		}
+		if n := min(o.TrainSubset, len(x)); n > 0 {
+			r := eval.Evaluate(net, x[:n], y[:n])
+			ep.Train = &r
+		}
+		hist.Epochs = append(hist.Epochs, ep)
+
+		if o.Recorder != nil {
+			err := o.Recorder.Write(results.Record{
+				Kind:    results.KindEpoch,
+				Model:   o.Model,
+				Params:  o.Params,
+				Seed:    o.Seed,
+				Epoch:   ep.Epoch,
+				Metrics: ep.Metrics(),
+			})
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		if o.OnEpoch != nil {
+			o.OnEpoch(ep)
+		}
+		if !validate {
+			continue
+		}
+
+		if v := sign * monitored(*ep.Val, monitor); v > best+o.MinDelta {
+			best, hist.Best, wait = v, ep.Epoch, 0
+			if o.RestoreBest {
+				bestW = Snapshot(net)
+			}
+		} else if wait++; o.Patience > 0 && wait >= o.Patience {
+			hist.Stopped = true
+			break
+		}
+	}
+
+	if o.RestoreBest && hist.Best > 0 && hist.Best < len(hist.Epochs) {
+		err := bestW.Restore(net)
+		if err == nil {
+			hist.Restored = true
+		} else if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return hist, firstErr
+}
+
+// direction is +1 for metrics to maximise and -1 for ones to minimise.
+func direction(metric string) (float64, error) {
+	switch metric {
+	case Loss:
+		return -1, nil
+	case Accuracy, ADHD:
+		return 1, nil
+	}
+	return 0, fmt.Errorf("unknown monitor %q (want %s, %s or %s)", metric, Loss, Accuracy, ADHD)
+}
+
+func monitored(r eval.Result, metric string) float64 {
+	switch metric {
+	case Accuracy:
+		return r.Accuracy
+	case ADHD:
+		return r.Score()
+	}
+	return r.LogLoss
+}
+
+func shuffled(x, y [][][]float64, r *rand.Rand) ([][][]float64, [][][]float64) {
+	sx, sy := make([][][]float64, len(x)), make([][][]float64, len(y))
+	for i, p := range r.Perm(len(x)) {
+		sx[i], sy[i] = x[p], y[p]
+	}
+	return sx, sy
+}
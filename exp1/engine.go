@@ -1,10 +1,10 @@
 package main
 
 import (
+	"arena/rng"
 	"fmt"
 	"math"
 	"math/rand"
-	"time"
 
 	"paragon"
 )
@@ -19,7 +19,9 @@ var digitPatterns = map[int][]int{
 }
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("exp1", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	// Generate training data
 	trainData := generateDigitData(200)
@@ -1,16 +1,18 @@
 package main
 
 import (
+	"arena/rng"
 	"fmt"
 	"math"
 	"math/rand"
-	"time"
 
 	"paragon"
 )
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("face1", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	faces := [][][]int{
 		// Happy
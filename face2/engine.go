@@ -1,17 +1,19 @@
 package main
 
 import (
+	"arena/rng"
 	"fmt"
 	"math"
 	"math/rand"
-	"time"
 
 	"paragon"
 )
 
 // We'll define 8×8 faces. Each face is an 8×8 grid with 0=blank, 1=eyes, 2=mouth, 3=eyebrows (or whatever).
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("face2", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	// A few 8×8 faces:
 	faceHappy := [][]int{
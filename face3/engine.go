@@ -1,17 +1,19 @@
 package main
 
 import (
+	"arena/rng"
 	"fmt"
 	"math"
 	"math/rand"
-	"time"
 
 	"paragon"
 )
 
 // We'll define 16×16 faces with 0=blank, 1=eyes, 2=mouth, 3=eyebrows.
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("face3", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	// Generate more training data
 	faces := generateTrainingFaces(100) // 100 synthetic faces
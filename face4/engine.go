@@ -1,12 +1,12 @@
 package main
 
 import (
+	"arena/rng"
 	"fmt"
 	"math"
 	"math/rand"
 	"os"
 	"strings"
-	"time"
 
 	"paragon" // Replace with actual import path, e.g., "github.com/username/paragon"
 )
@@ -657,7 +657,9 @@ func generateEmoticon(d *paragon.DiffusionModel, inputWord string) string {
 // main()
 // -------------------------------------------------------
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("face4", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	// 1) Build a custom char-level tokenizer with [PAD], [MASK], [SEP]
 	tok := &paragon.CustomTokenizer{
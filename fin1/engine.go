@@ -6,11 +6,10 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
-	"net/http"
-	"os"
 	"strconv"
 	"strings"
 
+	"arena/datasets/cache"
 	"paragon" // Replace with your Paragon package path
 )
 
@@ -18,28 +17,14 @@ func main() {
 	// Set random seed for reproducibility
 	rand.Seed(42)
 
-	// Download and extract the Bank Marketing dataset
-	url := "https://archive.ics.uci.edu/ml/machine-learning-databases/00222/bank.zip"
-	resp, err := http.Get(url)
+	// Fetch the Bank Marketing dataset through the dataset cache
+	zipPath, err := cache.Default().Fetch(cache.BankMarketing)
 	if err != nil {
-		panic(fmt.Errorf("failed to download Bank Marketing dataset: %v", err))
+		panic(fmt.Errorf("failed to fetch Bank Marketing dataset: %v", err))
 	}
-	defer resp.Body.Close()
-
-	// Save ZIP file temporarily
-	zipFile, err := os.Create("bank.zip")
-	if err != nil {
-		panic(fmt.Errorf("failed to create zip file: %v", err))
-	}
-	_, err = io.Copy(zipFile, resp.Body)
-	zipFile.Close()
-	if err != nil {
-		panic(fmt.Errorf("failed to write zip file: %v", err))
-	}
-	defer os.Remove("bank.zip")
 
 	// Extract bank.csv from ZIP
-	r, err := zip.OpenReader("bank.zip")
+	r, err := zip.OpenReader(zipPath)
 	if err != nil {
 		panic(fmt.Errorf("failed to open zip: %v", err))
 	}
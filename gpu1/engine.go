@@ -1,6 +1,7 @@
 package main
 
 import (
+	"arena/datasets/mnist"
 	"fmt"
 	"log"
 	"math"
@@ -49,11 +50,11 @@ func main() {
 	rand.Seed(42)
 
 	// Load MNIST data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
 
-	testInputs, _, err := loadMNISTData(mnistDir, false)
+	testInputs, _, err := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	if err != nil {
 		log.Fatalf("Failed to load test data: %v", err)
 	}
@@ -2,7 +2,10 @@ module main
 
 go 1.24.0
 
-require paragon v0.0.0
+require (
+	arena v0.0.0
+	paragon v0.0.0
+)
 
 require (
 	github.com/gocarina/gocsv v0.0.0-20240520201108-78e41c74b4b1 // indirect
@@ -11,3 +14,5 @@ require (
 )
 
 replace paragon => ../../
+
+replace arena => ../arena
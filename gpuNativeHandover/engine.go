@@ -213,6 +213,7 @@ void bufferMapCallback(WGPUMapAsyncStatus status, void* userdata) {
 */
 import "C"
 import (
+	"arena/rng"
 	"fmt"
 	"math"
 	"math/rand"
@@ -240,7 +241,9 @@ func main() {
 	const size = 1000000
 
 	// Generate random input vectors
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("gpuNativeHandover", "")
+	rand.Seed(seeds.Seed(rng.Global))
 	a := generateVector(size)
 	b := generateVector(size)
 
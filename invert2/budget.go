@@ -0,0 +1,40 @@
+package main
+
+import (
+	"arena/distill"
+	"fmt"
+	"log"
+	"os"
+	"paragon"
+)
+
+// queryBudgets are the teacher query counts the students are scored at
+var queryBudgets = []int{50, 100, 250, 500, 1000, 2000}
+
+// runQueryBudget distils students that may only query the teacher
+// queryBudgets[len-1] times, on probes from each of distill's generators,
+// and prints their agreement with the teacher against the query count.
+// The real-input probes are the tournament's training set.
+func runQueryBudget(trainX, testX, testY [][][]float64, teacher *paragon.Network[float64]) {
+	nTrain, nTest := min(tournamentTrain, len(trainX)), min(tournamentTest, len(testX))
+	fmt.Printf("\n🔎 Query budget: %v teacher queries, probes %v\n", queryBudgets, distill.ProbeNames())
+	data, err := distill.CachedData(teacherCache, "mnist/invert2-tournament", teacher, trainX[:nTrain], testX[:nTest], testY[:nTest])
+	if err != nil {
+		log.Fatalf("query budget: %v", err)
+	}
+
+	cps, err := distill.Budgeted(teacher, distill.ProbeNames(), data, distill.BudgetOptions[float64]{
+		Student: func(seed int64) *paragon.Network[float64] {
+			return paragon.NewNetwork[float64](studentSizes, studentActs, studentFC, seed)
+		},
+		Budgets: queryBudgets,
+		Seeds:   seeds.Sub("budget"),
+	})
+	if err != nil {
+		log.Fatalf("query budget: %v", err)
+	}
+	title := fmt.Sprintf("AGREEMENT %% VS TEACHER QUERIES (teacher accuracy %.2f%%)", data.TeacherAccuracy())
+	if err := distill.WriteCurve(os.Stdout, title, distill.Curve(cps)); err != nil {
+		log.Fatalf("query budget: %v", err)
+	}
+}
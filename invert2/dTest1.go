@@ -1,19 +1,34 @@
 package main
 
 import (
+	"arena/distill"
 	"fmt"
+	"log"
 	"math"
 	"math/rand"
 	"paragon"
 	"sort"
 )
 
+// newRule builds a fresh update rule for one student; rule is one of
+// distill.Names() (upstream, upstream-smart, hebb-error, momentum, ...)
+func newRule(rule string, params distill.Params) distill.LocalUpdateRule[float64] {
+	r, err := distill.New[float64](rule, params, seeds.Rand("rule="+rule))
+	if err != nil {
+		log.Fatalf("update rule: %v", err)
+	}
+	return r
+}
+
 func runStudentDistillation(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64, // ✅ FIXED TYPE
-	nn *paragon.Network,
+	nn *paragon.Network[float64],
+	rule string,
 ) {
-	fmt.Println("\n---------Student Network Iterative Distillation on Training Data----------")
+	fmt.Printf("\n---------Student Network Iterative Distillation on Training Data (%s)----------\n", rule)
+	teacherOuts := teacherOutputs(nn, trainSetInputs)
+	teacherScore := teacherADHD(nn, trainSetInputs, trainSetTargets)
 
 	params := []struct {
 		MaxUpdate float64
@@ -33,7 +48,7 @@ func runStudentDistillation(
 		student := createStudentNet()
 		lr := 0.01
 		lastScore := 0.0
-		//tick := 0
+		update := newRule(rule, distill.Params{"lr": lr, "max_update": p.MaxUpdate, "damping": p.Damping})
 
 		fmt.Printf("%-12s %-20s %-20s %s\n", "Iteration", "Teacher ADHD", "Student ADHD", "Δ")
 
@@ -43,8 +58,7 @@ func runStudentDistillation(
 			for i := range trainSetInputs {
 				input := trainSetInputs[i]
 
-				nn.Forward(input)
-				targetVec := nn.ExtractOutput()
+				targetVec := teacherOuts[i]
 
 				student.Forward(input)
 				predVec := student.ExtractOutput()
@@ -57,34 +71,7 @@ func runStudentDistillation(
 						err = -0.1
 					}
 
-					adjustNetworkUpstream(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					// adjustNetworkUpstream(student, input, err, lr, p.MaxUpdate, p.Damping)
-					//adjustNetworkUpstreamSmart(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					//adjustNetworkBehavioralPulse(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					//adjustNetworkUpstreamNoSignalAdjsutment(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					//adjustNetworkUpstreamDepthScaled(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					//adjustNetworkWaveProp(student, input, err, lr, p.MaxUpdate, p.Damping)
-					//adjustNetworkPulseFlow(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					//adjustNetworkSTDPDirect(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					//adjustNetworkSparseEcho(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					//adjustNetworkHebbError(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					//adjustNetworkMomentum(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					//adjustNetworkFeatureEcho(student, input, err, lr, p.MaxUpdate, p.Damping)
-
-					/*for k := 0; k < 3; k++ {
-						adjustNetworkPhaseTunedContrast(student, input, err, lr, p.MaxUpdate, p.Damping, k)
-
-					}*/
+					update.Update(student, input, err)
 
 					totalError += err * err
 				}
@@ -102,18 +89,6 @@ func runStudentDistillation(
 			student.EvaluateModel(trainExpected, trainPredicted)
 			currentScore := student.Performance.Score
 
-			// Evaluate teacher
-			var teachExpected, teachPredicted []float64
-			for i := range trainSetInputs {
-				nn.Forward(trainSetInputs[i])
-				pred := float64(paragon.ArgMax(nn.ExtractOutput()))
-				trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0])) // ✅ FIXED
-				teachExpected = append(teachExpected, trueLabel)
-				teachPredicted = append(teachPredicted, pred)
-			}
-			nn.EvaluateModel(teachExpected, teachPredicted)
-			teacherScore := nn.Performance.Score
-
 			var symbol string
 			if currentScore > lastScore {
 				symbol = "⬆"
@@ -131,9 +106,10 @@ func runStudentDistillation(
 func runStudentDistillationPermuteErrLR(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	nn *paragon.Network,
+	nn *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Student Network Permuted Error/LR Sweep (🧪 Experimental Upstream Divergence)----------")
+	teacherOuts := teacherOutputs(nn, trainSetInputs)
 
 	// ✅ Fixed settings
 	maxUpdate := 0.10
@@ -148,13 +124,13 @@ func runStudentDistillationPermuteErrLR(
 	for _, errOffset := range errorVariants {
 		for _, lr := range learningRates {
 			student := createStudentNet()
+			update := newRule("upstream", distill.Params{"lr": lr, "max_update": maxUpdate, "damping": damping})
 
 			// For all training samples
 			for i := range trainSetInputs {
 				input := trainSetInputs[i]
 
-				nn.Forward(input)
-				targetVec := nn.ExtractOutput()
+				targetVec := teacherOuts[i]
 
 				student.Forward(input)
 				predVec := student.ExtractOutput()
@@ -171,7 +147,7 @@ func runStudentDistillationPermuteErrLR(
 						err = -0.1
 					}
 
-					adjustNetworkUpstream(student, input, err, lr, maxUpdate, damping)
+					update.Update(student, input, err)
 				}
 			}
 
@@ -193,7 +169,7 @@ func runStudentDistillationPermuteErrLR(
 func runStudentDistillationPermuteErrLRExtreme(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	nn *paragon.Network,
+	nn *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Student Network Permuted Error/LR Sweep (🧪 Extreme Range Exploration)----------")
 
@@ -208,10 +184,11 @@ func runStudentDistillationPermuteErrLRExtreme(
 	for _, errVal := range errs {
 		for _, lr := range lrs {
 			student := createStudentNet()
+			update := newRule("upstream", distill.Params{"lr": lr, "max_update": maxUpdate, "damping": damping})
 
 			for i := range trainSetInputs {
 				input := trainSetInputs[i]
-				adjustNetworkUpstream(student, input, errVal, lr, maxUpdate, damping)
+				update.Update(student, input, errVal)
 			}
 
 			var expected, predicted []float64
@@ -232,7 +209,7 @@ func runStudentDistillationPermuteErrLRExtreme(
 func studentDistillFromHijackedTargetsSweep(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Hijacked Output Permutation Sweep (🧪 Synthetic Student Trials)----------")
 	fmt.Printf("%-12s %-12s %-12s\n", "Strategy", "RandomSeed", "Student ADHD")
@@ -244,6 +221,7 @@ func studentDistillFromHijackedTargetsSweep(
 		for _, seed := range seeds {
 			rand.Seed(int64(seed))
 			student := createStudentNet()
+			update := newRule("upstream", distill.Params{"lr": 0.02, "max_update": 0.1, "damping": 0.01})
 
 			for i := range trainSetInputs {
 				input := trainSetInputs[i]
@@ -276,7 +254,7 @@ func studentDistillFromHijackedTargetsSweep(
 				// Error signal
 				for j := range synth {
 					err := synth[j] - studentOut[j]
-					adjustNetworkUpstream(student, input, err, 0.02, 0.1, 0.01)
+					update.Update(student, input, err)
 				}
 			}
 
@@ -298,7 +276,7 @@ func studentDistillFromHijackedTargetsSweep(
 func studentDistillFromHijackedTargetsSweepProxyMod(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Hijacked Output + Proxy Sweep (🧪 Signal Injection Combinations)----------")
 	fmt.Printf("%-12s %-12s %-12s %-12s\n", "Strategy", "Seed", "ProxyMod", "ADHD")
@@ -312,6 +290,7 @@ func studentDistillFromHijackedTargetsSweepProxyMod(
 			for _, proxyMod := range proxyMods {
 				rand.Seed(int64(seed))
 				student := createStudentNet()
+				update := newRule("upstream", distill.Params{"lr": 0.02, "max_update": 0.1, "damping": 0.01, "proxy_mod": proxyMod})
 
 				for i := range trainSetInputs {
 					input := trainSetInputs[i]
@@ -344,7 +323,7 @@ func studentDistillFromHijackedTargetsSweepProxyMod(
 					// Apply adjustments using proxyMod variation
 					for j := range synth {
 						err := synth[j] - studentOut[j]
-						adjustNetworkUpstreamModulated(student, input, err, 0.02, 0.1, 0.01, proxyMod)
+						update.Update(student, input, err)
 					}
 				}
 
@@ -368,9 +347,10 @@ func studentDistillFromHijackedTargetsSweepProxyMod(
 func experimentalPermutationSweep(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Tri-Axis Experimental Permutation Sweep (🧪 Proxy, Entropy, Reinforce)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 	fmt.Printf("%-12s %-12s %-14s %-12s\n", "ProxyMod", "Entropy", "Reinforce", "ADHD")
 
 	proxyMods := []float64{-1.0, 0.0, 1.0}
@@ -381,11 +361,11 @@ func experimentalPermutationSweep(
 		for _, entropy := range entropyModes {
 			for _, reinforce := range reinforceModes {
 				student := createStudentNet()
+				update := newRule("upstream", distill.Params{"lr": 0.02, "max_update": 0.1, "damping": 0.01, "proxy_mod": proxy})
 
 				for i := range trainSetInputs {
 					input := trainSetInputs[i]
-					teacher.Forward(input)
-					teacherOut := teacher.ExtractOutput()
+					teacherOut := teacherOuts[i]
 
 					student.Forward(input)
 					studentOut := student.ExtractOutput()
@@ -402,7 +382,7 @@ func experimentalPermutationSweep(
 							err *= rand.Float64()
 						}
 
-						adjustNetworkUpstreamModulated(student, input, err, 0.02, 0.1, 0.01, proxy)
+						update.Update(student, input, err)
 					}
 				}
 
@@ -425,9 +405,10 @@ func experimentalPermutationSweep(
 func hybridStudentDistillationSweep(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Hybrid Distillation Sweep (🧪 Pushing ADHD > 50)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 	fmt.Printf("%-12s %-10s %-10s %-6s %-10s\n", "ProxyMod", "Entropy", "Reinforce", "TopK", "ADHD")
 
 	proxyMods := []float64{-0.5, 0.0, 0.5}
@@ -442,12 +423,12 @@ func hybridStudentDistillationSweep(
 		for _, reinforce := range reinforceOptions {
 			for _, entropy := range entropyOptions {
 				student := createStudentNet()
+				update := newRule("upstream", distill.Params{"lr": lr, "max_update": maxUpdate, "damping": damping, "proxy_mod": proxyMod})
 
 				for i := range trainSetInputs {
 					input := trainSetInputs[i]
 
-					teacher.Forward(input)
-					tOut := teacher.ExtractOutput()
+					tOut := teacherOuts[i]
 
 					var targetVec []float64
 					if entropy {
@@ -489,7 +470,7 @@ func hybridStudentDistillationSweep(
 						if reinforce {
 							err *= (0.8 + 0.4*rand.Float64()) // [0.8, 1.2]
 						}
-						adjustNetworkUpstreamModulated(student, input, err, lr, maxUpdate, damping, proxyMod)
+						update.Update(student, input, err)
 					}
 				}
 
@@ -5,44 +5,73 @@ import (
 	"log"
 	"math"
 	"math/rand/v2"
+	"os"
+	"path/filepath"
 	"sort"
 
+	"arena/datasets/mnist"
+	"arena/rng"
 	"paragon"
 )
 
 const (
-	baseURL   = "https://storage.googleapis.com/cvdf-datasets/mnist/"
 	mnistDir  = "mnist_data"
 	modelDir  = "models"
 	modelFile = "mnist_model.json"
 )
 
+// seeds is the run's stream family; noise drives the random input mutations
+var (
+	seeds *rng.Seeds
+	noise *rand.Rand
+)
+
 func main() {
+	seeds = rng.FromEnv()
+	defer seeds.Save("invert2", "")
+	noise = rand.New(rand.NewPCG(uint64(seeds.Seed(rng.Noise)), 0))
+
 	// --- Prepare MNIST ---
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainInputs, trainTargets, err := loadMNISTData(mnistDir, true)
+	trainInputs, trainTargets, err := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
 	if err != nil {
 		log.Fatalf("Training load failed: %v", err)
 	}
-	testInputs, testTargets, err := loadMNISTData(mnistDir, false)
+	testInputs, testTargets, err := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	if err != nil {
 		log.Fatalf("Test load failed: %v", err)
 	}
-	trainSetInputs, trainSetTargets, _, _ := paragon.SplitDataset(trainInputs, trainTargets, 0.8)
+	// MNIST's training file is already shuffled, so the first 80% is the
+	// split; it is the same every run, as the teacher cache needs
+	n := len(trainInputs) * 8 / 10
+	trainSetInputs, trainSetTargets := trainInputs[:n], trainTargets[:n]
 
 	// --- Build Model ---
 	layerSizes := []struct{ Width, Height int }{{28, 28}, {16, 16}, {10, 1}}
 	activations := []string{"leaky_relu", "leaky_relu", "softmax"}
 	fullyConnected := []bool{true, false, true}
-	//modelPath := filepath.Join(modelDir, modelFile)
-
-	var nn *paragon.Network
-	fmt.Println("🧠 No pre-trained model found. Starting training...")
-	nn = paragon.NewNetwork(layerSizes, activations, fullyConnected)
-	nn.Train(trainSetInputs, trainSetTargets, 10, 0.01, true)
-	fmt.Println("✅ Training complete.")
+	modelPath := filepath.Join(modelDir, modelFile)
+
+	// The teacher is trained once and reused, so its ModelHash, and with it
+	// the cached teacher outputs, carry over between runs
+	nn := &paragon.Network[float64]{}
+	if err := nn.LoadJSON(modelPath); err == nil {
+		fmt.Println("📂 Loaded pre-trained model from", modelPath)
+	} else {
+		fmt.Println("🧠 No pre-trained model found. Starting training...")
+		nn = paragon.NewNetwork[float64](layerSizes, activations, fullyConnected, seeds.Seed(rng.Init))
+		nn.Train(trainSetInputs, trainSetTargets, 10, 0.01, true, 5, -5)
+		fmt.Println("✅ Training complete.")
+		if err := os.MkdirAll(modelDir, 0755); err != nil {
+			log.Fatalf("Saving model failed: %v", err)
+		}
+		if err := nn.SaveJSON(modelPath); err != nil {
+			log.Fatalf("Saving model failed: %v", err)
+		}
+		fmt.Println("💾 Model saved to", modelPath)
+	}
 
 	// --- ADHD Evaluation ---
 	var expected, predicted []float64
@@ -80,7 +109,7 @@ func main() {
 	perSample := paragon.ComputePerSamplePerformance(expectedVectors, actualVectors, 0.01, nn)
 	paragon.PrintSampleDiagnostics(perSample, 0.01)
 
-	//runStudentDistillation(trainSetInputs, trainSetTargets, nn)
+	//runStudentDistillation(trainSetInputs, trainSetTargets, nn, "upstream")
 
 	//lets have some fun lol
 	//runStudentDistillationPermuteErrLR(trainSetInputs, trainSetTargets, nn)
@@ -88,7 +117,9 @@ func main() {
 	//studentDistillFromHijackedTargetsSweep(trainSetInputs, trainSetTargets, nn)
 	//studentDistillFromHijackedTargetsSweepProxyMod(trainSetInputs, trainSetTargets, nn)
 	//experimentalPermutationSweep(trainSetInputs, trainSetTargets, nn)
-	hybridStudentDistillationSweep(trainSetInputs, trainSetTargets, nn)
+	//hybridStudentDistillationSweep(trainSetInputs, trainSetTargets, nn)
+	runTournament(trainSetInputs, testInputs, testTargets, nn)
+	runQueryBudget(trainSetInputs, testInputs, testTargets, nn)
 
 	//projectiveDistillationUpstream(trainSetInputs, trainSetTargets, nn)
 	//echoDistillationPulse(trainSetInputs, trainSetTargets, nn)
@@ -102,106 +133,15 @@ func main() {
 	//multiverseInversionAblation(trainSetInputs, trainSetTargets, nn)
 }
 
-func adjustNetworkUpstream(net *paragon.Network, input [][]float64, error float64, lr float64, maxUpdate float64, damping float64) {
-	// Use proxy signal: mean pixel value from input
-	var proxySignal float64
-	count := 0
-	for _, row := range input {
-		for _, v := range row {
-			proxySignal += v
-			count++
-		}
-	}
-	if count > 0 {
-		proxySignal /= float64(count)
-	}
-
-	for layerIndex := net.OutputLayer; layerIndex > 0; layerIndex-- {
-		layer := &net.Layers[layerIndex]
-
-		for y := 0; y < layer.Height; y++ {
-			for x := 0; x < layer.Width; x++ {
-				neuron := layer.Neurons[y][x]
-				adj := lr * error * damping
-
-				if adj > maxUpdate {
-					adj = maxUpdate
-				} else if adj < -maxUpdate {
-					adj = -maxUpdate
-				}
-
-				neuron.Bias += adj
-
-				for i := range neuron.Inputs {
-					neuron.Inputs[i].Weight += adj * proxySignal
-				}
-			}
-		}
-
-		proxySignal *= 0.9
-	}
-}
-
-func adjustNetworkUpstreamModulated(
-	net *paragon.Network,
-	input [][]float64,
-	error float64,
-	lr float64,
-	maxUpdate float64,
-	damping float64,
-	proxyMod float64, // ← new: multiplier on proxy signal direction
-) {
-	// Step 1: Derive proxy signal from the input
-	var proxySignal float64
-	count := 0
-	for _, row := range input {
-		for _, v := range row {
-			proxySignal += v
-			count++
-		}
-	}
-	if count > 0 {
-		proxySignal /= float64(count)
-	}
-
-	// Step 2: Modulate signal (directional/intentional)
-	proxySignal *= proxyMod // ← direct multiplier (can be <0, >1, etc.)
-
-	// Step 3: Backward layer update using modulated signal
-	for layerIndex := net.OutputLayer; layerIndex > 0; layerIndex-- {
-		layer := &net.Layers[layerIndex]
-
-		for y := 0; y < layer.Height; y++ {
-			for x := 0; x < layer.Width; x++ {
-				neuron := layer.Neurons[y][x]
-
-				adj := lr * error * damping
-				if adj > maxUpdate {
-					adj = maxUpdate
-				} else if adj < -maxUpdate {
-					adj = -maxUpdate
-				}
-
-				neuron.Bias += adj
-
-				for i := range neuron.Inputs {
-					neuron.Inputs[i].Weight += adj * proxySignal
-				}
-			}
-		}
-
-		// Optionally decay the signal (mimicking depth falloff)
-		proxySignal *= 0.9
-	}
-}
-
-func createStudentNet() *paragon.Network {
-
-	layerSizes := []struct{ Width, Height int }{{28, 28}, {16, 16}, {10, 1}}
-	activations := []string{"leaky_relu", "leaky_relu", "softmax"}
-	fullyConnected := []bool{true, false, true}
+// The student has the teacher's shape but none of its weights
+var (
+	studentSizes = []struct{ Width, Height int }{{28, 28}, {16, 16}, {10, 1}}
+	studentActs  = []string{"leaky_relu", "leaky_relu", "softmax"}
+	studentFC    = []bool{true, false, true}
+)
 
-	nn := paragon.NewNetwork(layerSizes, activations, fullyConnected)
+func createStudentNet() *paragon.Network[float64] {
+	nn := paragon.NewNetwork[float64](studentSizes, studentActs, studentFC)
 
 	return nn
 }
@@ -209,9 +149,10 @@ func createStudentNet() *paragon.Network {
 func projectiveDistillationUpstream(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Projective Distillation Upstream (Black-Box Mimicry)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 
 	student := createStudentNet()
 	lr := 0.02
@@ -226,8 +167,7 @@ func projectiveDistillationUpstream(
 			input := trainSetInputs[i]
 
 			// Behavior only: input → output
-			teacher.Forward(input)
-			teacherOutput := teacher.ExtractOutput()
+			teacherOutput := teacherOuts[i]
 
 			student.Forward(input)
 			studentOutput := student.ExtractOutput()
@@ -274,16 +214,7 @@ func projectiveDistillationUpstream(
 		studentScore := student.Performance.Score
 
 		// Evaluate teacher (as reference)
-		var teacherExpected, teacherPredicted []float64
-		for i := range trainSetInputs {
-			teacher.Forward(trainSetInputs[i])
-			pred := float64(paragon.ArgMax(teacher.ExtractOutput()))
-			trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0]))
-			teacherExpected = append(teacherExpected, trueLabel)
-			teacherPredicted = append(teacherPredicted, pred)
-		}
-		teacher.EvaluateModel(teacherExpected, teacherPredicted)
-		teacherScore := teacher.Performance.Score
+		teacherScore := teacherADHD(teacher, trainSetInputs, trainSetTargets)
 
 		var symbol string
 		if studentScore > lastScore {
@@ -311,9 +242,10 @@ func clamp(val, min, max float64) float64 {
 func echoDistillationPulse(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Echo Distillation Pulse (Temporal Feedback Mimicry)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 
 	student := createStudentNet()
 	lr := 0.02
@@ -327,8 +259,7 @@ func echoDistillationPulse(
 		for i := range trainSetInputs {
 			input := trainSetInputs[i]
 
-			teacher.Forward(input)
-			tOut := teacher.ExtractOutput()
+			tOut := teacherOuts[i]
 
 			student.Forward(input)
 			sOut := student.ExtractOutput()
@@ -351,7 +282,7 @@ func echoDistillationPulse(
 						for x := 0; x < layer.Width; x++ {
 							neu := layer.Neurons[y][x]
 
-							if rand.Float64() < 0.2 && math.Abs(neu.Value) < 0.05 {
+							if noise.Float64() < 0.2 && math.Abs(neu.Value) < 0.05 {
 								continue // skip low-signal nodes
 							}
 
@@ -383,16 +314,7 @@ func echoDistillationPulse(
 		studentScore := student.Performance.Score
 
 		// Evaluate teacher
-		var teacherExpected, teacherPredicted []float64
-		for i := range trainSetInputs {
-			teacher.Forward(trainSetInputs[i])
-			pred := float64(paragon.ArgMax(teacher.ExtractOutput()))
-			trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0]))
-			teacherExpected = append(teacherExpected, trueLabel)
-			teacherPredicted = append(teacherPredicted, pred)
-		}
-		teacher.EvaluateModel(teacherExpected, teacherPredicted)
-		teacherScore := teacher.Performance.Score
+		teacherScore := teacherADHD(teacher, trainSetInputs, trainSetTargets)
 
 		var symbol string
 		if studentScore > lastScore {
@@ -410,9 +332,10 @@ func echoDistillationPulse(
 func reverseCausalTraceAlign(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Reverse Causal Trace Align (Behavioral Flow Rewiring)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 
 	student := createStudentNet()
 	lr := 0.015
@@ -427,8 +350,7 @@ func reverseCausalTraceAlign(
 			input := trainSetInputs[i]
 
 			// Observe only behavior
-			teacher.Forward(input)
-			teacherOut := teacher.ExtractOutput()
+			teacherOut := teacherOuts[i]
 
 			student.Forward(input)
 			studentOut := student.ExtractOutput()
@@ -489,16 +411,7 @@ func reverseCausalTraceAlign(
 		studentScore := student.Performance.Score
 
 		// Evaluate teacher
-		var teacherExpected, teacherPredicted []float64
-		for i := range trainSetInputs {
-			teacher.Forward(trainSetInputs[i])
-			pred := float64(paragon.ArgMax(teacher.ExtractOutput()))
-			trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0]))
-			teacherExpected = append(teacherExpected, trueLabel)
-			teacherPredicted = append(teacherPredicted, pred)
-		}
-		teacher.EvaluateModel(teacherExpected, teacherPredicted)
-		teacherScore := teacher.Performance.Score
+		teacherScore := teacherADHD(teacher, trainSetInputs, trainSetTargets)
 
 		var symbol string
 		if studentScore > lastScore {
@@ -516,9 +429,10 @@ func reverseCausalTraceAlign(
 func errorSculptPropagation(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Error Sculpt Propagation (Activation-Weighted Rewiring)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 
 	student := createStudentNet()
 	lr := 10.03
@@ -531,8 +445,7 @@ func errorSculptPropagation(
 		for i := range trainSetInputs {
 			input := trainSetInputs[i]
 
-			teacher.Forward(input)
-			tOut := teacher.ExtractOutput()
+			tOut := teacherOuts[i]
 
 			student.Forward(input)
 			sOut := student.ExtractOutput()
@@ -587,16 +500,7 @@ func errorSculptPropagation(
 		studentScore := student.Performance.Score
 
 		// Evaluate teacher
-		var teacherExpected, teacherPredicted []float64
-		for i := range trainSetInputs {
-			teacher.Forward(trainSetInputs[i])
-			pred := float64(paragon.ArgMax(teacher.ExtractOutput()))
-			trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0]))
-			teacherExpected = append(teacherExpected, trueLabel)
-			teacherPredicted = append(teacherPredicted, pred)
-		}
-		teacher.EvaluateModel(teacherExpected, teacherPredicted)
-		teacherScore := teacher.Performance.Score
+		teacherScore := teacherADHD(teacher, trainSetInputs, trainSetTargets)
 
 		var symbol string
 		if studentScore > lastScore {
@@ -614,9 +518,10 @@ func errorSculptPropagation(
 func eventTraceAlignment(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Event Trace Alignment (Trace-and-Resonate Conditioning)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 
 	student := createStudentNet()
 	lr := 0.03
@@ -630,8 +535,7 @@ func eventTraceAlignment(
 			input := trainSetInputs[i]
 
 			// Teacher behavior
-			teacher.Forward(input)
-			teacherOut := teacher.ExtractOutput()
+			teacherOut := teacherOuts[i]
 			targetClass := paragon.ArgMax(teacherOut)
 
 			// Student behavior and trace
@@ -679,16 +583,7 @@ func eventTraceAlignment(
 		studentScore := student.Performance.Score
 
 		// Evaluate teacher
-		var teacherExpected, teacherPredicted []float64
-		for i := range trainSetInputs {
-			teacher.Forward(trainSetInputs[i])
-			pred := float64(paragon.ArgMax(teacher.ExtractOutput()))
-			trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0]))
-			teacherExpected = append(teacherExpected, trueLabel)
-			teacherPredicted = append(teacherPredicted, pred)
-		}
-		teacher.EvaluateModel(teacherExpected, teacherPredicted)
-		teacherScore := teacher.Performance.Score
+		teacherScore := teacherADHD(teacher, trainSetInputs, trainSetTargets)
 
 		var symbol string
 		if studentScore > lastScore {
@@ -706,9 +601,10 @@ func eventTraceAlignment(
 func eventTraceAlignTopK(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Event Trace Alignment Top-K (Selective Influence Reinforcement)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 
 	student := createStudentNet()
 	lr := 0.03
@@ -723,8 +619,7 @@ func eventTraceAlignTopK(
 			input := trainSetInputs[i]
 
 			// Teacher behavior
-			teacher.Forward(input)
-			teacherOut := teacher.ExtractOutput()
+			teacherOut := teacherOuts[i]
 			targetClass := paragon.ArgMax(teacherOut)
 
 			// Student behavior and trace
@@ -734,7 +629,7 @@ func eventTraceAlignTopK(
 				layer := &student.Layers[layerIndex]
 
 				type traceNode struct {
-					Neuron *paragon.Neuron
+					Neuron *paragon.Neuron[float64]
 					X, Y   int
 					Value  float64
 				}
@@ -787,16 +682,7 @@ func eventTraceAlignTopK(
 		studentScore := student.Performance.Score
 
 		// Evaluate teacher
-		var teacherExpected, teacherPredicted []float64
-		for i := range trainSetInputs {
-			teacher.Forward(trainSetInputs[i])
-			pred := float64(paragon.ArgMax(teacher.ExtractOutput()))
-			trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0]))
-			teacherExpected = append(teacherExpected, trueLabel)
-			teacherPredicted = append(teacherPredicted, pred)
-		}
-		teacher.EvaluateModel(teacherExpected, teacherPredicted)
-		teacherScore := teacher.Performance.Score
+		teacherScore := teacherADHD(teacher, trainSetInputs, trainSetTargets)
 
 		var symbol string
 		if studentScore > lastScore {
@@ -814,9 +700,10 @@ func eventTraceAlignTopK(
 func correlationTraceAdjustment(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Correlation Trace Adjustment (Causal Contribution Rewiring)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 
 	student := createStudentNet()
 	lr := 0.02
@@ -829,8 +716,7 @@ func correlationTraceAdjustment(
 		for i := range trainSetInputs {
 			input := trainSetInputs[i]
 
-			teacher.Forward(input)
-			teacherOut := teacher.ExtractOutput()
+			teacherOut := teacherOuts[i]
 
 			student.Forward(input)
 			studentOut := student.ExtractOutput()
@@ -891,16 +777,7 @@ func correlationTraceAdjustment(
 		studentScore := student.Performance.Score
 
 		// Evaluate teacher
-		var teacherExpected, teacherPredicted []float64
-		for i := range trainSetInputs {
-			teacher.Forward(trainSetInputs[i])
-			pred := float64(paragon.ArgMax(teacher.ExtractOutput()))
-			trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0]))
-			teacherExpected = append(teacherExpected, trueLabel)
-			teacherPredicted = append(teacherPredicted, pred)
-		}
-		teacher.EvaluateModel(teacherExpected, teacherPredicted)
-		teacherScore := teacher.Performance.Score
+		teacherScore := teacherADHD(teacher, trainSetInputs, trainSetTargets)
 
 		var symbol string
 		if studentScore > lastScore {
@@ -918,9 +795,10 @@ func correlationTraceAdjustment(
 func correlationTraceReinforceV2(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Correlation Trace Reinforce V2 (Memory-Weighted Conditioning)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 
 	student := createStudentNet()
 	lr := 0.02
@@ -937,8 +815,7 @@ func correlationTraceReinforceV2(
 		for i := range trainSetInputs {
 			input := trainSetInputs[i]
 
-			teacher.Forward(input)
-			teacherOut := teacher.ExtractOutput()
+			teacherOut := teacherOuts[i]
 
 			student.Forward(input)
 			studentOut := student.ExtractOutput()
@@ -1018,16 +895,7 @@ func correlationTraceReinforceV2(
 		studentScore := student.Performance.Score
 
 		// Evaluate teacher
-		var teacherExpected, teacherPredicted []float64
-		for i := range trainSetInputs {
-			teacher.Forward(trainSetInputs[i])
-			pred := float64(paragon.ArgMax(teacher.ExtractOutput()))
-			trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0]))
-			teacherExpected = append(teacherExpected, trueLabel)
-			teacherPredicted = append(teacherPredicted, pred)
-		}
-		teacher.EvaluateModel(teacherExpected, teacherPredicted)
-		teacherScore := teacher.Performance.Score
+		teacherScore := teacherADHD(teacher, trainSetInputs, trainSetTargets)
 
 		var symbol string
 		if studentScore > lastScore {
@@ -1045,9 +913,10 @@ func correlationTraceReinforceV2(
 func latentSpacePulseInjection(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Latent Space Pulse Injection (Attractor-Oriented Mimicry)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 
 	student := createStudentNet()
 	lr := 0.03
@@ -1061,8 +930,7 @@ func latentSpacePulseInjection(
 			input := trainSetInputs[i]
 
 			// Forward teacher and student
-			teacher.Forward(input)
-			teacherOut := teacher.ExtractOutput()
+			teacherOut := teacherOuts[i]
 			teacherConfidence := softmaxSharpness(teacherOut)
 
 			student.Forward(input)
@@ -1116,16 +984,7 @@ func latentSpacePulseInjection(
 		studentScore := student.Performance.Score
 
 		// Evaluate teacher
-		var teacherExpected, teacherPredicted []float64
-		for i := range trainSetInputs {
-			teacher.Forward(trainSetInputs[i])
-			pred := float64(paragon.ArgMax(teacher.ExtractOutput()))
-			trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0]))
-			teacherExpected = append(teacherExpected, trueLabel)
-			teacherPredicted = append(teacherPredicted, pred)
-		}
-		teacher.EvaluateModel(teacherExpected, teacherPredicted)
-		teacherScore := teacher.Performance.Score
+		teacherScore := teacherADHD(teacher, trainSetInputs, trainSetTargets)
 
 		var symbol string
 		if studentScore > lastScore {
@@ -1167,9 +1026,10 @@ func softmaxSharpness(vec []float64) float64 {
 func multiverseInversionAblation(
 	trainSetInputs [][][]float64,
 	trainSetTargets [][][]float64,
-	teacher *paragon.Network,
+	teacher *paragon.Network[float64],
 ) {
 	fmt.Println("\n---------Multiverse Inversion Ablation (Counterfactual Node Salience)----------")
+	teacherOuts := teacherOutputs(teacher, trainSetInputs)
 
 	student := createStudentNet()
 	lr := 0.04
@@ -1182,8 +1042,7 @@ func multiverseInversionAblation(
 		for i := range trainSetInputs {
 			input := trainSetInputs[i]
 
-			teacher.Forward(input)
-			teacherOut := teacher.ExtractOutput()
+			teacherOut := teacherOuts[i]
 			teacherClass := paragon.ArgMax(teacherOut)
 
 			student.Forward(input)
@@ -1244,16 +1103,7 @@ func multiverseInversionAblation(
 		student.EvaluateModel(studentExpected, studentPredicted)
 		studentScore := student.Performance.Score
 
-		var teacherExpected, teacherPredicted []float64
-		for i := range trainSetInputs {
-			teacher.Forward(trainSetInputs[i])
-			pred := float64(paragon.ArgMax(teacher.ExtractOutput()))
-			trueLabel := float64(paragon.ArgMax(trainSetTargets[i][0]))
-			teacherExpected = append(teacherExpected, trueLabel)
-			teacherPredicted = append(teacherPredicted, pred)
-		}
-		teacher.EvaluateModel(teacherExpected, teacherPredicted)
-		teacherScore := teacher.Performance.Score
+		teacherScore := teacherADHD(teacher, trainSetInputs, trainSetTargets)
 
 		var symbol string
 		if studentScore > lastScore {
@@ -1267,3 +1117,12 @@ func multiverseInversionAblation(
 		lastScore = studentScore
 	}
 }
+
+func extractOutput(nn *paragon.Network[float64]) []float64 {
+	outWidth := nn.Layers[nn.OutputLayer].Width
+	out := make([]float64, outWidth)
+	for x := 0; x < outWidth; x++ {
+		out[x] = nn.Layers[nn.OutputLayer].Neurons[0][x].Value
+	}
+	return out
+}
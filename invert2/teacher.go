@@ -0,0 +1,33 @@
+package main
+
+import (
+	"arena/distill"
+	"log"
+	"paragon"
+)
+
+// teacherCache holds the teacher's outputs for the whole run, and on disk
+// for the next run of the same teacher, so the sweeps only pay for their
+// students
+var teacherCache = distill.NewCache(distill.DefaultCacheDir())
+
+// teacherOutputs returns the teacher's output on every training input,
+// running the teacher only on a cache miss
+func teacherOutputs(teacher *paragon.Network[float64], inputs [][][]float64) [][]float64 {
+	out, err := distill.TeacherOutputs(teacherCache, "mnist/invert2-train", teacher, inputs)
+	if err != nil {
+		log.Fatalf("teacher outputs: %v", err)
+	}
+	return out.Raw
+}
+
+// teacherADHD scores the teacher's cached outputs against the labels
+func teacherADHD(teacher *paragon.Network[float64], inputs, targets [][][]float64) float64 {
+	var expected, predicted []float64
+	for i, out := range teacherOutputs(teacher, inputs) {
+		expected = append(expected, float64(paragon.ArgMax(targets[i][0])))
+		predicted = append(predicted, float64(paragon.ArgMax(out)))
+	}
+	teacher.EvaluateModel(expected, predicted)
+	return teacher.Performance.Score
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"arena/distill"
+	"fmt"
+	"log"
+	"os"
+	"paragon"
+)
+
+// Every student in the tournament trains on the first tournamentTrain
+// training inputs and is scored on the first tournamentTest test inputs
+const (
+	tournamentTrain = 2000
+	tournamentTest  = 1000
+	leaderboardFile = "leaderboard.md"
+)
+
+// runTournament distils a student with every rule in arena/distill from
+// the teacher's cached outputs and ranks the rules by agreement
+// with the teacher, ADHD score and wall time
+func runTournament(trainX, testX, testY [][][]float64, teacher *paragon.Network[float64]) {
+	nTrain, nTest := min(tournamentTrain, len(trainX)), min(tournamentTest, len(testX))
+	fmt.Printf("\n🏁 Tournament: %d rules, %d training / %d test samples\n", len(distill.Names()), nTrain, nTest)
+	data, err := distill.CachedData(teacherCache, "mnist/invert2-tournament", teacher, trainX[:nTrain], testX[:nTest], testY[:nTest])
+	if err != nil {
+		log.Fatalf("tournament: %v", err)
+	}
+
+	entries, err := distill.Tournament(distill.Names(), data, distill.Options[float64]{
+		Student: func(seed int64) *paragon.Network[float64] {
+			return paragon.NewNetwork[float64](studentSizes, studentActs, studentFC, seed)
+		},
+		Seeds: seeds.Sub("tournament"),
+	})
+	if err != nil {
+		log.Fatalf("tournament: %v", err)
+	}
+	standings := distill.Standings(entries)
+	title := fmt.Sprintf("DISTILLATION TOURNAMENT (teacher accuracy %.2f%%)", data.TeacherAccuracy())
+	if err := distill.WriteLeaderboard(os.Stdout, title, standings); err != nil {
+		log.Fatalf("tournament: %v", err)
+	}
+
+	header := fmt.Sprintf("Teacher accuracy %.2f%%; %d training / %d test samples, master seed %d.\n\n",
+		data.TeacherAccuracy(), nTrain, nTest, seeds.Master())
+	if err := writeLeaderboard(leaderboardFile, header, standings); err != nil {
+		log.Fatalf("tournament: %v", err)
+	}
+	fmt.Printf("📄 Leaderboard written to %s\n", leaderboardFile)
+}
+
+// writeLeaderboard writes header and the standings as markdown to path;
+// a failed write or close is an error, so a truncated leaderboard is not
+// reported as written
+func writeLeaderboard(path, header string, standings []distill.Standing) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(f, header)
+	if err == nil {
+		err = distill.WriteMarkdown(f, standings)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
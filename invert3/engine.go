@@ -4,27 +4,33 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"math/rand/v2"
 
+	"arena/datasets/mnist"
+	"arena/rng"
 	"paragon"
 )
 
 const (
-	baseURL   = "https://storage.googleapis.com/cvdf-datasets/mnist/"
 	mnistDir  = "mnist_data"
 	modelDir  = "models"
 	modelFile = "mnist_model.json"
 )
 
 func main() {
+	seeds := rng.FromEnv()
+	defer seeds.Save("invert3", "")
+	noise = rand.New(rand.NewPCG(uint64(seeds.Seed(rng.Noise)), 0))
+
 	// --- Prepare MNIST ---
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainInputs, trainTargets, err := loadMNISTData(mnistDir, true)
+	trainInputs, trainTargets, err := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
 	if err != nil {
 		log.Fatalf("Training load failed: %v", err)
 	}
-	testInputs, testTargets, err := loadMNISTData(mnistDir, false)
+	testInputs, testTargets, err := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	if err != nil {
 		log.Fatalf("Test load failed: %v", err)
 	}
@@ -169,3 +175,12 @@ func runStudentDistillation(
 			p.MaxUpdate, p.Damping, teacherScore, student.Performance.Score)
 	}
 }
+
+func extractOutput(nn *paragon.Network) []float64 {
+	outWidth := nn.Layers[nn.OutputLayer].Width
+	out := make([]float64, outWidth)
+	for x := 0; x < outWidth; x++ {
+		out[x] = nn.Layers[nn.OutputLayer].Neurons[0][x].Value
+	}
+	return out
+}
@@ -7,6 +7,10 @@ import (
 	"paragon"
 )
 
+// noise generates the random inputs and targets of the mini tests; main
+// seeds it from the run's master seed
+var noise *rand.Rand
+
 // --- Mini Experiment 1: Random Mapping ---
 
 func distillRandomMapping() {
@@ -247,5 +251,5 @@ func generateRandomOutputs(n, dim int) [][]float64 {
 }
 
 func randFloat(min, max float64) float64 {
-	return min + (max-min)*rand.Float64()
+	return min + (max-min)*noise.Float64()
 }
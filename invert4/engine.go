@@ -4,11 +4,11 @@ import (
 	"fmt"
 	"log"
 
+	"arena/datasets/mnist"
 	"paragon"
 )
 
 const (
-	baseURL   = "https://storage.googleapis.com/cvdf-datasets/mnist/"
 	mnistDir  = "mnist_data"
 	modelDir  = "models"
 	modelFile = "mnist_model.json"
@@ -16,14 +16,14 @@ const (
 
 func main() {
 	// --- Load MNIST ---
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainInputs, trainTargets, err := loadMNISTData(mnistDir, true)
+	trainInputs, trainTargets, err := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
 	if err != nil {
 		log.Fatalf("Training load failed: %v", err)
 	}
-	testInputs, testTargets, err := loadMNISTData(mnistDir, false)
+	testInputs, testTargets, err := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	if err != nil {
 		log.Fatalf("Test load failed: %v", err)
 	}
@@ -325,3 +325,12 @@ func RunSandwichTraining(dataset [][][]float64, targets [][][]float64, student *
 		}
 	}
 }
+
+func extractOutput(nn *paragon.Network) []float64 {
+	outWidth := nn.Layers[nn.OutputLayer].Width
+	out := make([]float64, outWidth)
+	for x := 0; x < outWidth; x++ {
+		out[x] = nn.Layers[nn.OutputLayer].Neurons[0][x].Value
+	}
+	return out
+}
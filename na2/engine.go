@@ -1,16 +1,12 @@
 package main
 
 import (
-	"crypto/md5"
-	"encoding/hex"
+	"arena/datasets/cache"
 	"fmt"
-	"io"
 	"math"
 	"math/rand"
-	"net/http"
 	"os"
 	"paragon"
-	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
@@ -31,42 +27,17 @@ func generateSentences(n int) []string {
 	return sentences
 }
 
-// downloadText downloads text from a URL or reads it from a local file if available.
-func downloadText(url string) ([]string, error) {
-	dir := "data"
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create data directory: %v", err)
-	}
-	hash := md5.Sum([]byte(url))
-	filename := hex.EncodeToString(hash[:])
-	filepath := filepath.Join(dir, filename)
-
-	if _, err := os.Stat(filepath); err == nil {
-		content, err := os.ReadFile(filepath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read local file: %v", err)
-		}
-		text := string(content)
-		return splitIntoSentences(text), nil
-	}
-
-	resp, err := http.Get(url)
+// downloadText reads a text source through the dataset cache.
+func downloadText(src cache.Source) ([]string, error) {
+	path, err := cache.Default().Fetch(src)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download text: %v", err)
+		return nil, fmt.Errorf("failed to fetch text: %v", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
-	}
-
-	if err := os.WriteFile(filepath, body, 0644); err != nil {
-		return nil, fmt.Errorf("failed to save file: %v", err)
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
 	}
-
-	text := string(body)
-	return splitIntoSentences(text), nil
+	return splitIntoSentences(string(content)), nil
 }
 
 // splitIntoSentences splits text into sentences and filters out empty ones.
@@ -85,8 +56,7 @@ func splitIntoSentences(text string) []string {
 func main() {
 	fmt.Println("V5-IMPLEMENTATION-NA1-DIFFUSION-TRANSFORMER-MASKED")
 
-	/*url := "https://www.gutenberg.org/files/28/28-0.txt" // Pride and Prejudice as an example
-	additionalSentences, err := downloadText(url)
+	/*additionalSentences, err := downloadText(cache.GutenbergBook28)
 	if err != nil {
 		fmt.Printf("Error downloading text: %v\n", err)
 		return
@@ -1,17 +1,19 @@
 package main
 
 import (
+	"arena/rng"
 	"fmt"
 	"math"
 	"math/rand"
-	"time"
 
 	"paragon" // Replace with actual import path
 )
 
 func main() {
 	// Seed random number generator
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("na3", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	// Define small dataset
 	sentences := []string{
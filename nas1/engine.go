@@ -1,17 +1,30 @@
 package main
 
 import (
+	"arena/experiment"
 	"fmt"
 	"paragon"
 )
 
+// lstConvCubes is the converted cubes table every step trains on.
+var lstConvCubes [][]string
+
 func main() {
+	experiment.Before(loadTables)
+	experiment.Register("basic", "pad cube features and train a 99→12 reconstruction net",
+		experiment.Func(func() { basic(lstConvCubes) }))
+	experiment.Main("nas1")
+}
+
+// loadTables reads, cleans and prints cubes.csv and links.csv.
+func loadTables(*experiment.Context) error {
 	fmt.Println("Building partitioning nas")
 
 	// Read and process cubes.csv
 	cubes, _ := paragon.ReadCSV("cubes.csv")
 	lstCubes, _ := paragon.Cleaner(cubes, []int{0}, []int{})
-	lstLabelCubes, lstConvCubes, _ := paragon.Converter(lstCubes, []int{0})
+	lstLabelCubes, lstConv, _ := paragon.Converter(lstCubes, []int{0})
+	lstConvCubes = lstConv
 
 	paragon.PrintTable(lstCubes)
 	paragon.PrintTable(lstLabelCubes)
@@ -25,8 +38,7 @@ func main() {
 	paragon.PrintTable(lstLinks)
 	paragon.PrintTable(lstLabelLinks)
 	paragon.PrintTable(lstConvLinks)
-	basic(lstConvCubes)
-	//fullNas(lstConvCubes)
+	return nil
 }
 
 func basic(lstTmpData [][]string) {
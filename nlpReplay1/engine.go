@@ -6,6 +6,8 @@ import (
 	"math"
 	"math/rand"
 
+	"arena/gates"
+	"arena/tasks"
 	"paragon"
 )
 
@@ -16,7 +18,6 @@ const (
 	seqLength    = 10
 	numSamples   = 1000
 	numClasses   = 3
-	vocabSize    = 50
 	epochsStd    = 20 // standard / static
 	epochsDyn    = 25 // dynamic gets a few extra sweeps
 	learningRate = 0.0001
@@ -29,8 +30,10 @@ type results struct{ score float64 }
 func main() {
 	rand.Seed(fixedSeed)
 
-	base := buildModel(seqLength, numClasses)
-	ins, tgts := generateNLPClassificationData(numSamples, seqLength, vocabSize, numClasses)
+	// Token classification: recall the marked token of a sequence
+	task := tasks.CopyRecall{Length: seqLength, Symbols: numClasses}
+	base := buildModel(task.Shape())
+	ins, tgts := task.Generate(numSamples, rand.New(rand.NewSource(fixedSeed)))
 
 	log := map[string][]results{
 		"standard": {},
@@ -80,15 +83,8 @@ func main() {
 				layer := &net.Layers[l]
 				layer.ReplayEnabled = true
 				layer.ReplayBudget = budget
-				layer.ReplayGateFunc = EntropyGate(layer)
-				layer.ReplayGateToReps = func(e float64) int {
-					// reps = 1..budget, linear in entropy
-					r := int(1 + e*float64(budget-1))
-					if r < 1 {
-						r = 1
-					}
-					return r
-				}
+				layer.ReplayGateFunc = gates.Entropy(layer)
+				layer.ReplayGateToReps = gates.Linear(budget) // reps = 1..budget, linear in entropy
 			}
 
 			// train exactly ONE epoch each pass so updated budgets apply
@@ -115,9 +111,9 @@ func main() {
 }
 
 // ─────────────── Helpers ───────────────
-func buildModel(seqLen, numClasses int) *paragon.Network {
+func buildModel(width, height, numClasses int) *paragon.Network {
 	layers := []struct{ Width, Height int }{
-		{seqLen, 1},
+		{width, height},
 		{64, 1},
 		{32, 1},
 		{numClasses, 1},
@@ -127,39 +123,6 @@ func buildModel(seqLen, numClasses int) *paragon.Network {
 	return paragon.NewNetwork(layers, acts, full)
 }
 
-func generateNLPClassificationData(n, seqLen, vocabSize, numClasses int) ([][][]float64, [][][]float64) {
-	rng := rand.New(rand.NewSource(fixedSeed))
-	var ins, tgts [][][]float64
-	for i := 0; i < n; i++ {
-		input := [][]float64{make([]float64, seqLen)}
-		for j := 0; j < seqLen; j++ {
-			input[0][j] = float64(rng.Intn(vocabSize)) / float64(vocabSize)
-		}
-		label := rng.Intn(numClasses)
-		target := [][]float64{{0, 0, 0}}
-		target[0][label] = 1
-		ins = append(ins, input)
-		tgts = append(tgts, target)
-	}
-	return ins, tgts
-}
-
-func EntropyGate(layer *paragon.Grid) func([][]float64) float64 {
-	return func(_ [][]float64) float64 {
-		if len(layer.CachedOutputs) == 0 {
-			return 0
-		}
-		out := paragon.Softmax(layer.CachedOutputs)
-		h := 0.0
-		for _, p := range out {
-			if p > 0 {
-				h -= p * math.Log2(p)
-			}
-		}
-		return h / math.Log2(float64(len(out))) // 0‑1
-	}
-}
-
 func evaluate(net *paragon.Network, inputs, targets [][][]float64) results {
 	exp, pred := []float64{}, []float64{}
 	for i, in := range inputs {
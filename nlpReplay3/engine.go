@@ -2,20 +2,19 @@ package main
 
 import (
 	"bufio"
-	"bytes"
 	"fmt"
-	"io"
 	"math"
 	"math/rand"
-	"net/http"
+	"os"
 	"runtime"
 	"strings"
 
+	"arena/datasets/cache"
+	"arena/gates"
 	"paragon"
 )
 
 const (
-	bookURL        = "https://www.gutenberg.org/cache/epub/1661/pg1661.txt"
 	keepParagraphs = 300
 	seqLen         = 6
 	maxVocab       = 256
@@ -45,7 +44,7 @@ func main() {
 	rand.Seed(fixedSeed)
 
 	fmt.Println("Fetching text...")
-	paras := fetchParagraphs(bookURL, keepParagraphs)
+	paras := fetchParagraphs(cache.GutenbergSherlock, keepParagraphs)
 
 	fmt.Println("Learning BPE merges…")
 	bpe = TrainBPE(paras, maxVocab)
@@ -210,12 +209,16 @@ func prepareData(bpe *BPE, paras []string) (trainX, trainY, valX, valY [][][]flo
 	return
 }
 
-func fetchParagraphs(url string, n int) []string {
-	resp, _ := http.Get(url)
-	defer resp.Body.Close()
-	buf := new(bytes.Buffer)
-	io.Copy(buf, resp.Body)
-	all := paragraphs(buf.String())
+func fetchParagraphs(src cache.Source, n int) []string {
+	path, err := cache.Default().Fetch(src)
+	if err != nil {
+		panic(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	all := paragraphs(string(data))
 	rand.Shuffle(len(all), func(i, j int) { all[i], all[j] = all[j], all[i] })
 	if n > len(all) {
 		n = len(all)
@@ -269,10 +272,8 @@ func trainDynamic(net *paragon.Network, x, y [][][]float64) {
 			layer := &net.Layers[l]
 			layer.ReplayEnabled = true
 			layer.ReplayBudget = budget
-			layer.ReplayGateFunc = EntropyGate(layer)
-			layer.ReplayGateToReps = func(e float64) int {
-				return 1 + int(math.Pow(e, 1.1)*float64(layer.ReplayBudget-1))
-			}
+			layer.ReplayGateFunc = gates.Entropy(layer)
+			layer.ReplayGateToReps = gates.Power(budget, 1.1)
 		}
 		lr := cosineLR(ep, epochsDyn)
 		net.Train(x, y, 1, lr, false)
@@ -372,22 +373,6 @@ func buildModel(in, out int) *paragon.Network {
 	return paragon.NewNetwork(layers, acts, full)
 }
 
-func EntropyGate(layer *paragon.Grid) func([][]float64) float64 {
-	return func(_ [][]float64) float64 {
-		if len(layer.CachedOutputs) == 0 {
-			return 0
-		}
-		out := paragon.Softmax(layer.CachedOutputs)
-		h := 0.0
-		for _, p := range out {
-			if p > 0 {
-				h -= p * math.Log2(p)
-			}
-		}
-		return h / math.Log2(float64(len(out)))
-	}
-}
-
 func Clone(n *paragon.Network) *paragon.Network {
 	b, _ := n.MarshalJSONModel()
 	c := &paragon.Network{}
@@ -419,10 +404,8 @@ func trainWithProgress(net *paragon.Network, x, y [][][]float64, epochs int, mod
 				layer := &net.Layers[l]
 				layer.ReplayEnabled = true
 				layer.ReplayBudget = budget
-				layer.ReplayGateFunc = EntropyGate(layer)
-				layer.ReplayGateToReps = func(e float64) int {
-					return 1 + int(math.Pow(e, 1.1)*float64(layer.ReplayBudget-1))
-				}
+				layer.ReplayGateFunc = gates.Entropy(layer)
+				layer.ReplayGateToReps = gates.Power(budget, 1.1)
 			}
 		}
 
@@ -2,7 +2,10 @@ module main
 
 go 1.24.0
 
-require paragon v0.0.0
+require (
+	arena v0.0.0
+	paragon v0.0.0
+)
 
 require (
 	github.com/eliben/go-sentencepiece v0.6.0 // indirect
@@ -11,3 +14,5 @@ require (
 )
 
 replace paragon => ../../
+
+replace arena => ../arena
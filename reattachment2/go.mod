@@ -2,8 +2,13 @@ module main
 
 go 1.24.3
 
-require paragon v0.0.0
+require (
+	arena v0.0.0
+	paragon v0.0.0
+)
 
 require github.com/openfluke/webgpu v0.0.0-20250602005907-ad2e76f7888f // indirect
 
 replace paragon => ../../
+
+replace arena => ../arena
@@ -1,16 +1,18 @@
 package main
 
 import (
+	"arena/rng"
 	"fmt"
 	"math/rand"
 	"runtime"
-	"time"
 
 	"paragon"
 )
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("reattachment2", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	fmt.Println("=== Grow() Function Test with ADHD Evaluation ===")
 
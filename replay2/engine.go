@@ -1,6 +1,9 @@
 package main
 
 import (
+	"arena/datasets/mnist"
+	"arena/experiment"
+	"arena/rng"
 	"fmt"
 	"log"
 	"math"
@@ -8,35 +11,54 @@ import (
 	"paragon"
 	"runtime"
 	"sync"
-	"time"
 )
 
 // -------------------------------------------------- data helpers you already have
 const (
-	baseURL   = "https://storage.googleapis.com/cvdf-datasets/mnist/"
 	mnistDir  = "mnist_data"
 	modelDir  = "models"
 	modelFile = "mnist_model.json"
 )
 
 // -------------------------------------------------- main
+// seeds holds the current step's streams; every shuffle and weight seed
+// draws from it so a step can be rerun exactly with -seed
+var seeds *rng.Seeds
+
 func main() {
-	singleCompare()
-	benchmarkReplayVsBaseline()
-	benchmarkReplayVsBaselineN()
-	benchmarkReplayDepths()
-	benchmarkMaxReplay()
-	benchmarkReplaySweetSpot()
-	benchmarkReplayBeforeAfter()
+	experiment.Before(func(ctx *experiment.Context) error {
+		rand.Seed(ctx.Seeds.Seed(rng.Global))
+		return nil
+	})
+
+	experiment.Register("singleCompare", "baseline vs replay on the first hidden layer, one run each", step(singleCompare))
+	experiment.Register("benchmarkReplayVsBaseline", "baseline vs replay, 10 runs each", step(benchmarkReplayVsBaseline))
+	experiment.Register("benchmarkReplayVsBaselineN", "baseline vs replay, 100 runs each", step(benchmarkReplayVsBaselineN))
+	experiment.Register("benchmarkReplayDepths", "hidden layer count vs replay depth", step(benchmarkReplayDepths))
+	experiment.Register("benchmarkMaxReplay", "MaxReplay 0-3 on a 4-hidden-layer network", step(benchmarkMaxReplay))
+	experiment.Register("benchmarkReplaySweetSpot", "replay with a scaled learning rate, 1 and 2 hidden layers", step(benchmarkReplaySweetSpot))
+	experiment.Register("benchmarkReplayBeforeAfter", "replay phase before vs after", step(benchmarkReplayBeforeAfter))
+
+	experiment.Main("replay2")
+}
+
+// step adapts a benchmark to the registry and gives it its own seed
+// streams
+func step(fn func()) experiment.StepFunc {
+	return func(ctx *experiment.Context) error {
+		seeds = ctx.Seeds.Sub(ctx.Step)
+		fn()
+		return nil
+	}
 }
 
 func singleCompare() {
 	// 1) ── MNIST ──────────────────────────────────────────────────────────────
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.8)
 
 	// 2) ── common architecture  ──────────────────────────────────────────────
@@ -45,9 +67,9 @@ func singleCompare() {
 	fc := []bool{true, false, true}
 
 	// 3) ── build baseline & replay nets ──────────────────────────────────────
-	baseNet := paragon.NewNetwork[float32](layer, acts, fc)
+	baseNet := paragon.NewNetwork[float32](layer, acts, fc, seeds.Sub("baseline").Seed(rng.Init))
 
-	replayNet := paragon.NewNetwork[float32](layer, acts, fc)
+	replayNet := paragon.NewNetwork[float32](layer, acts, fc, seeds.Sub("replay").Seed(rng.Init))
 	replayNet.Layers[1].ReplayOffset = -1
 	replayNet.Layers[1].ReplayPhase = "after"
 	replayNet.Layers[1].MaxReplay = 1
@@ -116,11 +138,11 @@ func benchmarkReplayVsBaseline() {
 	//---------------------------------------------------------------------------
 	// 0) load data once
 	//---------------------------------------------------------------------------
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.8)
 
 	//---------------------------------------------------------------------------
@@ -141,14 +163,14 @@ func benchmarkReplayVsBaseline() {
 	run := func(idx int, kind string) {
 		defer wg.Done()
 
-		// unique seed so every model starts with different weights
-		rand.Seed(time.Now().UnixNano() + int64(idx)*31)
+		// unique seed per run, shared by its baseline and replay model
+		seed := seeds.Subf("run=%d", idx).Seed(rng.Init)
 
 		layer := []struct{ Width, Height int }{{28, 28}, {16, 16}, {10, 1}}
 		acts := []string{"leaky_relu", "leaky_relu", "softmax"}
 		fc := []bool{true, false, true}
 
-		net := paragon.NewNetwork[float32](layer, acts, fc)
+		net := paragon.NewNetwork[float32](layer, acts, fc, seed)
 		if kind == "replay" {
 			net.Layers[1].ReplayOffset = -1
 			net.Layers[1].ReplayPhase = "after"
@@ -242,11 +264,11 @@ func benchmarkReplayVsBaselineN() {
 	//----------------------------------------------------------------------
 	// 0) LOAD DATA ONCE
 	//----------------------------------------------------------------------
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.8)
 
 	//----------------------------------------------------------------------
@@ -270,13 +292,14 @@ func benchmarkReplayVsBaselineN() {
 		sem <- struct{}{}        // acquire slot
 		defer func() { <-sem }() // release
 
-		// deterministic but unique seed
-		rnd := rand.New(rand.NewSource(time.Now().UnixNano() + int64(idx)*113))
+		// deterministic but unique seeds, shared by the run's pair
+		run := seeds.Subf("run=%d", idx)
+		rnd := run.Rand(rng.Shuffle)
 
 		layer := []struct{ Width, Height int }{{28, 28}, {16, 16}, {10, 1}}
 		acts := []string{"leaky_relu", "leaky_relu", "softmax"}
 		fc := []bool{true, false, true}
-		net := paragon.NewNetwork[float32](layer, acts, fc)
+		net := paragon.NewNetwork[float32](layer, acts, fc, run.Seed(rng.Init))
 		if replay {
 			net.Layers[1].ReplayOffset = -1
 			net.Layers[1].ReplayPhase = "after"
@@ -392,11 +415,11 @@ func benchmarkReplayDepths() {
 	}
 
 	// ───────── load MNIST once ─────────────────────────────────────────────
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.8)
 
 	// ───────── concurrency guard (≈ 80 % CPU) ──────────────────────────────
@@ -420,7 +443,7 @@ func benchmarkReplayDepths() {
 		defer func() { <-sem }() // release slot
 
 		// unique RNG per run
-		seed := time.Now().UnixNano() + int64(hCnt)*1e6 + int64(rDepth)*1e4 + int64(runIdx)
+		seed := seeds.Subf("h=%d/depth=%d/run=%d", hCnt, rDepth, runIdx).Seed(rng.Shuffle)
 		rnd := rand.New(rand.NewSource(seed))
 
 		// ----- 1. construct layer sizes ------------------------------------
@@ -443,7 +466,7 @@ func benchmarkReplayDepths() {
 		fc[0], fc[len(fc)-1] = true, true // full connect input & output
 		// hidden layers use local connectivity (fc[i]=false)
 
-		net := paragon.NewNetwork[float32](layer, acts, fc)
+		net := paragon.NewNetwork[float32](layer, acts, fc, seeds.Subf("h=%d/run=%d", hCnt, runIdx).Seed(rng.Init))
 
 		// ----- 2. set replay on first rDepth hidden layers ------------------
 		for l := 1; l <= rDepth && l <= hCnt; l++ {
@@ -539,11 +562,11 @@ func benchmarkMaxReplay() {
 	)
 
 	// Load MNIST dataset
-	if err := ensureMNISTDownloads("mnist_data"); err != nil {
+	if err := mnist.Ensure("mnist_data"); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainX, trainY, _ := loadMNISTData("mnist_data", true)
-	testX, testY, _ := loadMNISTData("mnist_data", false)
+	trainX, trainY, _ := mnist.Load("mnist_data", mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load("mnist_data", mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.8)
 
 	// Set up concurrency using 80% of CPU cores
@@ -567,7 +590,7 @@ func benchmarkMaxReplay() {
 		defer func() { <-sem }() // Release slot
 
 		// Unique RNG seed for each run
-		seed := time.Now().UnixNano() + int64(maxReplay)*1e6 + int64(runIdx)
+		seed := seeds.Subf("max=%d/run=%d", maxReplay, runIdx).Seed(rng.Shuffle)
 		rnd := rand.New(rand.NewSource(seed))
 
 		// Define network architecture
@@ -589,7 +612,7 @@ func benchmarkMaxReplay() {
 		fc[0], fc[len(fc)-1] = true, true // Fully connected input/output
 
 		// Initialize network
-		net := paragon.NewNetwork[float32](layers, acts, fc)
+		net := paragon.NewNetwork[float32](layers, acts, fc, seeds.Subf("run=%d", runIdx).Seed(rng.Init))
 
 		// Configure replay for hidden layers
 		for l := 1; l <= hiddenLayers; l++ {
@@ -685,11 +708,11 @@ func benchmarkReplaySweetSpot() {
 	}
 
 	// ---------------------- dataset ---------------------------------------
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.8)
 
 	// ---------------------- concurrency -----------------------------------
@@ -714,7 +737,7 @@ func benchmarkReplaySweetSpot() {
 		sem <- struct{}{}
 		defer func() { <-sem }()
 
-		seed := time.Now().UnixNano() + int64(hCnt*1e5) + int64(runIdx*137)
+		seed := seeds.Subf("h=%d/run=%d", hCnt, runIdx).Seed(rng.Shuffle)
 		rnd := rand.New(rand.NewSource(seed))
 
 		// 1. construct layer sizes
@@ -734,7 +757,7 @@ func benchmarkReplaySweetSpot() {
 		fc := make([]bool, len(layers))
 		fc[0], fc[len(fc)-1] = true, true // full connect I/O
 
-		net := paragon.NewNetwork[float32](layers, acts, fc)
+		net := paragon.NewNetwork[float32](layers, acts, fc, seeds.Subf("h=%d/run=%d", hCnt, runIdx).Seed(rng.Init))
 
 		// 2. configure single‑layer replay (layer 1) if requested
 		if replay {
@@ -842,11 +865,11 @@ func benchmarkReplayBeforeAfter() {
 	)
 
 	// ---------- dataset ---------------------------------------------------
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.8)
 
 	fmt.Println(len(trainX))
@@ -877,9 +900,7 @@ func benchmarkReplayBeforeAfter() {
 		sem <- struct{}{}
 		defer func() { <-sem }()
 
-		seed := time.Now().UnixNano() +
-			int64(runIdx)*113 +
-			int64(len(kind))*1e6
+		seed := seeds.Subf("%s/run=%d", kind, runIdx).Seed(rng.Shuffle)
 		rnd := rand.New(rand.NewSource(seed))
 
 		// network shape: 28×28 → 16×16 → 10
@@ -891,7 +912,7 @@ func benchmarkReplayBeforeAfter() {
 		acts := []string{"leaky_relu", "leaky_relu", "softmax"}
 		fc := []bool{true, false, true}
 
-		net := paragon.NewNetwork[float32](layers, acts, fc)
+		net := paragon.NewNetwork[float32](layers, acts, fc, seeds.Subf("run=%d", runIdx).Seed(rng.Init))
 
 		// configure replay variant
 		if kind != baseline {
@@ -2,7 +2,10 @@ module main
 
 go 1.24.3
 
-require paragon v0.0.0
+require (
+	arena v0.0.0
+	paragon v0.0.0
+)
 
 require (
 	github.com/openfluke/pilot v0.0.2 // indirect
@@ -10,3 +13,5 @@ require (
 )
 
 replace paragon => ../../
+
+replace arena => ../arena
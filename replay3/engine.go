@@ -1,10 +1,16 @@
 package main
 
 import (
+	"arena/datasets/mnist"
+	"arena/experiment"
+	"arena/rng"
+	"arena/stats"
+	"arena/tasks"
 	"fmt"
 	"log"
 	"math"
 	"math/rand"
+	"os"
 	"paragon"
 	"runtime"
 	"sort"
@@ -13,32 +19,51 @@ import (
 
 // -------------------------------------------------- data helpers you already have
 const (
-	baseURL   = "https://storage.googleapis.com/cvdf-datasets/mnist/"
 	mnistDir  = "mnist_data"
 	modelDir  = "models"
 	modelFile = "mnist_model.json"
 )
 
 // -------------------------------------------------- main
+// seeds holds the current step's streams; steps that draw their data
+// from it can be rerun exactly with -seed
+var seeds *rng.Seeds
+
 func main() {
-	//testReplayVariantsParallel()
-	//testReplayVariantsWithLowerLR()
-	//multiTest()
-	//multiTestExtend()
-	//multiTestDeepReplaySweep()
-
-	//multiTestDeepReplaySweepLowerLR()
-	//multiTestDeepReplaySweepUltraLowLR()
-	multiTestHardReplaySweepLowerLR()
+	experiment.Before(func(ctx *experiment.Context) error {
+		rand.Seed(ctx.Seeds.Seed(rng.Global))
+		return nil
+	})
+
+	experiment.Register("multiTestHardReplaySweepLowerLR", "sparse XOR and temporal echo, deep replay at very low learning rates", step(multiTestHardReplaySweepLowerLR))
+
+	experiment.Optional("testReplayVariantsParallel", "MNIST replay phase and repeat variants in parallel", experiment.Func(testReplayVariantsParallel))
+	experiment.Optional("testReplayVariantsWithLowerLR", "MNIST replay variants with scaled learning rates", experiment.Func(testReplayVariantsWithLowerLR))
+	experiment.Optional("multiTest", "adversarial replay benchmark: fuzzy XOR, hotspot, center mass", experiment.Func(multiTest))
+	experiment.Optional("multiTestExtend", "sparse clusters, noisy rings and scattered bits", experiment.Func(multiTestExtend))
+	experiment.Optional("multiTestDeepReplaySweep", "replay depth sweep on noisy ring detection", experiment.Func(multiTestDeepReplaySweep))
+	experiment.Optional("multiTestDeepReplaySweepLowerLR", "deep replay sweep at lower learning rates", experiment.Func(multiTestDeepReplaySweepLowerLR))
+	experiment.Optional("multiTestDeepReplaySweepUltraLowLR", "deep replay sweep at ultra-low learning rates", experiment.Func(multiTestDeepReplaySweepUltraLowLR))
+
+	experiment.Main("replay3")
+}
+
+// step adapts a study to the registry and gives it its own seed streams
+func step(fn func()) experiment.StepFunc {
+	return func(ctx *experiment.Context) error {
+		seeds = ctx.Seeds.Sub(ctx.Step)
+		fn()
+		return nil
+	}
 }
 
 func testReplayVariantsParallel() {
 	// 1) Load MNIST once
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.8)
 
 	// 2) Shared architecture config
@@ -177,11 +202,11 @@ func testReplayVariantsParallel() {
 
 func testReplayVariantsWithLowerLR() {
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.8)
 
 	// 2) Shared config
@@ -440,10 +465,14 @@ func multiTest() {
 	type outcome struct {
 		task     string
 		replay   int
+		run      int
 		adhd     float64
 		accuracy float64
 	}
 
+	// Repeated runs per setting, so replay gains can be told from noise
+	const runs = 8
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var results []outcome
@@ -452,51 +481,53 @@ func multiTest() {
 
 	for _, task := range tasks {
 		for replay := 0; replay <= 3; replay++ {
-			wg.Add(1)
-			sem <- struct{}{}
-			go func(cfg taskConfig, replayCount int) {
-				defer wg.Done()
-				defer func() { <-sem }()
+			for run := 0; run < runs; run++ {
+				wg.Add(1)
+				sem <- struct{}{}
+				go func(cfg taskConfig, replayCount, run int) {
+					defer wg.Done()
+					defer func() { <-sem }()
 
-				X, Y := cfg.gen()
-				layer := []struct{ Width, Height int }{
-					{cfg.inputW, cfg.inputH},
-					{12, 12},
-					{cfg.outputC, 1},
-				}
-				acts := []string{"leaky_relu", "leaky_relu", "softmax"}
-				fc := []bool{true, false, true}
-				net := paragon.NewNetwork(layer, acts, fc)
+					X, Y := cfg.gen()
+					layer := []struct{ Width, Height int }{
+						{cfg.inputW, cfg.inputH},
+						{12, 12},
+						{cfg.outputC, 1},
+					}
+					acts := []string{"leaky_relu", "leaky_relu", "softmax"}
+					fc := []bool{true, false, true}
+					net := paragon.NewNetwork(layer, acts, fc)
 
-				if replayCount > 0 {
-					net.Layers[1].ReplayOffset = -1
-					net.Layers[1].ReplayPhase = "after"
-					net.Layers[1].MaxReplay = replayCount
-				}
+					if replayCount > 0 {
+						net.Layers[1].ReplayOffset = -1
+						net.Layers[1].ReplayPhase = "after"
+						net.Layers[1].MaxReplay = replayCount
+					}
 
-				net.Train(X, Y, 25, 0.001, true)
+					net.Train(X, Y, 25, 0.001, true)
 
-				exp, pred := make([]float64, len(X)), make([]float64, len(X))
-				correct := 0
-				for i := range X {
-					net.Forward(X[i])
-					p := float64(paragon.ArgMax(net.ExtractOutput()))
-					t := float64(paragon.ArgMax(Y[i][0]))
-					exp[i], pred[i] = t, p
-					if p == t {
-						correct++
+					exp, pred := make([]float64, len(X)), make([]float64, len(X))
+					correct := 0
+					for i := range X {
+						net.Forward(X[i])
+						p := float64(paragon.ArgMax(net.ExtractOutput()))
+						t := float64(paragon.ArgMax(Y[i][0]))
+						exp[i], pred[i] = t, p
+						if p == t {
+							correct++
+						}
 					}
-				}
-				net.EvaluateModel(exp, pred)
-				acc := float64(correct) / float64(len(X)) * 100.0
+					net.EvaluateModel(exp, pred)
+					acc := float64(correct) / float64(len(X)) * 100.0
 
-				mu.Lock()
-				results = append(results, outcome{
-					task: cfg.name, replay: replayCount,
-					adhd: net.Performance.Score, accuracy: acc,
-				})
-				mu.Unlock()
-			}(task, replay)
+					mu.Lock()
+					results = append(results, outcome{
+						task: cfg.name, replay: replayCount, run: run,
+						adhd: net.Performance.Score, accuracy: acc,
+					})
+					mu.Unlock()
+				}(task, replay, run)
+			}
 		}
 	}
 
@@ -506,17 +537,42 @@ func multiTest() {
 		if results[i].task != results[j].task {
 			return results[i].task < results[j].task
 		}
-		return results[i].replay < results[j].replay
+		if results[i].replay != results[j].replay {
+			return results[i].replay < results[j].replay
+		}
+		return results[i].run < results[j].run
 	})
 
 	fmt.Println("\n=============== ADVERSARIAL REPLAY BENCHMARK ===============")
-	fmt.Printf("%-22s | Replays | ADHD   | Acc%%\n", "Task")
+	fmt.Printf("%-22s | Replays | Run | ADHD   | Acc%%\n", "Task")
 	fmt.Println("------------------------------------------------------------")
 	for _, r := range results {
-		fmt.Printf("%-22s |    %d     | %6.2f | %5.2f\n",
-			r.task, r.replay, r.adhd, r.accuracy)
+		fmt.Printf("%-22s |    %d    | %3d | %6.2f | %5.2f\n",
+			r.task, r.replay, r.run, r.adhd, r.accuracy)
 	}
 	fmt.Println("============================================================")
+
+	// Replay counts 1-3 against no replay, per task
+	for _, task := range tasks {
+		acc := make([]stats.Sample, 4)
+		adhd := make([]stats.Sample, 4)
+		for rp := range acc {
+			acc[rp].Name = fmt.Sprintf("replay=%d", rp)
+			adhd[rp].Name = acc[rp].Name
+		}
+		for _, r := range results {
+			if r.task == task.name {
+				acc[r.replay].Values = append(acc[r.replay].Values, r.accuracy)
+				adhd[r.replay].Values = append(adhd[r.replay].Values, r.adhd)
+			}
+		}
+		if err := stats.WriteComparisons(os.Stdout, task.name, "accuracy", acc, 0.95); err != nil {
+			log.Printf("comparisons: %v", err)
+		}
+		if err := stats.WriteComparisons(os.Stdout, task.name, "ADHD score", adhd, 0.95); err != nil {
+			log.Printf("comparisons: %v", err)
+		}
+	}
 }
 
 func multiTestExtend() {
@@ -708,7 +764,6 @@ func multiTestExtend() {
 	fmt.Println("==========================================================")
 }
 
-// Place this inside your main package or call it from `main()` to execute.
 func multiTestDeepReplaySweep() {
 	type taskConfig struct {
 		name    string
@@ -1199,93 +1254,17 @@ func multiTestDeepReplaySweepUltraLowLR() {
 	fmt.Println("==============================================================")
 }
 
-func generateHardSparseXOR() ([][][]float64, [][][]float64) {
-	var X, Y [][][]float64
-	for i := 0; i < 1000; i++ {
-		in := make([][]float64, 12)
-		for y := range in {
-			in[y] = make([]float64, 12)
-		}
-
-		a := rand.Intn(2)
-		b := rand.Intn(2)
-		c := rand.Intn(2)
-		label := (a ^ b) ^ c
-
-		// Embed sparse features
-		if a == 1 {
-			in[2][3] = 1.0
-		}
-		if b == 1 {
-			in[5][6] = 1.0
-		}
-		if c == 1 {
-			in[8][9] = 1.0
-		}
-
-		out := [][]float64{{0.2, 0.2}}
-		out[0][label] = 0.8
-
-		X = append(X, in)
-		Y = append(Y, out)
-	}
-	return X, Y
-}
-
-func generateTemporalEcho() ([][][]float64, [][][]float64) {
-	var X, Y [][][]float64
-	for i := 0; i < 1000; i++ {
-		in := make([][]float64, 12)
-		for y := range in {
-			in[y] = make([]float64, 12)
-		}
-
-		// Simulate a signal + echo
-		signal := rand.Intn(2)
-		delay := rand.Intn(4) + 2
-
-		in[1][1] = float64(signal)
-		if 1+delay < 12 && 1+delay < 12 {
-			in[1+delay][1+delay] = float64(signal)
-		}
-
-		// Add noise
-		for j := 0; j < 10; j++ {
-			in[rand.Intn(12)][rand.Intn(12)] += rand.Float64() * 0.3
-		}
-
-		out := [][]float64{{0.2, 0.2}}
-		out[0][signal] = 0.8
-		X = append(X, in)
-		Y = append(Y, out)
-	}
-	return X, Y
-}
-
 func multiTestHardReplaySweepLowerLR() {
+	// The hard tasks are the arena battery's sparse parity and delayed
+	// echo; each sees one dataset, shared by every variant of the sweep.
 	type taskConfig struct {
-		name    string
-		inputW  int
-		inputH  int
-		outputC int
-		gen     func() ([][][]float64, [][][]float64)
+		name string
+		task tasks.Task
 	}
 
-	tasks := []taskConfig{
-		{
-			name:    "Sparse Concept XOR",
-			inputW:  12,
-			inputH:  12,
-			outputC: 2,
-			gen:     generateHardSparseXOR,
-		},
-		{
-			name:    "Temporal Echo Classification",
-			inputW:  12,
-			inputH:  12,
-			outputC: 2,
-			gen:     generateTemporalEcho,
-		},
+	configs := []taskConfig{
+		{name: "Sparse Concept XOR", task: tasks.SparseParity{Width: 12, Height: 12, Bits: 3}},
+		{name: "Temporal Echo Classification", task: tasks.DelayedEcho{Length: 16, Delay: 4, Noise: 0.3}},
 	}
 
 	type outcome struct {
@@ -1303,16 +1282,20 @@ func multiTestHardReplaySweepLowerLR() {
 	var results []outcome
 	sem := make(chan struct{}, int(0.8*float64(runtime.NumCPU())))
 
-	layerVariants := [][]struct{ Width, Height int }{
-		{{12, 12}, {32, 32}, {16, 16}, {8, 8}, {2, 1}},
+	hiddenVariants := [][]struct{ Width, Height int }{
+		{{32, 32}, {16, 16}, {8, 8}},
 	}
 
 	phases := []string{"before", "after"}
 	learningRates := []float64{0.000005, 0.00001, 0.00005, 0.0001}
 	maxReplay := 10
 
-	for _, task := range tasks {
-		for _, layers := range layerVariants {
+	for _, task := range configs {
+		w, h, classes := task.task.Shape()
+		X, Y := task.task.Generate(1000, seeds.Sub(task.task.Name()).Rand(rng.Noise))
+		for _, hidden := range hiddenVariants {
+			layers := append([]struct{ Width, Height int }{{w, h}}, hidden...)
+			layers = append(layers, struct{ Width, Height int }{classes, 1})
 			for _, phase := range phases {
 				for _, lr := range learningRates {
 					for repeats := 0; repeats <= maxReplay; repeats++ {
@@ -1322,7 +1305,6 @@ func multiTestHardReplaySweepLowerLR() {
 							defer wg.Done()
 							defer func() { <-sem }()
 
-							X, Y := cfg.gen()
 							acts := make([]string, len(layerSet))
 							fc := make([]bool, len(layerSet))
 							for i := range acts {
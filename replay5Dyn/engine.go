@@ -6,6 +6,9 @@ import (
 	"math/rand"
 	"os"
 
+	"arena/config"
+	"arena/eval"
+	"arena/stats"
 	"paragon"
 )
 
@@ -52,7 +55,7 @@ func main() {
 		net := Clone(base)
 		fmt.Printf("\n[Standard %d] Training...\n", i+1)
 		net.Train(ins, tgts, epochs, learnRate, false, clipUpper, clipLower)
-		score := evaluate(net, ins, tgts).score
+		score := eval.Evaluate(net, ins, tgts).Score()
 		fmt.Printf("→ ADHD Score: %.2f\n", score)
 		results["standard"] = append(results["standard"], score)
 	}
@@ -68,7 +71,7 @@ func main() {
 		layer.MaxReplay = 5
 
 		net.Train(ins, tgts, epochs, learnRate, false, clipUpper, clipLower)
-		score := evaluate(net, ins, tgts).score
+		score := eval.Evaluate(net, ins, tgts).Score()
 		fmt.Printf("→ ADHD Score: %.2f\n", score)
 		results["manual"] = append(results["manual"], score)
 	}
@@ -79,18 +82,16 @@ func main() {
 
 	if _, err := os.Stat(dynModelPath); os.IsNotExist(err) {
 		fmt.Println("📁 No saved dynamic model found. Training new one...")
+		replay := dynamicReplay(base.OutputLayer)
 		for i := 0; i < 10; i++ {
 			net := Clone(base)
 			fmt.Printf("\n[Dynamic %d] Training with Entropy-Gated Replay...\n", i+1)
-			for l := 1; l < net.OutputLayer; l++ {
-				layer := &net.Layers[l]
-				layer.ReplayEnabled = true
-				layer.ReplayBudget = 20
-				layer.ReplayGateFunc = EntropyGate[float32](layer)
-				layer.ReplayGateToReps = OLDEntropyToReplay
+			if err := config.Apply(net, replay); err != nil {
+				fmt.Println("❌ Failed to set up replay:", err)
+				return
 			}
 			net.Train(ins, tgts, epochs, learnRate, false, clipUpper, clipLower)
-			score := evaluate(net, ins, tgts).score
+			score := eval.Evaluate(net, ins, tgts).Score()
 			fmt.Printf("→ ADHD Score: %.2f\n", score)
 			results["dynamic"] = append(results["dynamic"], score)
 
@@ -102,32 +103,32 @@ func main() {
 
 		if bestNet != nil {
 			fmt.Printf("💾 Saving best dynamic model (Score: %.2f)...\n", bestScore)
-			if err := bestNet.SaveJSON(dynModelPath); err != nil {
+			if err := config.SaveModel(bestNet, dynModelPath, replay); err != nil {
 				fmt.Println("❌ Failed to save model:", err)
 			}
 		}
 	} else {
 		fmt.Println("📂 Loading existing best dynamic model...")
-		net := &paragon.Network[float32]{}
-		if err := net.LoadJSON(dynModelPath); err != nil {
+		// The gates and policies come back from the replay manifest; models
+		// saved before it get the replay they were trained with
+		net, m, err := config.LoadModel[float32](dynModelPath)
+		if err == nil && len(m.Layers) == 0 {
+			err = config.Apply(net, dynamicReplay(net.OutputLayer))
+		}
+		if err != nil {
 			fmt.Println("❌ Failed to load model:", err)
 		} else {
-			for l := 1; l < net.OutputLayer; l++ {
-				layer := &net.Layers[l]
-				if layer.ReplayEnabled {
-					layer.ReplayGateFunc = EntropyGate[float32](layer)
-					layer.ReplayGateToReps = OLDEntropyToReplay
-				}
-			}
-			score := evaluate(net, ins, tgts).score
+			score := eval.Evaluate(net, ins, tgts).Score()
 			fmt.Printf("→ ADHD Score from loaded model: %.2f\n", score)
 			results["dynamic"] = append(results["dynamic"], score)
 		}
 	}
 
 	// ─── SUMMARY ───
+	modes := []string{"standard", "manual", "dynamic"}
 	fmt.Println("\n📊 Summary:")
-	for mode, scores := range results {
+	for _, mode := range modes {
+		scores := results[mode]
 		fmt.Printf("%-10s → avg ADHD = %.2f | Scores: ", mode, avg(scores))
 		for i, s := range scores {
 			if i > 0 {
@@ -137,70 +138,34 @@ func main() {
 		}
 		fmt.Println()
 	}
-}
-
-// ────────── Helper Code ──────────
 
-type evalResult struct {
-	score   float64
-	exp     []float64
-	pred    []float64
-	buckets map[string]int
-}
-
-func evaluate(net *paragon.Network[float32], inputs, targets [][][]float64) evalResult {
-	exp, pred := []float64{}, []float64{}
-	for i, in := range inputs {
-		net.Forward(in)
-		net.ApplySoftmax()
-		out := net.ExtractOutput()
-		pred = append(pred, float64(paragon.ArgMax(out)))
-		exp = append(exp, float64(paragon.ArgMax(targets[i][0])))
+	// Every run starts from the same base model but draws its own batches, so
+	// the runs are compared as independent samples
+	samples := make([]stats.Sample, len(modes))
+	for i, mode := range modes {
+		samples[i] = stats.Sample{Name: mode, Values: results[mode]}
 	}
-	net.EvaluateModel(exp, pred)
-	b := map[string]int{}
-	for k, v := range net.Performance.Buckets {
-		b[k] = v.Count
+	if err := stats.WriteComparisons(os.Stdout, "REPLAY vs STANDARD", "ADHD score", samples, 0.95); err != nil {
+		fmt.Println("❌ Failed to write comparisons:", err)
 	}
-	return evalResult{net.Performance.Score, exp, pred, b}
 }
 
-func EntropyGate[T paragon.Numeric](layer *paragon.Grid[T]) func(input [][]T) float64 {
-	return func(input [][]T) float64 {
-		if len(input) == 0 || len(input[0]) == 0 {
-			return 0.0
-		}
-		var values []float64
-		for _, row := range input {
-			for _, v := range row {
-				values = append(values, float64(v))
-			}
-		}
-		if len(values) == 0 {
-			return 0.0 // <-- this guards the crash
-		}
-		out := paragon.Softmax(values)
-		sum := 0.0
-		for _, p := range out {
-			if p > 0 {
-				sum -= p * math.Log2(p)
-			}
-		}
-		return sum / math.Log2(float64(len(out)))
-	}
-}
+// ────────── Helper Code ──────────
 
-func OLDEntropyToReplay(score float64) int {
-	switch {
-	case score > 0.9:
-		return 10
-	case score > 0.7:
-		return 5
-	case score > 0.5:
-		return 2
-	default:
-		return 1
+// dynamicReplay gates every hidden layer below output with the entropy of
+// its input and replay5Dyn's entropy-to-replays table, up to 20 replays per
+// pass
+func dynamicReplay(output int) config.Manifest {
+	var m config.Manifest
+	for l := 1; l < output; l++ {
+		m.Layers = append(m.Layers, config.LayerReplay{Layer: l, Replay: config.Replay{
+			Mode:   config.ReplayDynamic,
+			Gate:   "input-entropy",
+			Policy: "entropy-steps",
+			Budget: 20,
+		}})
 	}
+	return m
 }
 
 func Clone[T paragon.Numeric](n *paragon.Network[T]) *paragon.Network[T] {
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"arena/compute"
+	"arena/datasets/mnist"
+	"arena/results"
+	"arena/rng"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"paragon"
+	"sort"
+	"sync"
+)
+
+// benchmarkEqualCompute asks whether replay beats the baseline or only
+// spends more compute: static and dynamic replay on one 3x3 hidden layer
+// against the plain network and against one whose hidden layer is widened
+// until a forward pass costs as much as MaxReplay=3.
+func benchmarkEqualCompute() {
+	const (
+		nRuns     = 5
+		epochs    = 3
+		lr        = 0.001
+		maxReplay = 3
+	)
+
+	if err := mnist.Ensure(mnistDir); err != nil {
+		log.Fatal(err)
+	}
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
+	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.3)
+
+	// The static network's cost fixes the width of the matched baseline.
+	layers := []struct{ Width, Height int }{{28, 28}, {3, 3}, {10, 1}}
+	static := createNetwork("static", 0, 1)
+	static.Layers[1].MaxReplay = maxReplay
+	wide := compute.Widen(layers, 1, compute.Forward(static).MACs)
+	fmt.Printf("📐 MaxReplay=%d costs %s MACs per pass; matched baseline is %dx%d\n",
+		maxReplay, compute.Format(compute.Forward(static).MACs), wide[1].Width, wide[1].Height)
+
+	models := []struct {
+		name  string
+		build func(seed int64) *paragon.Network[float32]
+	}{
+		{"Baseline", func(seed int64) *paragon.Network[float32] { return createNetwork("baseline", seed, 1) }},
+		{"Static MaxReplay=1", func(seed int64) *paragon.Network[float32] {
+			net := createNetwork("static", seed, 1)
+			net.Layers[1].MaxReplay = 1
+			return net
+		}},
+		{fmt.Sprintf("Static MaxReplay=%d", maxReplay), func(seed int64) *paragon.Network[float32] {
+			net := createNetwork("static", seed, 1)
+			net.Layers[1].MaxReplay = maxReplay
+			return net
+		}},
+		{"Dynamic", func(seed int64) *paragon.Network[float32] { return createNetwork("dynamic", seed, 1) }},
+		{fmt.Sprintf("Wide %dx%d", wide[1].Width, wide[1].Height), func(seed int64) *paragon.Network[float32] {
+			return paragon.NewNetwork[float32](wide, []string{"leaky_relu", "leaky_relu", "softmax"}, []bool{true, true, true}, seed)
+		}},
+	}
+
+	var wg sync.WaitGroup
+	for _, m := range models {
+		for run := 0; run < nRuns; run++ {
+			wg.Add(1)
+			go func(name string, build func(int64) *paragon.Network[float32], run int) {
+				defer wg.Done()
+				streams := seeds.Subf("run=%d", run) // shared so runs pair across models
+				seed := streams.Seed(rng.Init)
+				rnd := streams.Rand(rng.Shuffle)
+				net := build(seed)
+				fmt.Printf("🧠 Training %s for run %d …\n", name, run)
+				shX := make([][][]float64, len(trainX))
+				shY := make([][][]float64, len(trainY))
+				for i, p := range rnd.Perm(len(trainX)) {
+					shX[i], shY[i] = trainX[p], trainY[p]
+				}
+				counter := compute.Attach(net)
+				counter.Train(len(shX), epochs)
+				net.Train(shX, shY, epochs, lr, true, 5, -5)
+				counter.Infer(len(testX))
+				res := evaluateNet(net, testX, testY)
+				params := map[string]any{"hidden_width": net.Layers[1].Width, "hidden_height": net.Layers[1].Height,
+					"max_replay": net.Layers[1].MaxReplay}
+				recordRun(name, params, seed, res, counter.Metrics(res.Accuracy))
+			}(m.name, m.build, run)
+		}
+	}
+	wg.Wait()
+
+	title := fmt.Sprintf("EQUAL COMPUTE BENCHMARK (%d runs each)", nRuns)
+	writeSummary(title)
+	writeSignificance("Baseline")
+	writeCompute(title)
+}
+
+// writeCompute renders accuracy against compute for the current step's
+// models, cheapest first
+func writeCompute(title string) {
+	sums := results.Summarize(rec.StepRecords())
+	sort.SliceStable(sums, func(i, j int) bool {
+		return sums[i].Metrics["train_macs"].Mean < sums[j].Metrics["train_macs"].Mean
+	})
+	if err := compute.WriteTable(io.MultiWriter(os.Stdout, resultsFile), title+": COMPUTE", "accuracy", sums); err != nil {
+		log.Printf("Failed to write results.txt: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"arena/config"
+	"arena/datasets/mnist"
+	"arena/eval"
+	"arena/experiment"
+	"arena/results"
+	"arena/rng"
+	"fmt"
+	"paragon"
+)
+
+// configSweep trains every variant of a config file (see configs/) on 30%
+// of MNIST from shared initial weights and records one result per variant
+func configSweep(ctx *experiment.Context) error {
+	if len(ctx.Args) != 1 {
+		return fmt.Errorf("usage: run configSweep [-seed N] <config.yaml>")
+	}
+	cfg, err := config.Load(ctx.Args[0])
+	if err != nil {
+		return err
+	}
+	variants, err := cfg.Expand()
+	if err != nil {
+		return err
+	}
+
+	if err := mnist.Ensure(mnistDir); err != nil {
+		return err
+	}
+	trainX, trainY, err := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	if err != nil {
+		return err
+	}
+	testX, testY, err := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
+	if err != nil {
+		return err
+	}
+	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.3)
+
+	// every variant starts from the same weights unless it sets its own
+	// seed, so the swept values are compared on equal terms
+	initSeed := seeds.Seed(rng.Init)
+	for _, v := range variants {
+		if v.Config.Seed == 0 {
+			v.Config.Seed = initSeed
+		}
+		net, err := config.Build[float32](v.Config)
+		if err != nil {
+			return fmt.Errorf("%s: %w", v.Label(), err)
+		}
+		fmt.Printf("🧠 Training %s …\n", v.Label())
+		config.Train(net, v.Config.Training, trainX, trainY)
+		res := evaluateNet(net, testX, testY)
+		err = rec.Write(results.Record{
+			Kind:        results.KindFinal,
+			Model:       v.Label(),
+			Params:      v.Params,
+			Seed:        v.Config.Seed,
+			ConfigHash:  results.Hash(v.Config),
+			ADHD:        res.ADHD,
+			Metrics:     res.Metrics(),
+			Diagnostics: res.Diagnostics(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writeSummary(fmt.Sprintf("CONFIG SWEEP: %s (%d variants)", cfg.Name, len(variants)))
+	return nil
+}
+
+// evaluateNet scores a trained network on a one-hot test set, with top-3
+// accuracy alongside the defaults
+func evaluateNet(net *paragon.Network[float32], testX, testY [][][]float64) eval.Result {
+	return eval.Evaluate(net, testX, testY, 3)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"arena/datasets/mnist"
+	"arena/plot"
+	"arena/results"
+	"arena/rng"
+	"arena/train"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"paragon"
+	"sync"
+)
+
+// benchmarkLearningCurves trains baseline, static and dynamic replay epoch by
+// epoch against a validation split, to show whether replay converges faster
+// or only ends up elsewhere. Each model stops after 3 epochs without a lower
+// validation loss and is rolled back to its best epoch before testing.
+func benchmarkLearningCurves() {
+	const (
+		nRuns     = 3
+		maxEpochs = 15
+		patience  = 3
+		lr        = 0.001
+	)
+
+	if err := mnist.Ensure(mnistDir); err != nil {
+		log.Fatal(err)
+	}
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
+	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.3)        // 30% training data
+	trainX, trainY, valX, valY := paragon.SplitDataset(trainX, trainY, 0.9) // 10% of it held out
+
+	var wg sync.WaitGroup
+	for run := 0; run < nRuns; run++ {
+		for _, kind := range []string{"baseline", "static", "dynamic"} {
+			wg.Add(1)
+			go func(kind string, run int) {
+				defer wg.Done()
+				streams := seeds.Subf("run=%d", run) // shared so runs pair across kinds
+				seed := streams.Seed(rng.Init)
+				net := createNetwork(kind, seed, 1)
+				model := fmt.Sprintf("%s run=%d", kind, run)
+				fmt.Printf("🧠 Training %s …\n", model)
+				hist, err := train.Fit(net, trainX, trainY, train.Options[float32]{
+					Epochs:                  maxEpochs,
+					LearningRate:            lr,
+					EarlyStopOnNegativeLoss: true,
+					ClipUpper:               5,
+					ClipLower:               -5,
+					Shuffle:                 streams.Rand(rng.Shuffle),
+					ValX:                    valX,
+					ValY:                    valY,
+					Patience:                patience,
+					RestoreBest:             true,
+					Recorder:                rec,
+					Model:                   model,
+					Params:                  map[string]any{"replay": kind},
+					Seed:                    seed,
+				})
+				if err != nil {
+					log.Printf("%s: %v", model, err)
+				}
+				res := evaluateNet(net, testX, testY)
+				recordRun(kind, map[string]any{"replay": kind, "best_epoch": hist.Best, "epochs": len(hist.Epochs)}, seed, res)
+				fmt.Printf("✅ %s: best epoch %d of %d, test accuracy %.2f%%\n", model, hist.Best, len(hist.Epochs), res.Accuracy)
+			}(kind, run)
+		}
+	}
+	wg.Wait()
+
+	out := io.MultiWriter(os.Stdout, resultsFile)
+	if err := results.WriteEpochs(out, "LEARNING CURVE", rec.StepRecords()); err != nil {
+		log.Printf("Failed to write results.txt: %v", err)
+	}
+	writeSummary(fmt.Sprintf("LEARNING CURVES: BEST-EPOCH TEST RESULTS (%d runs)", nRuns))
+	writeSignificance("baseline")
+	for _, metric := range []string{"val_accuracy", "val_loss"} {
+		writeFigure(metric, &plot.LineChart{
+			Title:  fmt.Sprintf("LEARNING CURVES: %s (mean of %d runs)", metric, nRuns),
+			XLabel: "epoch",
+			YLabel: metric,
+			Series: plot.Epochs(rec.StepRecords(), metric, plot.Param("replay")),
+		})
+	}
+}
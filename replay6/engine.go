@@ -1,68 +1,174 @@
 package main
 
 import (
+	"arena/compute"
+	"arena/datasets/mnist"
+	"arena/eval"
+	"arena/experiment"
+	"arena/gates"
+	"arena/plot"
+	"arena/results"
+	"arena/rng"
+	"arena/stats"
+	"arena/trace"
+	"arena/train"
 	"fmt"
+	"io"
 	"log"
-	"math"
 	"math/rand"
 	"os"
 	"paragon"
 	"runtime"
+	"sort"
 	"sync"
-	"time"
 )
 
 // -------------------------------------------------- data helpers (unchanged)
 const (
-	baseURL   = "https://storage.googleapis.com/cvdf-datasets/mnist/"
 	mnistDir  = "mnist_data"
 	modelDir  = "models"
 	modelFile = "mnist_model.json"
 )
 
-// Global file and mutex for thread-safe writing to results.txt
+// Structured results go to resultsData (use a .csv name for the long CSV
+// layout); results.txt gets the human tables rendered from them.
+const resultsData = "results.jsonl"
+
 var (
+	rec         *results.Recorder
 	resultsFile *os.File
-	fileMu      sync.Mutex
 )
 
+// seeds holds the current step's streams; every network and shuffle draws
+// from it so a step can be rerun exactly with -seed
+var seeds *rng.Seeds
+
 // -------------------------------------------------- main
 func main() {
-	// Initialize results file
+	experiment.Before(openResults)
+
+	experiment.Register("singleCompare", "baseline, static and dynamic replay, one run each", step(singleCompare))
+	experiment.Register("benchmarkReplayVsBaseline", "baseline vs static vs dynamic replay, repeated runs", step(benchmarkReplayVsBaseline))
+	experiment.Register("benchmarkReplayVsBaselineN", "20 models per replay mode", step(benchmarkReplayVsBaselineN))
+	experiment.Register("benchmarkReplayDepths", "hidden layer count vs replay depth", step(benchmarkReplayDepths))
+	experiment.Register("benchmarkMaxReplay", "MaxReplay 0-3 on one hidden layer", step(benchmarkMaxReplay))
+	experiment.Register("benchmarkReplaySweetSpot", "replay with a scaled learning rate across depths", step(benchmarkReplaySweetSpot))
+	experiment.Register("benchmarkReplayBeforeAfter", "replay phase before vs after", step(benchmarkReplayBeforeAfter))
+	experiment.Register("benchmarkReplaySettingsMassive", "grid over replay settings", step(benchmarkReplaySettingsMassive))
+	experiment.Register("benchmarkDeepReplaySettings", "replay settings on deeper networks", step(benchmarkDeepReplaySettings))
+	experiment.Register("benchmarkDynamicReplayOptimizer", "gate types and thresholds for dynamic replay", step(benchmarkDynamicReplayOptimizer))
+	experiment.Register("benchmarkAdaptiveTemporalReplay", "temporal gate on deep networks", step(benchmarkAdaptiveTemporalReplay))
+	experiment.Register("benchmarkEnhancedTemporalReplay", "temporal gate threshold sweep", step(benchmarkEnhancedTemporalReplay))
+
+	experiment.Optional("benchmarkEqualCompute", "replay vs a baseline widened to the same compute, with MACs per model", step(benchmarkEqualCompute))
+	experiment.Optional("benchmarkLearningCurves", "per-epoch validation curves with early stopping, baseline vs static vs dynamic", step(benchmarkLearningCurves))
+
+	experiment.Optional("configSweep", "train every variant of a config file: run configSweep configs/maxreplay.yaml",
+		func(ctx *experiment.Context) error {
+			rec.SetStep(ctx.Step)
+			seeds = ctx.Seeds.Sub(ctx.Step)
+			return configSweep(ctx)
+		})
+
+	experiment.Optional("searchReplaySettings", "tune replay settings: run searchReplaySettings [hyperband|random|grid]",
+		func(ctx *experiment.Context) error {
+			rec.SetStep(ctx.Step)
+			seeds = ctx.Seeds.Sub(ctx.Step)
+			return searchReplaySettings(ctx)
+		})
+
+	experiment.Main("replay6")
+}
+
+// openResults seeds the run and opens the results sink and results.txt
+func openResults(ctx *experiment.Context) error {
+	rand.Seed(ctx.Seeds.Seed(rng.Global))
+
 	var err error
-	resultsFile, err = os.OpenFile("results.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		log.Fatalf("Failed to open results.txt: %v", err)
-	}
-	defer resultsFile.Close()
-
-	// Write timestamp header
-	writeResult(fmt.Sprintf("\n=== Test Run: %s ===\n", time.Now().Format("2006-01-02 15:04:05")))
-
-	singleCompare()
-	benchmarkReplayVsBaseline()
-	benchmarkReplayVsBaselineN()
-	benchmarkReplayDepths()
-	benchmarkMaxReplay()
-	benchmarkReplaySweetSpot()
-	benchmarkReplayBeforeAfter()
-	benchmarkReplaySettingsMassive()
-	benchmarkDeepReplaySettings()
-	benchmarkDynamicReplayOptimizer()
-	benchmarkAdaptiveTemporalReplay()
-	benchmarkEnhancedTemporalReplay()
+	rec, resultsFile, err = ctx.OpenResults(resultsData, "results.txt")
+	return err
 }
 
-// writeResult writes formatted output to results.txt with thread safety
-func writeResult(format string, args ...interface{}) {
-	fileMu.Lock()
-	defer fileMu.Unlock()
-	_, err := fmt.Fprintf(resultsFile, format, args...)
+// step adapts a benchmark to the registry, tags its records with the step
+// name and gives it its own seed streams
+func step(fn func()) experiment.StepFunc {
+	return func(ctx *experiment.Context) error {
+		rec.SetStep(ctx.Step)
+		seeds = ctx.Seeds.Sub(ctx.Step)
+		fn()
+		return nil
+	}
+}
+
+// recordRun stores one evaluated model: ADHD score, buckets, accuracy, the
+// other eval metrics and the diagnostics arena report shows
+func recordRun(model string, params map[string]any, seed int64, res eval.Result, extra ...map[string]float64) {
+	metrics := res.Metrics()
+	for _, m := range extra {
+		for k, v := range m {
+			metrics[k] = v
+		}
+	}
+	err := rec.Write(results.Record{
+		Kind:        results.KindFinal,
+		Model:       model,
+		Params:      params,
+		Seed:        seed,
+		ADHD:        res.ADHD,
+		Metrics:     metrics,
+		Diagnostics: res.Diagnostics(),
+	})
 	if err != nil {
-		log.Printf("Failed to write to results.txt: %v", err)
+		log.Printf("Failed to record result: %v", err)
+	}
+}
+
+// writeSummary renders the current step's records to stdout and results.txt
+func writeSummary(title string) {
+	sums := results.Summarize(rec.StepRecords())
+	sort.SliceStable(sums, func(i, j int) bool { return sums[i].Model < sums[j].Model })
+	if err := results.WriteSummary(io.MultiWriter(os.Stdout, resultsFile), title, sums); err != nil {
+		log.Printf("Failed to write results.txt: %v", err)
+	}
+	writeSummaryBars(title)
+}
+
+// writeSignificance compares every model of the current step with base on
+// accuracy and ADHD score; runs that share a seed are also compared paired
+func writeSignificance(base string) {
+	out := io.MultiWriter(os.Stdout, resultsFile)
+	for _, metric := range []string{"accuracy", "adhd"} {
+		samples := results.Samples(rec.StepRecords(), metric)
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].Name == base && samples[j].Name != base })
+		if len(samples) == 0 || samples[0].Name != base {
+			log.Printf("No %q runs to compare against", base)
+			return
+		}
+		if err := stats.WriteComparisons(out, "SIGNIFICANCE vs "+base, metric, samples, 0.95); err != nil {
+			log.Printf("Failed to write results.txt: %v", err)
+		}
+	}
+}
+
+// writeCalibration fits a softmax temperature on a validation split held
+// out of the training data and writes net's reliability on the test set
+// before and after scaling, to show whether replay changes how far its
+// probabilities can be trusted
+func writeCalibration(name string, net *paragon.Network[float32], valX, valY, testX, testY [][][]float64) {
+	t := eval.FitTemperature(eval.Outputs(net, valX), eval.Labels(valY))
+	out, labels := eval.Outputs(net, testX), eval.Labels(testY)
+	w := io.MultiWriter(os.Stdout, resultsFile)
+	for _, c := range []struct {
+		title string
+		cal   eval.Calibration
+	}{
+		{name + " CALIBRATION", eval.Calibrate(out, labels, 10)},
+		{fmt.Sprintf("%s CALIBRATION (temperature %.2f)", name, t), eval.Calibrate(eval.Temperature(out, t), labels, 10)},
+	} {
+		if err := eval.WriteReliability(w, c.title, c.cal); err != nil {
+			log.Printf("Failed to write results.txt: %v", err)
+		}
 	}
-	// Ensure immediate write
-	resultsFile.Sync()
 }
 
 // Helper to create a network with consistent architecture and replay settings
@@ -91,33 +197,9 @@ func createNetwork(replayType string, seed int64, hCnt int) *paragon.Network[flo
 		net.Layers[1].ReplayPhase = "after"
 		net.Layers[1].MaxReplay = 3
 	} else if replayType == "dynamic" {
-		// Dynamic replay: entropy-based gating
-		net.Layers[1].ReplayEnabled = true
-		net.Layers[1].ReplayBudget = 3
-		net.Layers[1].ReplayGateFunc = func(input [][]float32) float64 {
-			outputs := net.Layers[1].CachedOutputs
-			if len(outputs) == 0 {
-				return 0.5
-			}
-			var sum float64
-			for _, v := range outputs {
-				v64 := float64(v)
-				if v64 > 1e-10 { // Avoid log(0)
-					sum += v64 * math.Log(v64)
-				}
-			}
-			entropy := -sum / float64(len(outputs))
-			maxEntropy := math.Log(float64(len(outputs)))
-			if maxEntropy == 0 {
-				return 0.5
-			}
-			return math.Min(1.0, math.Max(0.0, entropy/maxEntropy))
-		}
-		net.Layers[1].ReplayGateToReps = func(score float64) int {
-			if score > 0.4 { // Trigger replay for moderate entropy
-				return 3
-			}
-			return 0
+		// Dynamic replay: entropy-based gating, 3 replays above 0.4
+		if err := gates.Apply(&net.Layers[1], "raw-entropy", gates.Threshold(0.4, 3), 3); err != nil {
+			log.Fatal(err)
 		}
 	}
 	return net
@@ -125,17 +207,22 @@ func createNetwork(replayType string, seed int64, hCnt int) *paragon.Network[flo
 
 func singleCompare() {
 	// 1) Load MNIST dataset
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
-	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.3) // 30% training data
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
+	// 30% training data; the rest of the training set is the validation
+	// split calibration fits its temperature on
+	trainX, trainY, valX, valY := paragon.SplitDataset(trainX, trainY, 0.3)
 
 	// 2) Create three networks (1 hidden layer)
-	baseNet := createNetwork("baseline", time.Now().UnixNano(), 1)
-	staticReplayNet := createNetwork("static", time.Now().UnixNano()+1, 1)
-	dynamicReplayNet := createNetwork("dynamic", time.Now().UnixNano()+2, 1)
+	baselineSeed := seeds.Sub("baseline").Seed(rng.Init)
+	staticSeed := seeds.Sub("static").Seed(rng.Init)
+	dynamicSeed := seeds.Sub("dynamic").Seed(rng.Init)
+	baseNet := createNetwork("baseline", baselineSeed, 1)
+	staticReplayNet := createNetwork("static", staticSeed, 1)
+	dynamicReplayNet := createNetwork("dynamic", dynamicSeed, 1)
 
 	// 3) Train all three
 	fmt.Println("🧠 Training baseline …")
@@ -147,73 +234,33 @@ func singleCompare() {
 	fmt.Println("🧠 Training dynamic replay …")
 	dynamicReplayNet.Train(trainX, trainY, 3, 0.001, true, 5, -5)
 
-	// 4) Evaluation helper
-	type results struct {
-		name    string
-		score   float64
-		acc     float64
-		buckets map[string]int
-	}
-	evaluate := func(net *paragon.Network[float32], name string) results {
-		exp, pred := []float64{}, []float64{}
-		correct := 0
-		for i, in := range testX {
-			net.Forward(in)
-			predVal := float64(paragon.ArgMax(net.ExtractOutput()))
-			expVal := float64(paragon.ArgMax(testY[i][0]))
-			pred = append(pred, predVal)
-			exp = append(exp, expVal)
-			if predVal == expVal {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		b := map[string]int{}
-		for k, v := range net.Performance.Buckets {
-			b[k] = v.Count
-		}
-		acc := float64(correct) / float64(len(testX)) * 100.0
-		return results{name, net.Performance.Score, acc, b}
-	}
-
-	baseRes := evaluate(baseNet, "Baseline")
-	staticRes := evaluate(staticReplayNet, "Static Replay")
-	dynamicRes := evaluate(dynamicReplayNet, "Dynamic Replay")
-
-	// 5) Write results to file
-	writeResult("\n============== PERFORMANCE COMPARISON ==============\n")
-	writeResult("Metric                     | %-12s | %-12s | %-12s\n",
-		"Baseline", "Static Replay", "Dynamic Replay")
-	writeResult("---------------------------+--------------+--------------+--------------\n")
-	writeResult("ADHD Score                 | %12.2f | %12.2f | %12.2f\n",
-		baseRes.score, staticRes.score, dynamicRes.score)
-	writeResult("Accuracy %%                 | %12.2f | %12.2f | %12.2f\n",
-		baseRes.acc, staticRes.acc, dynamicRes.acc)
-	writeResult("\nDeviation buckets (# samples):\n")
-	keys := []string{"0-10%", "10-20%", "20-30%", "30-40%", "40-50%", "50-100%", "100%+"}
-	for _, k := range keys {
-		writeResult(" %-7s | %12d | %12d | %12d\n",
-			k, baseRes.buckets[k], staticRes.buckets[k], dynamicRes.buckets[k])
-	}
+	// 4) Evaluate and record
+	for _, m := range []struct {
+		name string
+		seed int64
+		net  *paragon.Network[float32]
+	}{
+		{"baseline", baselineSeed, baseNet},
+		{"static", staticSeed, staticReplayNet},
+		{"dynamic", dynamicSeed, dynamicReplayNet},
+	} {
+		res := evaluateNet(m.net, testX, testY)
+		recordRun(m.name, map[string]any{"replay": m.name}, m.seed, res)
+		writeCalibration(m.name, m.net, valX, valY, testX, testY)
+	}
+	writeSummary("PERFORMANCE COMPARISON")
 }
 
 func benchmarkReplayVsBaseline() {
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.3)
 
 	// 2) Setup concurrency
-	type outcome struct {
-		Idx  int
-		Kind string
-		ADHD float64
-		Acc  float64
-	}
-	outCh := make(chan outcome, 30)
 	var wg sync.WaitGroup
 
 	// 3) Helper: build -> train -> evaluate
@@ -222,27 +269,14 @@ func benchmarkReplayVsBaseline() {
 		sem := make(chan struct{}, 1)
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		rnd := rand.New(rand.NewSource(time.Now().UnixNano() + int64(idx)*31))
-		net := createNetwork(kind, rnd.Int63(), 1)
+		seed := seeds.Subf("run=%d", idx).Seed(rng.Init)
+		net := createNetwork(kind, seed, 1)
 		fmt.Printf("🧠 Training %s for run %d …\n", kind, idx)
 		net.Train(trainX, trainY, 3, 0.001, true, 5, -5)
 
 		// Evaluate
-		exp, pred := make([]float64, len(testX)), make([]float64, len(testX))
-		correct := 0
-		for i, in := range testX {
-			net.Forward(in)
-			predVal := float64(paragon.ArgMax(net.ExtractOutput()))
-			expVal := float64(paragon.ArgMax(testY[i][0]))
-			pred[i] = predVal
-			exp[i] = expVal
-			if predVal == expVal {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		acc := float64(correct) / float64(len(testX)) * 100.0
-		outCh <- outcome{idx, kind, net.Performance.Score, acc}
+		res := evaluateNet(net, testX, testY)
+		recordRun(kind, map[string]any{"replay": kind}, seed, res)
 	}
 
 	// 4) Launch 30 goroutines (10 per variant)
@@ -253,47 +287,11 @@ func benchmarkReplayVsBaseline() {
 		go run(i, "dynamic")
 	}
 
-	go func() {
-		wg.Wait()
-		close(outCh)
-	}()
-
-	// 5) Collect and write results
-	bScores, sScores, dScores := []outcome{}, []outcome{}, []outcome{}
-	for res := range outCh {
-		switch res.Kind {
-		case "baseline":
-			bScores = append(bScores, res)
-		case "static":
-			sScores = append(sScores, res)
-		case "dynamic":
-			dScores = append(dScores, res)
-		}
-	}
+	wg.Wait()
 
-	writeResult("\n================ 10× BENCHMARK ================\n")
-	writeResult("%-4s | %-12s | %-8s | %-8s\n", "Run", "Kind", "ADHD", "Acc%")
-	writeResult("----------------------------------------------\n")
-	avgBadhd, avgSadhd, avgDadhd := 0.0, 0.0, 0.0
-	avgBacc, avgSacc, avgDacc := 0.0, 0.0, 0.0
-	for i := 0; i < 10; i++ {
-		b, s, d := bScores[i], sScores[i], dScores[i]
-		writeResult("%-4d | %-12s | %8.2f | %7.2f\n", b.Idx, "baseline", b.ADHD, b.Acc)
-		writeResult("%-4d | %-12s | %8.2f | %7.2f\n", s.Idx, "static", s.ADHD, s.Acc)
-		writeResult("%-4d | %-12s | %8.2f | %7.2f\n", d.Idx, "dynamic", d.ADHD, d.Acc)
-		writeResult("----------------------------------------------\n")
-		avgBadhd += b.ADHD
-		avgSadhd += s.ADHD
-		avgDadhd += d.ADHD
-		avgBacc += b.Acc
-		avgSacc += s.Acc
-		avgDacc += d.Acc
-	}
-	n := float64(10)
-	writeResult("AVERAGE %-12s | %8.2f | %7.2f\n", "baseline", avgBadhd/n, avgBacc/n)
-	writeResult("AVERAGE %-12s | %8.2f | %7.2f\n", "static", avgSadhd/n, avgSacc/n)
-	writeResult("AVERAGE %-12s | %8.2f | %7.2f\n", "dynamic", avgDadhd/n, avgDacc/n)
-	writeResult("==============================================\n")
+	// 5) Write results
+	writeSummary("10× BENCHMARK")
+	writeSignificance("baseline")
 }
 
 func benchmarkReplayVsBaselineN() {
@@ -304,11 +302,11 @@ func benchmarkReplayVsBaselineN() {
 	)
 
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.3)
 
 	// 2) Concurrency governor
@@ -317,20 +315,17 @@ func benchmarkReplayVsBaselineN() {
 		maxThreads = 1
 	}
 	sem := make(chan struct{}, maxThreads)
-	type score struct{ ADHD, Acc float64 }
-	bScores := make([]score, NModels)
-	sScores := make([]score, NModels)
-	dScores := make([]score, NModels)
 	var wg sync.WaitGroup
-	var mu sync.Mutex
 
 	// 3) Worker
 	buildAndRun := func(idx int, kind string) {
 		defer wg.Done()
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		rnd := rand.New(rand.NewSource(time.Now().UnixNano() + int64(idx)*113))
-		net := createNetwork(kind, rnd.Int63(), 1)
+		streams := seeds.Subf("run=%d", idx)
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
+		net := createNetwork(kind, seed, 1)
 		fmt.Printf("🧠 Training %s for run %d …\n", kind, idx)
 		shuffledX := make([][][]float64, len(trainX))
 		shuffledY := make([][][]float64, len(trainY))
@@ -340,29 +335,8 @@ func benchmarkReplayVsBaselineN() {
 			shuffledY[i] = trainY[p]
 		}
 		net.Train(shuffledX, shuffledY, Epochs, LearningRate, true, 5, -5)
-		correct := 0
-		exp, pred := make([]float64, len(testX)), make([]float64, len(testX))
-		for i, in := range testX {
-			net.Forward(in)
-			p := float64(paragon.ArgMax(net.ExtractOutput()))
-			t := float64(paragon.ArgMax(testY[i][0]))
-			pred[i], exp[i] = p, t
-			if p == t {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		accPc := float64(correct) / float64(len(testX)) * 100.0
-		mu.Lock()
-		switch kind {
-		case "baseline":
-			bScores[idx] = score{net.Performance.Score, accPc}
-		case "static":
-			sScores[idx] = score{net.Performance.Score, accPc}
-		case "dynamic":
-			dScores[idx] = score{net.Performance.Score, accPc}
-		}
-		mu.Unlock()
+		res := evaluateNet(net, testX, testY)
+		recordRun(kind, map[string]any{"replay": kind}, seed, res)
 	}
 
 	// 4) Launch goroutines
@@ -375,27 +349,8 @@ func benchmarkReplayVsBaselineN() {
 	wg.Wait()
 
 	// 5) Write results
-	writeResult("\n================ %d× BENCHMARK (%.0f%% CPUs) ================\n",
-		NModels, 100*0.8)
-	writeResult("Run | ADHD_bas | ADHD_sta | ADHD_dyn | Acc%%_bas | Acc%%_sta | Acc%%_dyn\n")
-	writeResult("------------------------------------------------------------------\n")
-	var sumBad, sumSad, sumDad, sumBac, sumSac, sumDac float64
-	for i := 0; i < NModels; i++ {
-		b, s, d := bScores[i], sScores[i], dScores[i]
-		writeResult("%3d | %8.2f | %8.2f | %8.2f | %8.2f | %8.2f | %8.2f\n",
-			i, b.ADHD, s.ADHD, d.ADHD, b.Acc, s.Acc, d.Acc)
-		sumBad += b.ADHD
-		sumSad += s.ADHD
-		sumDad += d.ADHD
-		sumBac += b.Acc
-		sumSac += s.Acc
-		sumDac += d.Acc
-	}
-	n := float64(NModels)
-	writeResult("------------------------------------------------------------------\n")
-	writeResult("AVG | %8.2f | %8.2f | %8.2f | %8.2f | %8.2f | %8.2f\n",
-		sumBad/n, sumSad/n, sumDad/n, sumBac/n, sumSac/n, sumDac/n)
-	writeResult("===============================================================\n")
+	writeSummary(fmt.Sprintf("%d× BENCHMARK (%.0f%% CPUs)", NModels, 100*0.8))
+	writeSignificance("baseline")
 }
 
 func benchmarkReplayDepths() {
@@ -408,11 +363,11 @@ func benchmarkReplayDepths() {
 	)
 
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.3)
 
 	// 2) Concurrency guard
@@ -424,21 +379,15 @@ func benchmarkReplayDepths() {
 	var wg sync.WaitGroup
 
 	// 3) Result storage
-	type key struct {
-		hCnt  int
-		rType string
-	}
-	type metric struct{ adh, acc float64 }
-	results := make(map[key][]metric)
-	var mu sync.Mutex
 
 	// 4) Worker
 	runModel := func(hCnt int, rType string, runIdx int) {
 		defer wg.Done()
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		seed := time.Now().UnixNano() + int64(hCnt)*1e6 + int64(runIdx)*1e4
-		rnd := rand.New(rand.NewSource(seed))
+		streams := seeds.Subf("h=%d/run=%d", hCnt, runIdx)
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
 		net := createNetwork(rType, seed, hCnt)
 		fmt.Printf("🧠 Training %s with %d hidden layers for run %d …\n", rType, hCnt, runIdx)
 		shX := make([][][]float64, len(trainX))
@@ -448,22 +397,8 @@ func benchmarkReplayDepths() {
 			shX[i], shY[i] = trainX[p], trainY[p]
 		}
 		net.Train(shX, shY, epochs, lr, true, 5, -5)
-		correct := 0
-		exp, pred := make([]float64, len(testX)), make([]float64, len(testX))
-		for i, in := range testX {
-			net.Forward(in)
-			p := float64(paragon.ArgMax(net.ExtractOutput()))
-			t := float64(paragon.ArgMax(testY[i][0]))
-			pred[i], exp[i] = p, t
-			if p == t {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		accPct := float64(correct) / float64(len(testX)) * 100.0
-		mu.Lock()
-		results[key{hCnt, rType}] = append(results[key{hCnt, rType}], metric{net.Performance.Score, accPct})
-		mu.Unlock()
+		res := evaluateNet(net, testX, testY)
+		recordRun(fmt.Sprintf("h=%d %s", hCnt, rType), map[string]any{"hidden": hCnt, "replay": rType}, seed, res)
 	}
 
 	// 5) Enqueue runs
@@ -478,32 +413,8 @@ func benchmarkReplayDepths() {
 	wg.Wait()
 
 	// 6) Write results
-	writeResult("\n========= MULTI-HIDDEN LAYER REPLAY BENCHMARK (5 runs each) =========\n")
-	writeResult("Hidden | Replay Type | ADHD(avg) | ±sd   | Acc%%(avg) | ±sd  \n")
-	writeResult("---------------------------------------------------------------------\n")
-	for hCnt := 1; hCnt <= 2; hCnt++ {
-		for _, rType := range []string{"baseline", "static", "dynamic"} {
-			mets := results[key{hCnt, rType}]
-			var sumA, sumAcc float64
-			for _, m := range mets {
-				sumA += m.adh
-				sumAcc += m.acc
-			}
-			n := float64(len(mets))
-			meanA, meanAcc := sumA/n, sumAcc/n
-			var varA, varAcc float64
-			for _, m := range mets {
-				varA += (m.adh - meanA) * (m.adh - meanA)
-				varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-			}
-			sdA := math.Sqrt(varA / n)
-			sdAcc := math.Sqrt(varAcc / n)
-			writeResult("  %d    | %-11s | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-				hCnt, rType, meanA, sdA, meanAcc, sdAcc)
-		}
-		writeResult("---------------------------------------------------------------------\n")
-	}
-	writeResult("=====================================================================\n")
+	writeSummary("MULTI-HIDDEN LAYER REPLAY BENCHMARK (5 runs each)")
+	writeDepthBars("REPLAY DEPTHS")
 }
 
 func benchmarkMaxReplay() {
@@ -517,11 +428,11 @@ func benchmarkMaxReplay() {
 	)
 
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.3)
 
 	// 2) Concurrency
@@ -531,18 +442,15 @@ func benchmarkMaxReplay() {
 	}
 	sem := make(chan struct{}, maxWorkers)
 	var wg sync.WaitGroup
-	type key struct{ maxReplay int }
-	type metric struct{ adh, acc float64 }
-	results := make(map[key][]metric)
-	var mu sync.Mutex
 
 	// 3) Worker
 	runModel := func(maxReplay, runIdx int) {
 		defer wg.Done()
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		seed := time.Now().UnixNano() + int64(maxReplay)*1e6 + int64(runIdx)
-		rnd := rand.New(rand.NewSource(seed))
+		streams := seeds.Subf("max=%d/run=%d", maxReplay, runIdx)
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
 		replayType := "baseline"
 		if maxReplay > 0 {
 			replayType = "static"
@@ -558,23 +466,12 @@ func benchmarkMaxReplay() {
 		for i, p := range perm {
 			shX[i], shY[i] = trainX[p], trainY[p]
 		}
+		counter := compute.Attach(net)
+		counter.Train(len(shX), epochs)
 		net.Train(shX, shY, epochs, lr, true, 5, -5)
-		correct := 0
-		exp, pred := make([]float64, len(testX)), make([]float64, len(testX))
-		for i, in := range testX {
-			net.Forward(in)
-			p := float64(paragon.ArgMax(net.ExtractOutput()))
-			t := float64(paragon.ArgMax(testY[i][0]))
-			pred[i], exp[i] = p, t
-			if p == t {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		accPct := float64(correct) / float64(len(testX)) * 100.0
-		mu.Lock()
-		results[key{maxReplay}] = append(results[key{maxReplay}], metric{net.Performance.Score, accPct})
-		mu.Unlock()
+		counter.Infer(len(testX))
+		res := evaluateNet(net, testX, testY)
+		recordRun(fmt.Sprintf("MaxReplay=%d", maxReplay), map[string]any{"max_replay": maxReplay}, seed, res, counter.Metrics(res.Accuracy))
 	}
 
 	// 4) Launch runs
@@ -587,29 +484,15 @@ func benchmarkMaxReplay() {
 	wg.Wait()
 
 	// 5) Write results
-	writeResult("\n========= MAX REPLAY BENCHMARK (1 Hidden Layer, 5 runs each) =========\n")
-	writeResult("MaxReplay | ADHD(avg) | ±sd   | Acc%%(avg) | ±sd  \n")
-	writeResult("---------------------------------------------------------------------\n")
-	for maxReplay := 0; maxReplay <= maxMaxReplay; maxReplay++ {
-		mets := results[key{maxReplay}]
-		var sumA, sumAcc float64
-		for _, m := range mets {
-			sumA += m.adh
-			sumAcc += m.acc
-		}
-		n := float64(len(mets))
-		meanA, meanAcc := sumA/n, sumAcc/n
-		var varA, varAcc float64
-		for _, m := range mets {
-			varA += (m.adh - meanA) * (m.adh - meanA)
-			varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-		}
-		sdA := math.Sqrt(varA / n)
-		sdAcc := math.Sqrt(varAcc / n)
-		writeResult("    %d     | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-			maxReplay, meanA, sdA, meanAcc, sdAcc)
-	}
-	writeResult("=====================================================================\n")
+	writeSummary("MAX REPLAY BENCHMARK (1 Hidden Layer, 5 runs each)")
+	writeSignificance("MaxReplay=0")
+	writeCompute("MAX REPLAY BENCHMARK")
+	writeFigure("depth", &plot.LineChart{
+		Title:  "MAX REPLAY: TEST ACCURACY",
+		XLabel: "max replay",
+		YLabel: "test accuracy %",
+		Series: plot.Sweep(rec.StepRecords(), "accuracy", "max_replay", nil),
+	})
 }
 
 func benchmarkReplaySweetSpot() {
@@ -621,11 +504,11 @@ func benchmarkReplaySweetSpot() {
 	)
 
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.3)
 
 	// 2) Concurrency
@@ -635,21 +518,15 @@ func benchmarkReplaySweetSpot() {
 	}
 	sem := make(chan struct{}, maxWorkers)
 	var wg sync.WaitGroup
-	type key struct {
-		hCnt  int
-		rType string
-	}
-	type metric struct{ adh, acc float64 }
-	results := make(map[key][]metric)
-	var mu sync.Mutex
 
 	// 3) Worker
 	runModel := func(hCnt int, rType string, runIdx int) {
 		defer wg.Done()
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		seed := time.Now().UnixNano() + int64(hCnt*1e5) + int64(runIdx*137)
-		rnd := rand.New(rand.NewSource(seed))
+		streams := seeds.Subf("h=%d/run=%d", hCnt, runIdx)
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
 		net := createNetwork(rType, seed, hCnt)
 		fmt.Printf("🧠 Training %s with %d hidden layers for run %d …\n", rType, hCnt, runIdx)
 		shX := make([][][]float64, len(trainX))
@@ -663,22 +540,8 @@ func benchmarkReplaySweetSpot() {
 			lr *= lrScaleReplay
 		}
 		net.Train(shX, shY, epochs, lr, true, 5, -5)
-		correct := 0
-		exp, pred := make([]float64, len(testX)), make([]float64, len(testX))
-		for i, in := range testX {
-			net.Forward(in)
-			p := float64(paragon.ArgMax(net.ExtractOutput()))
-			t := float64(paragon.ArgMax(testY[i][0]))
-			pred[i], exp[i] = p, t
-			if p == t {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		accPct := float64(correct) / float64(len(testX)) * 100.0
-		mu.Lock()
-		results[key{hCnt, rType}] = append(results[key{hCnt, rType}], metric{net.Performance.Score, accPct})
-		mu.Unlock()
+		res := evaluateNet(net, testX, testY)
+		recordRun(fmt.Sprintf("h=%d %s", hCnt, rType), map[string]any{"hidden": hCnt, "replay": rType, "lr": lr}, seed, res)
 	}
 
 	// 4) Enqueue jobs
@@ -693,31 +556,8 @@ func benchmarkReplaySweetSpot() {
 	wg.Wait()
 
 	// 5) Write results
-	writeResult("\n========= REPLAY SWEET-SPOT BENCHMARK (5 runs each) =========\n")
-	writeResult("Hidden | Kind         | ADHD(avg) | ±sd   | Acc%%(avg) | ±sd  \n")
-	writeResult("-------------------------------------------------------------\n")
-	for hCnt := 1; hCnt <= 2; hCnt++ {
-		for _, rType := range []string{"baseline", "static", "dynamic"} {
-			mets := results[key{hCnt, rType}]
-			var sumA, sumAcc float64
-			for _, m := range mets {
-				sumA += m.adh
-				sumAcc += m.acc
-			}
-			n := float64(len(mets))
-			meanA, meanAcc := sumA/n, sumAcc/n
-			var varA, varAcc float64
-			for _, m := range mets {
-				varA += (m.adh - meanA) * (m.adh - meanA)
-				varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-			}
-			sdA, sdAcc := math.Sqrt(varA/n), math.Sqrt(varAcc/n)
-			writeResult("  %d    | %-12s | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-				hCnt, rType, meanA, sdA, meanAcc, sdAcc)
-		}
-		writeResult("-------------------------------------------------------------\n")
-	}
-	writeResult("=============================================================\n")
+	writeSummary("REPLAY SWEET-SPOT BENCHMARK (5 runs each)")
+	writeDepthBars("REPLAY SWEET SPOT")
 }
 
 func benchmarkReplayBeforeAfter() {
@@ -728,11 +568,11 @@ func benchmarkReplayBeforeAfter() {
 	)
 
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.3)
 
 	// 2) Concurrency
@@ -750,17 +590,15 @@ func benchmarkReplayBeforeAfter() {
 		dynamic      variant = "dynamic"
 	)
 	allVariants := []variant{baseline, staticBefore, staticAfter, dynamic}
-	type metric struct{ adh, acc float64 }
-	results := make(map[variant][]metric)
-	var mu sync.Mutex
 
 	// 3) Worker
 	run := func(kind variant, runIdx int) {
 		defer wg.Done()
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		seed := time.Now().UnixNano() + int64(runIdx)*113 + int64(len(kind))*1e6
-		rnd := rand.New(rand.NewSource(seed))
+		streams := seeds.Subf("%s/run=%d", kind, runIdx)
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
 		replayType := string(kind)
 		if kind == staticBefore {
 			replayType = "static"
@@ -779,22 +617,8 @@ func benchmarkReplayBeforeAfter() {
 			shX[i], shY[i] = trainX[p], trainY[p]
 		}
 		net.Train(shX, shY, epochs, lr, true, 5, -5)
-		correct := 0
-		exp, pred := make([]float64, len(testX)), make([]float64, len(testX))
-		for i, in := range testX {
-			net.Forward(in)
-			p := float64(paragon.ArgMax(net.ExtractOutput()))
-			t := float64(paragon.ArgMax(testY[i][0]))
-			pred[i], exp[i] = p, t
-			if p == t {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		acc := float64(correct) / float64(len(testX)) * 100.0
-		mu.Lock()
-		results[kind] = append(results[kind], metric{net.Performance.Score, acc})
-		mu.Unlock()
+		res := evaluateNet(net, testX, testY)
+		recordRun(string(kind), map[string]any{"variant": string(kind)}, seed, res)
 	}
 
 	// 4) Launch jobs
@@ -807,29 +631,8 @@ func benchmarkReplayBeforeAfter() {
 	wg.Wait()
 
 	// 5) Write results
-	writeResult("\n========= REPLAY BEFORE vs AFTER vs DYNAMIC (1 hidden layer, 5 runs) =========\n")
-	writeResult("Variant        | ADHD(avg) | ±sd   | Acc%%(avg) | ±sd  \n")
-	writeResult("-------------------------------------------------------------------\n")
-	for _, v := range allVariants {
-		mets := results[v]
-		var sumA, sumAcc float64
-		for _, m := range mets {
-			sumA += m.adh
-			sumAcc += m.acc
-		}
-		n := float64(len(mets))
-		meanA, meanAcc := sumA/n, sumAcc/n
-		var varA, varAcc float64
-		for _, m := range mets {
-			varA += (m.adh - meanA) * (m.adh - meanA)
-			varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-		}
-		sdA := math.Sqrt(varA / n)
-		sdAcc := math.Sqrt(varAcc / n)
-		writeResult("%-13s | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-			string(v), meanA, sdA, meanAcc, sdAcc)
-	}
-	writeResult("===================================================================\n")
+	writeSummary("REPLAY BEFORE vs AFTER vs DYNAMIC (1 hidden layer, 5 runs)")
+	writeSignificance(string(baseline))
 }
 
 func benchmarkReplaySettingsMassive() {
@@ -883,11 +686,11 @@ func benchmarkReplaySettingsMassive() {
 	}
 
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.25) // 25% training data
 
 	// 2) Concurrency guard
@@ -899,22 +702,15 @@ func benchmarkReplaySettingsMassive() {
 	var wg sync.WaitGroup
 
 	// 3) Result storage
-	type key struct {
-		replayType string
-		configDesc string
-		hCnt       int
-	}
-	type metric struct{ adh, acc float64 }
-	results := make(map[key][]metric)
-	var mu sync.Mutex
 
 	// 4) Worker
 	runModel := func(hCnt int, replayType, configDesc string, maxReplay int, replayPhase string, replayOffset int, gateType string, gateThreshold float64, replayBudget int, runIdx int) {
 		defer wg.Done()
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		seed := time.Now().UnixNano() + int64(hCnt*1e5) + int64(runIdx*137)
-		rnd := rand.New(rand.NewSource(seed))
+		streams := seeds.Subf("h=%d/run=%d", hCnt, runIdx)
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
 		net := createNetworkBIGTEST(replayType, seed, hCnt, maxReplay, replayPhase, replayOffset, gateType, gateThreshold, replayBudget)
 		fmt.Printf("🧠 Training %s (%s, hCnt=%d) for run %d …\n", replayType, configDesc, hCnt, runIdx)
 		shX := make([][][]float64, len(trainX))
@@ -924,22 +720,8 @@ func benchmarkReplaySettingsMassive() {
 			shX[i], shY[i] = trainX[p], trainY[p]
 		}
 		net.Train(shX, shY, epochs, lr, true, 5, -5)
-		correct := 0
-		exp, pred := make([]float64, len(testX)), make([]float64, len(testX))
-		for i, in := range testX {
-			net.Forward(in)
-			p := float64(paragon.ArgMax(net.ExtractOutput()))
-			t := float64(paragon.ArgMax(testY[i][0]))
-			pred[i], exp[i] = p, t
-			if p == t {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		accPct := float64(correct) / float64(len(testX)) * 100.0
-		mu.Lock()
-		results[key{replayType, configDesc, hCnt}] = append(results[key{replayType, configDesc, hCnt}], metric{net.Performance.Score, accPct})
-		mu.Unlock()
+		res := evaluateNet(net, testX, testY)
+		recordRun(fmt.Sprintf("h=%d %s", hCnt, configDesc), map[string]any{"hidden": hCnt, "replay": replayType, "max_replay": maxReplay, "phase": replayPhase, "offset": replayOffset, "gate": gateType, "threshold": gateThreshold, "budget": replayBudget}, seed, res)
 	}
 
 	// 5) Enqueue runs
@@ -970,61 +752,19 @@ func benchmarkReplaySettingsMassive() {
 	wg.Wait()
 
 	// 6) Write results
-	for hCnt := 1; hCnt <= 2; hCnt++ {
-		writeResult("\n========= MASSIVE REPLAY SETTINGS BENCHMARK (hCnt=%d, %d runs each) =========\n", hCnt, nRuns)
-		writeResult("Replay Type | Configuration                          | ADHD(avg) | ±sd   | Acc%%(avg) | ±sd  \n")
-		writeResult("---------------------------------------------------------------------------\n")
-		// Static configurations
-		for _, cfg := range staticConfigs {
-			replayType := "static"
-			configDesc := fmt.Sprintf("MaxReplay=%d,Phase=%s,Offset=%d", cfg.maxReplay, cfg.replayPhase, cfg.replayOffset)
-			if cfg.maxReplay == 0 {
-				replayType = "baseline"
-				configDesc = "NoReplay"
-			}
-			mets := results[key{replayType, configDesc, hCnt}]
-			var sumA, sumAcc float64
-			for _, m := range mets {
-				sumA += m.adh
-				sumAcc += m.acc
-			}
-			n := float64(len(mets))
-			meanA, meanAcc := sumA/n, sumAcc/n
-			var varA, varAcc float64
-			for _, m := range mets {
-				varA += (m.adh - meanA) * (m.adh - meanA)
-				varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-			}
-			sdA := math.Sqrt(varA / n)
-			sdAcc := math.Sqrt(varAcc / n)
-			writeResult("%-11s | %-37s | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-				replayType, configDesc, meanA, sdA, meanAcc, sdAcc)
-		}
-		// Dynamic configurations
-		for _, cfg := range dynamicConfigs {
-			configDesc := fmt.Sprintf("Gate=%s,Threshold=%.1f,Budget=%d,Phase=%s,Offset=%d",
-				cfg.gateType, cfg.threshold, cfg.replayBudget, cfg.replayPhase, cfg.replayOffset)
-			mets := results[key{"dynamic", configDesc, hCnt}]
-			var sumA, sumAcc float64
-			for _, m := range mets {
-				sumA += m.adh
-				sumAcc += m.acc
-			}
-			n := float64(len(mets))
-			meanA, meanAcc := sumA/n, sumAcc/n
-			var varA, varAcc float64
-			for _, m := range mets {
-				varA += (m.adh - meanA) * (m.adh - meanA)
-				varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-			}
-			sdA := math.Sqrt(varA / n)
-			sdAcc := math.Sqrt(varAcc / n)
-			writeResult("%-11s | %-37s | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-				"dynamic", configDesc, meanA, sdA, meanAcc, sdAcc)
-		}
-		writeResult("---------------------------------------------------------------------------\n")
+	writeSummary(fmt.Sprintf("MASSIVE REPLAY SETTINGS BENCHMARK (%d runs each)", nRuns))
+	writeSettingsHeatmap("MASSIVE REPLAY SETTINGS")
+}
+
+// gateName maps replay6's gate labels onto arena/gates: its "entropy" was
+// the raw-output entropy. "confidence" and "loss" never had a gate
+// function, so they are passed through and createNetworkBIGTEST leaves
+// them ungated as before; name "margin" to gate on confidence.
+func gateName(gateType string) string {
+	if gateType == "entropy" {
+		return "raw-entropy"
 	}
-	writeResult("===========================================================================\n")
+	return gateType
 }
 
 func createNetworkBIGTEST(replayType string, seed int64, hCnt int, maxReplay int, replayPhase string, replayOffset int, gateType string, gateThreshold float64, replayBudget int) *paragon.Network[float32] {
@@ -1048,67 +788,15 @@ func createNetworkBIGTEST(replayType string, seed int64, hCnt int, maxReplay int
 		net.Layers[1].ReplayPhase = replayPhase
 		net.Layers[1].MaxReplay = maxReplay
 	} else if replayType == "dynamic" {
-		net.Layers[1].ReplayEnabled = true
-		net.Layers[1].ReplayBudget = replayBudget
 		net.Layers[1].ReplayPhase = replayPhase
 		net.Layers[1].ReplayOffset = replayOffset
-		switch gateType {
-		case "entropy":
-			net.Layers[1].ReplayGateFunc = func(input [][]float32) float64 {
-				outputs := net.Layers[1].CachedOutputs
-				if len(outputs) == 0 {
-					return 0.5
-				}
-				var sum float64
-				for _, v := range outputs {
-					v64 := float64(v)
-					if v64 > 1e-10 {
-						sum += v64 * math.Log(v64)
-					}
-				}
-				entropy := -sum / float64(len(outputs))
-				maxEntropy := math.Log(float64(len(outputs)))
-				if maxEntropy == 0 {
-					return 0.5
-				}
-				return math.Min(1.0, math.Max(0.0, entropy/maxEntropy))
-			}
-		case "variance":
-			net.Layers[1].ReplayGateFunc = func(input [][]float32) float64 {
-				outputs := net.Layers[1].CachedOutputs
-				if len(outputs) == 0 {
-					return 0.5
-				}
-				var mean, sumSq float64
-				n := float64(len(outputs))
-				for _, v := range outputs {
-					v64 := float64(v)
-					mean += v64 / n
-					sumSq += v64 * v64
-				}
-				variance := sumSq/n - mean*mean
-				return math.Min(1.0, math.Max(0.0, variance/0.1))
-			}
-		case "gradient":
-			net.Layers[1].ReplayGateFunc = func(input [][]float32) float64 {
-				outputs := net.Layers[1].CachedOutputs
-				if len(outputs) < 2 {
-					return 0.5
-				}
-				var sumDiff float64
-				for i := 1; i < len(outputs); i++ {
-					diff := float64(outputs[i] - outputs[i-1])
-					sumDiff += diff * diff
-				}
-				gradient := sumDiff / float64(len(outputs)-1)
-				return math.Min(1.0, math.Max(0.0, gradient/0.1))
-			}
-		}
-		net.Layers[1].ReplayGateToReps = func(score float64) int {
-			if score > gateThreshold {
-				return replayBudget
-			}
-			return 0
+		policy := gates.Threshold(gateThreshold, replayBudget)
+		if !gates.Known(gateName(gateType)) {
+			net.Layers[1].ReplayEnabled = true
+			net.Layers[1].ReplayBudget = replayBudget
+			net.Layers[1].ReplayGateToReps = policy
+		} else if err := gates.Apply(&net.Layers[1], gateName(gateType), policy, replayBudget); err != nil {
+			log.Fatal(err)
 		}
 	}
 	return net
@@ -1149,11 +837,11 @@ func benchmarkDeepReplaySettings() {
 	}
 
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.2) // 20% training data
 
 	// 2) Concurrency guard
@@ -1165,22 +853,15 @@ func benchmarkDeepReplaySettings() {
 	var wg sync.WaitGroup
 
 	// 3) Result storage
-	type key struct {
-		replayType string
-		configDesc string
-		hCnt       int
-	}
-	type metric struct{ adh, acc float64 }
-	results := make(map[key][]metric)
-	var mu sync.Mutex
 
 	// 4) Worker
 	runModel := func(hCnt int, replayType, configDesc string, maxReplay int, replayPhase string, replayOffset int, gateType string, gateThreshold float64, replayBudget int, runIdx int) {
 		defer wg.Done()
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		seed := time.Now().UnixNano() + int64(hCnt*1e5) + int64(runIdx*137)
-		rnd := rand.New(rand.NewSource(seed))
+		streams := seeds.Subf("h=%d/run=%d", hCnt, runIdx)
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
 		net := createNetworkBIGTEST(replayType, seed, hCnt, maxReplay, replayPhase, replayOffset, gateType, gateThreshold, replayBudget)
 		fmt.Printf("🧠 Training %s (%s, hCnt=%d) for run %d …\n", replayType, configDesc, hCnt, runIdx)
 		shX := make([][][]float64, len(trainX))
@@ -1190,22 +871,8 @@ func benchmarkDeepReplaySettings() {
 			shX[i], shY[i] = trainX[p], trainY[p]
 		}
 		net.Train(shX, shY, epochs, lr, true, 5, -5)
-		correct := 0
-		exp, pred := make([]float64, len(testX)), make([]float64, len(testX))
-		for i, in := range testX {
-			net.Forward(in)
-			p := float64(paragon.ArgMax(net.ExtractOutput()))
-			t := float64(paragon.ArgMax(testY[i][0]))
-			pred[i], exp[i] = p, t
-			if p == t {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		accPct := float64(correct) / float64(len(testX)) * 100.0
-		mu.Lock()
-		results[key{replayType, configDesc, hCnt}] = append(results[key{replayType, configDesc, hCnt}], metric{net.Performance.Score, accPct})
-		mu.Unlock()
+		res := evaluateNet(net, testX, testY)
+		recordRun(fmt.Sprintf("h=%d %s", hCnt, configDesc), map[string]any{"hidden": hCnt, "replay": replayType, "max_replay": maxReplay, "phase": replayPhase, "offset": replayOffset, "gate": gateType, "threshold": gateThreshold, "budget": replayBudget}, seed, res)
 	}
 
 	// 5) Enqueue runs
@@ -1236,61 +903,8 @@ func benchmarkDeepReplaySettings() {
 	wg.Wait()
 
 	// 6) Write results
-	for hCnt := 3; hCnt <= 5; hCnt++ {
-		writeResult("\n========= DEEP REPLAY SETTINGS BENCHMARK (hCnt=%d, %d runs each) =========\n", hCnt, nRuns)
-		writeResult("Replay Type | Configuration                          | ADHD(avg) | ±sd   | Acc%%(avg) | ±sd  \n")
-		writeResult("---------------------------------------------------------------------------\n")
-		// Static configurations
-		for _, cfg := range staticConfigs {
-			replayType := "static"
-			configDesc := fmt.Sprintf("MaxReplay=%d,Phase=%s,Offset=%d", cfg.maxReplay, cfg.replayPhase, cfg.replayOffset)
-			if cfg.maxReplay == 0 {
-				replayType = "baseline"
-				configDesc = "NoReplay"
-			}
-			mets := results[key{replayType, configDesc, hCnt}]
-			var sumA, sumAcc float64
-			for _, m := range mets {
-				sumA += m.adh
-				sumAcc += m.acc
-			}
-			n := float64(len(mets))
-			meanA, meanAcc := sumA/n, sumAcc/n
-			var varA, varAcc float64
-			for _, m := range mets {
-				varA += (m.adh - meanA) * (m.adh - meanA)
-				varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-			}
-			sdA := math.Sqrt(varA / n)
-			sdAcc := math.Sqrt(varAcc / n)
-			writeResult("%-11s | %-37s | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-				replayType, configDesc, meanA, sdA, meanAcc, sdAcc)
-		}
-		// Dynamic configurations
-		for _, cfg := range dynamicConfigs {
-			configDesc := fmt.Sprintf("Gate=%s,Threshold=%.1f,Budget=%d,Phase=%s,Offset=%d",
-				cfg.gateType, cfg.threshold, cfg.replayBudget, cfg.replayPhase, cfg.replayOffset)
-			mets := results[key{"dynamic", configDesc, hCnt}]
-			var sumA, sumAcc float64
-			for _, m := range mets {
-				sumA += m.adh
-				sumAcc += m.acc
-			}
-			n := float64(len(mets))
-			meanA, meanAcc := sumA/n, sumAcc/n
-			var varA, varAcc float64
-			for _, m := range mets {
-				varA += (m.adh - meanA) * (m.adh - meanA)
-				varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-			}
-			sdA := math.Sqrt(varA / n)
-			sdAcc := math.Sqrt(varAcc / n)
-			writeResult("%-11s | %-37s | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-				"dynamic", configDesc, meanA, sdA, meanAcc, sdAcc)
-		}
-		writeResult("---------------------------------------------------------------------------\n")
-	}
-	writeResult("===========================================================================\n")
+	writeSummary(fmt.Sprintf("DEEP REPLAY SETTINGS BENCHMARK (%d runs each)", nRuns))
+	writeSettingsHeatmap("DEEP REPLAY SETTINGS")
 }
 
 func benchmarkDynamicReplayOptimizer() {
@@ -1343,11 +957,11 @@ func benchmarkDynamicReplayOptimizer() {
 	}
 
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.2) // 20% training data
 
 	// 2) Concurrency guard
@@ -1359,21 +973,15 @@ func benchmarkDynamicReplayOptimizer() {
 	var wg sync.WaitGroup
 
 	// 3) Result storage
-	type key struct {
-		replayType string
-		configDesc string
-	}
-	type metric struct{ adh, acc float64 }
-	results := make(map[key][]metric)
-	var mu sync.Mutex
 
 	// 4) Worker
 	runModel := func(replayType, configDesc string, gateType string, gateThreshold float64, replayBudget int, replayPhase string, replayOffset int, runIdx int) {
 		defer wg.Done()
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		seed := time.Now().UnixNano() + int64(runIdx*137)
-		rnd := rand.New(rand.NewSource(seed))
+		streams := seeds.Subf("run=%d", runIdx)
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
 		net := createNetworkBIGTEST(replayType, seed, hCnt, 0, replayPhase, replayOffset, gateType, gateThreshold, replayBudget)
 		fmt.Printf("🧠 Training %s (%s) for run %d …\n", replayType, configDesc, runIdx)
 		shX := make([][][]float64, len(trainX))
@@ -1382,24 +990,29 @@ func benchmarkDynamicReplayOptimizer() {
 		for i, p := range perm {
 			shX[i], shY[i] = trainX[p], trainY[p]
 		}
-		net.Train(shX, shY, epochs, lr, true, 5, -5)
-		correct := 0
-		exp, pred := []float64{}, []float64{}
-		for i, in := range testX {
-			net.Forward(in)
-			p := float64(paragon.ArgMax(net.ExtractOutput()))
-			t := float64(paragon.ArgMax(testY[i][0]))
-			pred = append(pred, p)
-			exp = append(exp, t)
-			if p == t {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		accPct := float64(correct) / float64(len(testX)) * 100.0
-		mu.Lock()
-		results[key{replayType, configDesc}] = append(results[key{replayType, configDesc}], metric{net.Performance.Score, accPct})
-		mu.Unlock()
+		params := map[string]any{"hidden": hCnt, "replay": replayType, "phase": replayPhase, "offset": replayOffset, "gate": gateType, "threshold": gateThreshold, "budget": replayBudget}
+		if replayType != "dynamic" {
+			net.Train(shX, shY, epochs, lr, true, 5, -5)
+			recordRun(configDesc, params, seed, evaluateNet(net, testX, testY))
+			return
+		}
+		// one epoch at a time so each is traced as its own, with samples
+		// mapped back through the shuffle
+		tr := trace.Attach(net)
+		tr.Order(perm)
+		if _, err := train.Fit(net, shX, shY, train.Options[float32]{
+			Epochs:                  epochs,
+			LearningRate:            lr,
+			EarlyStopOnNegativeLoss: true,
+			ClipUpper:               5,
+			ClipLower:               -5,
+			BeforeEpoch:             func(e int) { tr.Begin(trace.Train, e+1) },
+		}); err != nil {
+			log.Printf("Training %s (%s) run %d: %v", replayType, configDesc, runIdx, err)
+		}
+		res := trace.Evaluate(tr, net, testX, testY, 3)
+		recordRun(configDesc, params, seed, res, tr.Metrics())
+		keepTrace(configDesc, runIdx, tr, res.Accuracy)
 	}
 
 	// 5) Enqueue runs
@@ -1421,39 +1034,9 @@ func benchmarkDynamicReplayOptimizer() {
 	wg.Wait()
 
 	// 6) Write results
-	writeResult("\n========= DYNAMIC REPLAY OPTIMIZER BENCHMARK (hCnt=%d, %d runs each) =========\n", hCnt, nRuns)
-	writeResult("Replay Type | Configuration                          | ADHD(avg) | ±sd   | Acc%%(avg) | ±sd  \n")
-	writeResult("---------------------------------------------------------------------------\n")
-	for _, cfg := range configs {
-		replayType := "dynamic"
-		if cfg.gateType == "none" {
-			replayType = "baseline"
-		}
-		configDesc := fmt.Sprintf("Gate=%s,Threshold=%.1f,Budget=%d,Phase=%s,Offset=%d",
-			cfg.gateType, cfg.threshold, cfg.replayBudget, cfg.replayPhase, cfg.replayOffset)
-		if cfg.gateType == "none" {
-			configDesc = "NoReplay"
-		}
-		mets := results[key{replayType, configDesc}]
-		var sumA, sumAcc float64
-		for _, m := range mets {
-			sumA += m.adh
-			sumAcc += m.acc
-		}
-		n := float64(len(mets))
-		meanA, meanAcc := sumA/n, sumAcc/n
-		var varA, varAcc float64
-		for _, m := range mets {
-			varA += (m.adh - meanA) * (m.adh - meanA)
-			varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-		}
-		sdA := math.Sqrt(varA / n)
-		sdAcc := math.Sqrt(varAcc / n)
-		writeResult("%-11s | %-37s | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-			replayType, configDesc, meanA, sdA, meanAcc, sdAcc)
-	}
-	writeResult("---------------------------------------------------------------------------\n")
-	writeResult("===========================================================================\n")
+	writeSummary(fmt.Sprintf("DYNAMIC REPLAY OPTIMIZER BENCHMARK (hCnt=%d, %d runs each)", hCnt, nRuns))
+	writeSignificance("NoReplay")
+	writeTraces("DYNAMIC REPLAY OPTIMIZER")
 }
 
 func benchmarkAdaptiveTemporalReplay() {
@@ -1481,11 +1064,11 @@ func benchmarkAdaptiveTemporalReplay() {
 	}
 
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.2) // 20% training data
 
 	// 2) Concurrency guard
@@ -1497,21 +1080,15 @@ func benchmarkAdaptiveTemporalReplay() {
 	var wg sync.WaitGroup
 
 	// 3) Result storage
-	type key struct {
-		replayType string
-		configDesc string
-	}
-	type metric struct{ adh, acc float64 }
-	results := make(map[key][]metric)
-	var mu sync.Mutex
 
 	// 4) Worker
 	runModel := func(replayType, configDesc string, gateType string, gateThreshold float64, replayBudget int, replayPhase string, replayOffset int, runIdx int) {
 		defer wg.Done()
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		seed := time.Now().UnixNano() + int64(runIdx*137)
-		rnd := rand.New(rand.NewSource(seed))
+		streams := seeds.Subf("run=%d", runIdx)
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
 		net := createNetworkTEMPORTAL(replayType, seed, hCnt, 0, replayPhase, replayOffset, gateType, gateThreshold, replayBudget)
 		fmt.Printf("🧠 Training %s (%s) for run %d …\n", replayType, configDesc, runIdx)
 		shX := make([][][]float64, len(trainX))
@@ -1520,24 +1097,29 @@ func benchmarkAdaptiveTemporalReplay() {
 		for i, p := range perm {
 			shX[i], shY[i] = trainX[p], trainY[p]
 		}
-		net.Train(shX, shY, epochs, lr, true, 5, -5)
-		correct := 0
-		exp, pred := []float64{}, []float64{}
-		for i, in := range testX {
-			net.Forward(in)
-			p := float64(paragon.ArgMax(net.ExtractOutput()))
-			t := float64(paragon.ArgMax(testY[i][0]))
-			pred = append(pred, p)
-			exp = append(exp, t)
-			if p == t {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		accPct := float64(correct) / float64(len(testX)) * 100.0
-		mu.Lock()
-		results[key{replayType, configDesc}] = append(results[key{replayType, configDesc}], metric{net.Performance.Score, accPct})
-		mu.Unlock()
+		params := map[string]any{"hidden": hCnt, "replay": replayType, "phase": replayPhase, "offset": replayOffset, "gate": gateType, "threshold": gateThreshold, "budget": replayBudget}
+		if replayType != "dynamic" {
+			net.Train(shX, shY, epochs, lr, true, 5, -5)
+			recordRun(configDesc, params, seed, evaluateNet(net, testX, testY))
+			return
+		}
+		// one epoch at a time so each is traced as its own, with samples
+		// mapped back through the shuffle
+		tr := trace.Attach(net)
+		tr.Order(perm)
+		if _, err := train.Fit(net, shX, shY, train.Options[float32]{
+			Epochs:                  epochs,
+			LearningRate:            lr,
+			EarlyStopOnNegativeLoss: true,
+			ClipUpper:               5,
+			ClipLower:               -5,
+			BeforeEpoch:             func(e int) { tr.Begin(trace.Train, e+1) },
+		}); err != nil {
+			log.Printf("Training %s (%s) run %d: %v", replayType, configDesc, runIdx, err)
+		}
+		res := trace.Evaluate(tr, net, testX, testY, 3)
+		recordRun(configDesc, params, seed, res, tr.Metrics())
+		keepTrace(configDesc, runIdx, tr, res.Accuracy)
 	}
 
 	// 5) Enqueue runs
@@ -1559,39 +1141,8 @@ func benchmarkAdaptiveTemporalReplay() {
 	wg.Wait()
 
 	// 6) Write results
-	writeResult("\n========= ADAPTIVE TEMPORAL REPLAY BENCHMARK (hCnt=%d, %d runs each) =========\n", hCnt, nRuns)
-	writeResult("Replay Type | Configuration                          | ADHD(avg) | ±sd   | Acc%%(avg) | ±sd  \n")
-	writeResult("---------------------------------------------------------------------------\n")
-	for _, cfg := range configs {
-		replayType := "dynamic"
-		if cfg.gateType == "none" {
-			replayType = "baseline"
-		}
-		configDesc := fmt.Sprintf("Gate=%s,Threshold=%.1f,Budget=%d,Phase=%s,Offset=%d",
-			cfg.gateType, cfg.threshold, cfg.replayBudget, cfg.replayPhase, cfg.replayOffset)
-		if cfg.gateType == "none" {
-			configDesc = "NoReplay"
-		}
-		mets := results[key{replayType, configDesc}]
-		var sumA, sumAcc float64
-		for _, m := range mets {
-			sumA += m.adh
-			sumAcc += m.acc
-		}
-		n := float64(len(mets))
-		meanA, meanAcc := sumA/n, sumAcc/n
-		var varA, varAcc float64
-		for _, m := range mets {
-			varA += (m.adh - meanA) * (m.adh - meanA)
-			varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-		}
-		sdA := math.Sqrt(varA / n)
-		sdAcc := math.Sqrt(varAcc / n)
-		writeResult("%-11s | %-37s | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-			replayType, configDesc, meanA, sdA, meanAcc, sdAcc)
-	}
-	writeResult("---------------------------------------------------------------------------\n")
-	writeResult("===========================================================================\n")
+	writeSummary(fmt.Sprintf("ADAPTIVE TEMPORAL REPLAY BENCHMARK (hCnt=%d, %d runs each)", hCnt, nRuns))
+	writeSignificance("NoReplay")
 }
 
 func createNetworkTEMPORTAL(replayType string, seed int64, hCnt int, maxReplay int, replayPhase string, replayOffset int, gateType string, gateThreshold float64, replayBudget int) *paragon.Network[float32] {
@@ -1611,93 +1162,10 @@ func createNetworkTEMPORTAL(replayType string, seed int64, hCnt int, maxReplay i
 	}
 	net := paragon.NewNetwork[float32](layers, acts, fc, seed)
 	if replayType == "dynamic" {
-		net.Layers[1].ReplayEnabled = true
-		net.Layers[1].ReplayBudget = replayBudget
 		net.Layers[1].ReplayPhase = replayPhase
 		net.Layers[1].ReplayOffset = replayOffset
-		switch gateType {
-		case "variance":
-			net.Layers[1].ReplayGateFunc = func(input [][]float32) float64 {
-				outputs := net.Layers[1].CachedOutputs
-				if len(outputs) == 0 {
-					return 0.5
-				}
-				var mean, sumSq float64
-				n := float64(len(outputs))
-				for _, v := range outputs {
-					v64 := float64(v)
-					mean += v64 / n
-					sumSq += v64 * v64
-				}
-				variance := sumSq/n - mean*mean
-				score := math.Min(1.0, math.Max(0.0, variance/0.1))
-				log.Printf("Variance score: %f", score)
-				return score
-			}
-		case "temporal":
-			net.Layers[1].ReplayGateFunc = func(input [][]float32) float64 {
-				outputs := net.Layers[1].CachedOutputsHistory
-				if len(outputs) < 5 {
-					return 0.5 // Default until enough history
-				}
-				var avgVar float64
-				for i := 0; i < len(outputs[0]); i++ {
-					var mean, sumSq float64
-					n := float64(len(outputs))
-					for _, hist := range outputs {
-						v64 := float64(hist[i])
-						mean += v64 / n
-						sumSq += v64 * v64
-					}
-					variance := sumSq/n - mean*mean
-					avgVar += variance / 0.1 // Fixed divisor
-				}
-				score := avgVar / float64(len(outputs[0]))
-				log.Printf("Temporal score: %f", score)
-				return math.Min(1.0, math.Max(0.0, score))
-			}
-		case "hybrid":
-			net.Layers[1].ReplayGateFunc = func(input [][]float32) float64 {
-				outputs := net.Layers[1].CachedOutputsHistory
-				currOutputs := net.Layers[1].CachedOutputs
-				if len(outputs) < 5 || len(currOutputs) == 0 {
-					return 0.5
-				}
-				// Temporal score
-				var avgVar float64
-				for i := 0; i < len(outputs[0]); i++ {
-					var mean, sumSq float64
-					n := float64(len(outputs))
-					for _, hist := range outputs {
-						v64 := float64(hist[i])
-						mean += v64 / n
-						sumSq += v64 * v64
-					}
-					variance := sumSq/n - mean*mean
-					avgVar += variance / 0.1
-				}
-				temporalScore := avgVar / float64(len(outputs[0]))
-				// Spatial variance score
-				var mean, sumSq float64
-				n := float64(len(currOutputs))
-				for _, v := range currOutputs {
-					v64 := float64(v)
-					mean += v64 / n
-					sumSq += v64 * v64
-				}
-				spatialVariance := sumSq/n - mean*mean
-				spatialScore := math.Min(1.0, math.Max(0.0, spatialVariance/0.1))
-				// Combined score
-				score := 0.6*temporalScore + 0.4*spatialScore
-				log.Printf("Hybrid Temporal-Spatial score: %f (Temporal: %f, Spatial: %f)", score, temporalScore, spatialScore)
-				return math.Min(1.0, math.Max(0.0, score))
-			}
-		}
-		net.Layers[1].ReplayGateToReps = func(score float64) int {
-			if score > gateThreshold {
-				return int(math.Min(float64(replayBudget), math.Ceil(score*float64(replayBudget))))
-			}
-			return 0
+		if err := gates.Apply(&net.Layers[1], gateName(gateType), gates.Scaled(gateThreshold, replayBudget), replayBudget); err != nil {
+			log.Fatal(err)
 		}
 	}
 	return net
@@ -1736,11 +1204,11 @@ func benchmarkEnhancedTemporalReplay() {
 	}
 
 	// 1) Load data
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatal(err)
 	}
-	trainX, trainY, _ := loadMNISTData(mnistDir, true)
-	testX, testY, _ := loadMNISTData(mnistDir, false)
+	trainX, trainY, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testX, testY, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainX, trainY, _, _ = paragon.SplitDataset(trainX, trainY, 0.2) // 20% training data
 
 	// 2) Concurrency guard
@@ -1752,21 +1220,15 @@ func benchmarkEnhancedTemporalReplay() {
 	var wg sync.WaitGroup
 
 	// 3) Result storage
-	type key struct {
-		replayType string
-		configDesc string
-	}
-	type metric struct{ adh, acc float64 }
-	results := make(map[key][]metric)
-	var mu sync.Mutex
 
 	// 4) Worker
 	runModel := func(replayType, configDesc, gateType string, threshold float64, replayBudget int, replayPhase string, replayOffset int, runIdx int) {
 		defer wg.Done()
 		sem <- struct{}{}
 		defer func() { <-sem }()
-		seed := time.Now().UnixNano() + int64(runIdx*137)
-		rnd := rand.New(rand.NewSource(seed))
+		streams := seeds.Subf("run=%d", runIdx)
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
 		net := createNetworkTEMPORTAL(replayType, seed, hCnt, 0, replayPhase, replayOffset, gateType, threshold, replayBudget)
 		fmt.Printf("🧠 Training %s (%s) for run %d …\n", replayType, configDesc, runIdx)
 		shX := make([][][]float64, len(trainX))
@@ -1776,23 +1238,8 @@ func benchmarkEnhancedTemporalReplay() {
 			shX[i], shY[i] = trainX[p], trainY[p]
 		}
 		net.Train(shX, shY, epochs, lr, true, 5, -5)
-		correct := 0
-		exp, pred := []float64{}, []float64{}
-		for i, in := range testX {
-			net.Forward(in)
-			p := float64(paragon.ArgMax(net.ExtractOutput()))
-			t := float64(paragon.ArgMax(testY[i][0]))
-			pred = append(pred, p)
-			exp = append(exp, t)
-			if p == t {
-				correct++
-			}
-		}
-		net.EvaluateModel(exp, pred)
-		accPct := float64(correct) / float64(len(testX)) * 100.0
-		mu.Lock()
-		results[key{replayType, configDesc}] = append(results[key{replayType, configDesc}], metric{net.Performance.Score, accPct})
-		mu.Unlock()
+		res := evaluateNet(net, testX, testY)
+		recordRun(configDesc, map[string]any{"hidden": hCnt, "replay": replayType, "phase": replayPhase, "offset": replayOffset, "gate": gateType, "threshold": threshold, "budget": replayBudget}, seed, res)
 	}
 
 	// 5) Enqueue runs
@@ -1814,37 +1261,6 @@ func benchmarkEnhancedTemporalReplay() {
 	wg.Wait()
 
 	// 6) Write results
-	writeResult("\n========= ENHANCED TEMPORAL REPLAY BENCHMARK (hCnt=%d, %d runs each) =========\n", hCnt, nRuns)
-	writeResult("Replay Type | Configuration                          | ADHD(avg) | ±sd   | Acc%%(avg) | ±sd  \n")
-	writeResult("---------------------------------------------------------------------------\n")
-	for _, cfg := range configs {
-		replayType := "dynamic"
-		if cfg.gateType == "none" {
-			replayType = "baseline"
-		}
-		configDesc := fmt.Sprintf("Gate=%s,Threshold=%.1f,Budget=%d,Phase=%s,Offset=%d",
-			cfg.gateType, cfg.threshold, cfg.replayBudget, cfg.replayPhase, cfg.replayOffset)
-		if cfg.gateType == "none" {
-			configDesc = "NoReplay"
-		}
-		mets := results[key{replayType, configDesc}]
-		var sumA, sumAcc float64
-		for _, m := range mets {
-			sumA += m.adh
-			sumAcc += m.acc
-		}
-		n := float64(len(mets))
-		meanA, meanAcc := sumA/n, sumAcc/n
-		var varA, varAcc float64
-		for _, m := range mets {
-			varA += (m.adh - meanA) * (m.adh - meanA)
-			varAcc += (m.acc - meanAcc) * (m.acc - meanAcc)
-		}
-		sdA := math.Sqrt(varA / n)
-		sdAcc := math.Sqrt(varAcc / n)
-		writeResult("%-11s | %-37s | %8.2f | %5.2f |   %6.2f | %5.2f\n",
-			replayType, configDesc, meanA, sdA, meanAcc, sdAcc)
-	}
-	writeResult("---------------------------------------------------------------------------\n")
-	writeResult("===========================================================================\n")
+	writeSummary(fmt.Sprintf("ENHANCED TEMPORAL REPLAY BENCHMARK (hCnt=%d, %d runs each)", hCnt, nRuns))
+	writeSignificance("NoReplay")
 }
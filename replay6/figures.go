@@ -0,0 +1,67 @@
+package main
+
+import (
+	"arena/plot"
+	"arena/results"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// Figures go to figures/<step>-<name>.png next to results.txt.
+const figureDir = "figures"
+
+// maxBars is the most models writeSummary still draws as a bar chart; the
+// settings grids get heatmaps instead.
+const maxBars = 12
+
+// writeFigure saves c for the current step
+func writeFigure(name string, c plot.Chart) {
+	recs := rec.StepRecords()
+	if len(recs) == 0 {
+		return
+	}
+	path := filepath.Join(figureDir, fmt.Sprintf("%s-%s.png", recs[0].Step, name))
+	if err := plot.Save(path, c); err != nil {
+		log.Printf("Failed to write figure: %v", err)
+		return
+	}
+	fmt.Printf("📈 Saved %s\n", path)
+}
+
+// writeSummaryBars charts mean ± std accuracy per model
+func writeSummaryBars(title string) {
+	c := plot.Bars(rec.StepRecords(), "accuracy", plot.ByModel, nil)
+	if len(c.Groups) == 0 || len(c.Groups) > maxBars {
+		return
+	}
+	c.Title, c.YLabel = title, "test accuracy %"
+	writeFigure("accuracy", c)
+}
+
+// writeDepthBars groups accuracy and ADHD by hidden depth, one bar per
+// replay mode
+func writeDepthBars(title string) {
+	for _, metric := range []string{"accuracy", "adhd"} {
+		c := plot.Bars(rec.StepRecords(), metric, hiddenKey, plot.Param("replay"))
+		c.Title = fmt.Sprintf("%s: %s", title, metric)
+		writeFigure("depth-"+metric, c)
+	}
+}
+
+// writeSettingsHeatmap shows accuracy of every replay setting (rows) at
+// every hidden depth (columns)
+func writeSettingsHeatmap(title string) {
+	setting := func(r results.Record) string {
+		_, desc, _ := strings.Cut(r.Model, " ") // drop the "h=N " prefix
+		return desc
+	}
+	c := plot.Grid(rec.StepRecords(), "accuracy", setting, hiddenKey)
+	c.Title, c.XLabel = title+": test accuracy %", "hidden layers"
+	writeFigure("heatmap", c)
+}
+
+func hiddenKey(r results.Record) string {
+	return "h=" + plot.Param("hidden")(r)
+}
@@ -2,11 +2,17 @@ module main
 
 go 1.24.3
 
-require paragon v0.0.0
+require (
+	arena v0.0.0
+	paragon v0.0.0
+)
 
 require (
 	github.com/openfluke/pilot v0.0.2 // indirect
 	github.com/openfluke/webgpu v0.0.0-20250606223622-ea0f1659b3ca // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace paragon => ../../
+
+replace arena => ../arena
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"arena/datasets/mnist"
+	"arena/experiment"
+	"arena/rng"
+	"arena/search"
+	"fmt"
+	"io"
+	"os"
+	"paragon"
+)
+
+// replaySpace covers what benchmarkReplaySettingsMassive,
+// benchmarkDeepReplaySettings and benchmarkDynamicReplayOptimizer loop
+// over by hand. Each replay mode only carries the settings
+// createNetworkBIGTEST reads for it, so a grid has 3·(1+16+180) points.
+var replaySpace = search.Space{
+	search.Int("hidden", 1, 3),
+	search.Choice("replay", "baseline", "static", "dynamic"),
+	search.Int("max_replay", 1, 4).When("replay", "static"),
+	search.Choice("phase", "before", "after").When("replay", "static", "dynamic"),
+	search.Int("offset", -2, -1).When("replay", "static", "dynamic"),
+	search.Choice("gate", "entropy", "variance", "gradient", "confidence", "hybrid").When("replay", "dynamic"),
+	search.Float("threshold", 0.3, 0.7).When("replay", "dynamic"),
+	search.Int("budget", 1, 3).When("replay", "dynamic"),
+}
+
+// searchReplaySettings tunes replay settings with one of the search
+// schedulers, one epoch per budget unit. Trials are scored on a validation
+// set cut from the training data the search does not train on; the test
+// set scores only the winner, retrained once the search is over. Finished
+// trials go to search-<scheduler>.jsonl with the seed; rerunning with the
+// same -seed resumes, and another seed is refused.
+func searchReplaySettings(ctx *experiment.Context) error {
+	const (
+		maxEpochs = 9
+		eta       = 3
+		nRandom   = 60
+		lr        = 0.001
+		nVal      = 10000
+	)
+	scheduler := "hyperband"
+	if len(ctx.Args) > 0 {
+		scheduler = ctx.Args[0]
+	}
+
+	if err := mnist.Ensure(mnistDir); err != nil {
+		return err
+	}
+	trainX, trainY, err := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	if err != nil {
+		return err
+	}
+	testX, testY, err := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
+	if err != nil {
+		return err
+	}
+	// 25% training data; validation comes from the rest
+	trainX, trainY, valX, valY := paragon.SplitDataset(trainX, trainY, 0.25)
+	valX, valY = valX[:min(nVal, len(valX))], valY[:min(nVal, len(valY))]
+
+	// Every budget of a point starts from the same weights and shuffle.
+	train := func(p search.Point, epochs int) (*paragon.Network[float32], int64) {
+		streams := seeds.Sub(p.Key())
+		seed := streams.Seed(rng.Init)
+		rnd := streams.Rand(rng.Shuffle)
+		replayType, gateType := p.String("replay"), p.String("gate")
+		net := createNetworkBIGTEST(replayType, seed, p.Int("hidden"), p.Int("max_replay"),
+			p.String("phase"), p.Int("offset"), gateType, p.Float("threshold"), p.Int("budget"))
+		shX := make([][][]float64, len(trainX))
+		shY := make([][][]float64, len(trainY))
+		for i, j := range rnd.Perm(len(trainX)) {
+			shX[i], shY[i] = trainX[j], trainY[j]
+		}
+		net.Train(shX, shY, epochs, lr, true, 5, -5)
+		return net, seed
+	}
+	params := func(p search.Point, epochs int, split string) map[string]any {
+		m := map[string]any{"epochs": epochs, "split": split}
+		for k, v := range p {
+			m[k] = v
+		}
+		return m
+	}
+	objective := func(p search.Point, epochs int) (float64, error) {
+		net, seed := train(p, epochs)
+		res := evaluateNet(net, valX, valY)
+		recordRun(p.Key(), params(p, epochs, "validation"), seed, res)
+		return res.Accuracy, nil
+	}
+
+	logPath := fmt.Sprintf("search-%s.jsonl", scheduler)
+	trialLog, err := search.OpenLog(logPath, ctx.Seed)
+	if err != nil {
+		return err
+	}
+	defer trialLog.Close()
+	if n := trialLog.Len(); n > 0 {
+		fmt.Printf("♻️  Resuming from %s (%d trials done)\n", logPath, n)
+	}
+
+	s := &search.Search{
+		Objective: objective,
+		Log:       trialLog,
+		OnTrial: func(t search.Trial) {
+			if t.Resumed {
+				return
+			}
+			if t.Err != "" {
+				fmt.Printf("❌ %s (%d epochs): %s\n", t.Key, t.Budget, t.Err)
+				return
+			}
+			fmt.Printf("🧠 %s (%d epochs): %.2f%% in %.0fs\n", t.Key, t.Budget, t.Score, t.Seconds)
+		},
+	}
+	var trials []search.Trial
+	switch scheduler {
+	case "grid":
+		trials, err = s.Grid(replaySpace, 3, maxEpochs)
+	case "random":
+		trials, err = s.Random(replaySpace, nRandom, maxEpochs, seeds.Rand("search"))
+	case "hyperband":
+		trials, err = s.Hyperband(replaySpace, maxEpochs, eta, seeds.Rand("search"))
+	default:
+		return fmt.Errorf("unknown scheduler %q (want grid, random or hyperband)", scheduler)
+	}
+	if err != nil {
+		return err
+	}
+
+	out := io.MultiWriter(os.Stdout, resultsFile)
+	title := fmt.Sprintf("REPLAY SETTINGS SEARCH (%s, %d trials)", scheduler, len(trials))
+	if err := search.WriteTrials(out, title, trials, 20); err != nil {
+		return err
+	}
+	best, ok := search.Best(trials)
+	if !ok {
+		return nil
+	}
+	fmt.Printf("🧠 Retraining %s for %d epochs to score it on the test set …\n", best.Key, best.Budget)
+	net, seed := train(best.Point, best.Budget)
+	res := evaluateNet(net, testX, testY)
+	recordRun(best.Key, params(best.Point, best.Budget, "test"), seed, res)
+	fmt.Fprintf(out, "🏆 Best: %s → %.2f%% validation, %.2f%% test after %d epochs\n",
+		best.Key, best.Score, res.Accuracy, best.Budget)
+	return nil
+}
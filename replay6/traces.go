@@ -0,0 +1,94 @@
+package main
+
+import (
+	"arena/trace"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Replay traces of the first run of each config go to
+// traces/<step>-<config>.jsonl, one line per gate decision.
+const traceDir = "traces"
+
+var (
+	tracesMu sync.Mutex
+	traces   = map[string]*trace.Tracer{} // config -> first run's tracer
+	traceAcc = map[string]float64{}       // config -> that run's accuracy
+)
+
+// keepTrace remembers the tracer of a config's first run and saves its
+// events
+func keepTrace(config string, runIdx int, tr *trace.Tracer, accuracy float64) {
+	if runIdx != 0 {
+		return
+	}
+	tracesMu.Lock()
+	traces[config], traceAcc[config] = tr, accuracy
+	tracesMu.Unlock()
+
+	recs := rec.StepRecords()
+	if len(recs) == 0 {
+		return
+	}
+	name := strings.NewReplacer(",", "_", "=", "-").Replace(config)
+	path := filepath.Join(traceDir, fmt.Sprintf("%s-%s.jsonl", recs[0].Step, name))
+	if err := os.MkdirAll(traceDir, 0755); err != nil {
+		log.Printf("Failed to write trace: %v", err)
+		return
+	}
+	if err := tr.Save(path); errors.Is(err, trace.ErrTruncated) {
+		fmt.Printf("⚠️ %v; raise trace.MaxEvents for the full trace\n", err)
+	} else if err != nil {
+		log.Printf("Failed to write trace: %v", err)
+	}
+}
+
+// writeTraces renders the kept traces: one row per config, then the
+// histograms and replay/correctness buckets of the most accurate config.
+// The kept traces are cleared.
+func writeTraces(title string) {
+	tracesMu.Lock()
+	defer tracesMu.Unlock()
+	if len(traces) == 0 {
+		return
+	}
+	names := make([]string, 0, len(traces))
+	for n := range traces {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	ts := make([]*trace.Tracer, len(names))
+	best := names[0]
+	for i, n := range names {
+		ts[i] = traces[n]
+		if traceAcc[n] > traceAcc[best] {
+			best = n
+		}
+	}
+
+	out := io.MultiWriter(os.Stdout, resultsFile)
+	if n := traces[best].Dropped(); n > 0 {
+		fmt.Fprintf(out, "⚠️ %s: %d gate decisions past trace.MaxEvents were dropped; the eval outcomes below are incomplete\n", best, n)
+	}
+	err := trace.WriteTable(out, title+": REPLAY TRACES (run 0)", names, ts)
+	if err == nil {
+		err = trace.WriteSummary(out, best+": GATE DECISIONS", traces[best].Summaries())
+	}
+	if err == nil {
+		err = trace.WriteHistograms(out, traces[best].Summaries())
+	}
+	if err == nil {
+		err = trace.WriteOutcomes(out, best+": REPLAYS VS CORRECTNESS", traces[best].Outcomes(trace.Eval))
+	}
+	if err != nil {
+		log.Printf("Failed to write results.txt: %v", err)
+	}
+	traces, traceAcc = map[string]*trace.Tracer{}, map[string]float64{}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"arena/datasets/cache"
+	"arena/results"
+	"arena/rng"
 	"bufio"
 	"fmt"
 	"log"
 	"math"
-	"net/http"
+	"math/rand"
 	"os"
 	"paragon"
 	"strings"
@@ -18,6 +21,7 @@ const (
 	dataDir             = "nlp_data"
 	dataFile            = "corpus.txt"
 	resultsFile         = "results.txt"
+	resultsData         = "results.jsonl"
 	vocab               = "abcdefghijklmnopqrstuvwxyz .,!?" // 30 characters
 	vocabSize           = 31                                // Aligned with paragon
 	inputWidth          = 100
@@ -35,12 +39,6 @@ const (
 	varianceThreshold   = 0.01
 )
 
-// Global file and mutex for thread-safe writing to results.txt
-var (
-	file   *os.File
-	fileMu sync.Mutex
-)
-
 // Character to index mapping
 var charToIndex map[rune]int
 
@@ -54,30 +52,22 @@ func init() {
 	log.Printf("vocabSize: %d, charToIndex['t']: %d, vocab: %q", vocabSize, charToIndex['t'], vocab)
 }
 
-// writeResult writes formatted output to results.txt with thread safety
-func writeResult(format string, args ...interface{}) {
-	fileMu.Lock()
-	defer fileMu.Unlock()
-	_, err := fmt.Fprintf(file, format, args...)
-	if err != nil {
-		log.Printf("Failed to write to results.txt: %v", err)
-	}
-	file.Sync()
-}
-
-// ensureTextCorpus downloads or loads a text corpus
+// ensureTextCorpus cleans the cached Gutenberg text into dir if needed
 func ensureTextCorpus(dir string) error {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 	path := fmt.Sprintf("%s/%s", dir, dataFile)
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		url := "https://www.gutenberg.org/files/11/11-0.txt"
-		resp, err := http.Get(url)
+		src, err := cache.Default().Fetch(cache.GutenbergAlice)
 		if err != nil {
-			return fmt.Errorf("failed to download corpus: %v", err)
+			return fmt.Errorf("failed to fetch corpus: %v", err)
 		}
-		defer resp.Body.Close()
+		in, err := os.Open(src)
+		if err != nil {
+			return fmt.Errorf("failed to open corpus: %v", err)
+		}
+		defer in.Close()
 
 		f, err := os.Create(path)
 		if err != nil {
@@ -85,7 +75,7 @@ func ensureTextCorpus(dir string) error {
 		}
 		defer f.Close()
 
-		scanner := bufio.NewScanner(resp.Body)
+		scanner := bufio.NewScanner(in)
 		for scanner.Scan() {
 			line := strings.ToLower(scanner.Text())
 			var cleaned strings.Builder
@@ -432,14 +422,18 @@ func computePerplexity(net *paragon.Network[float32], inputs [][][]float64, targ
 }
 
 func main() {
-	var err error
-	file, err = os.OpenFile(resultsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	// every config's weights and the split derive from one master seed;
+	// rerun with ARENA_SEED to reproduce a run
+	seeds := rng.FromEnv()
+	defer seeds.Save("replay7", "")
+	rand.Seed(seeds.Seed(rng.Global))
+
+	sink, err := results.Open(resultsData)
 	if err != nil {
-		log.Fatalf("Failed to open results.txt: %v", err)
+		log.Fatalf("Failed to open %s: %v", resultsData, err)
 	}
-	defer file.Close()
-
-	writeResult("\n=== NLP Test Run: %s ===\n", time.Now().Format("2006-01-02 15:04:05"))
+	rec := results.NewRecorder(sink, results.NewRun("replay7", seeds.Master(), nil))
+	defer rec.Close()
 
 	if err := ensureTextCorpus(dataDir); err != nil {
 		log.Fatalf("Text corpus error: %v", err)
@@ -469,16 +463,7 @@ func main() {
 	sem := make(chan struct{}, maxWorkers)
 	var wg sync.WaitGroup
 
-	type result struct {
-		replayType string
-		configDesc string
-		epoch      int
-		perplexity float64
-		chunks     []string
-	}
-	resultsCh := make(chan result, len(configs)*epochs)
-
-	for _, cfg := range configs {
+	for i, cfg := range configs {
 		wg.Add(1)
 		go func(cfg struct {
 			replayType   string
@@ -486,12 +471,11 @@ func main() {
 			threshold    float64
 			replayBudget int
 			replayPhase  string
-		}) {
+		}, seed int64) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			seed := time.Now().UnixNano()
 			var net *paragon.Network[float32]
 			configDesc := cfg.replayType
 			if cfg.replayType == "dynamic" {
@@ -574,27 +558,31 @@ func main() {
 				}
 
 				fmt.Printf("Epoch %d, %s (%s): Perplexity=%.2f\nChunks:\n%s\n", epoch+1, cfg.replayType, configDesc, perp, strings.Join(chunks, "\n---\n"))
-				resultsCh <- result{
-					replayType: cfg.replayType,
-					configDesc: configDesc,
-					epoch:      epoch,
-					perplexity: perp,
-					chunks:     chunks,
+				err := rec.Write(results.Record{
+					Kind:    results.KindEpoch,
+					Model:   configDesc,
+					Params:  map[string]any{"replay": cfg.replayType, "gate": cfg.gateType, "threshold": cfg.threshold, "budget": cfg.replayBudget, "phase": cfg.replayPhase},
+					Seed:    seed,
+					Epoch:   epoch + 1,
+					Metrics: map[string]float64{"perplexity": perp},
+					Samples: chunks,
+				})
+				if err != nil {
+					log.Printf("Failed to record epoch: %v", err)
 				}
 			}
-		}(cfg)
+		}(cfg, seeds.Subf("config=%d", i).Seed(rng.Init))
 	}
+	wg.Wait()
 
-	go func() {
-		wg.Wait()
-		close(resultsCh)
-	}()
-
-	resultsByConfig := make(map[string][]result)
-	for res := range resultsCh {
-		key := fmt.Sprintf("%s|%s", res.replayType, res.configDesc)
-		resultsByConfig[key] = append(resultsByConfig[key], res)
+	// Render the epoch tables from the records, in config order
+	file, err := os.OpenFile(resultsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Fatalf("Failed to open results.txt: %v", err)
 	}
+	defer file.Close()
+	fmt.Fprintf(file, "\n=== NLP Test Run: %s (run %s, seed %d) ===\n",
+		time.Now().Format("2006-01-02 15:04:05"), rec.Run().ID, seeds.Master())
 
 	for _, cfg := range configs {
 		configDesc := cfg.replayType
@@ -606,16 +594,14 @@ func main() {
 		} else {
 			configDesc = "NoReplay"
 		}
-		key := fmt.Sprintf("%s|%s", cfg.replayType, configDesc)
-		results := resultsByConfig[key]
-
-		writeResult("\n========= EPOCH-WISE NLP RESULTS (%s) =========\n", configDesc)
-		writeResult("Epoch | Perplexity | Sample Chunks\n")
-		writeResult("---------------------------------------------\n")
-		for _, res := range results {
-			writeResult("%5d | %10.2f | \n%s\n",
-				res.epoch+1, res.perplexity, strings.Join(res.chunks, "\n---\n"))
+		var recs []results.Record
+		for _, r := range rec.Records() {
+			if r.Model == configDesc {
+				recs = append(recs, r)
+			}
+		}
+		if err := results.WriteEpochs(file, "EPOCH-WISE NLP RESULTS", recs); err != nil {
+			log.Printf("Failed to write results.txt: %v", err)
 		}
-		writeResult("=============================================\n")
 	}
 }
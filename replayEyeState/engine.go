@@ -10,6 +10,9 @@ import (
 	"strconv"
 	"strings"
 
+	"arena/eval"
+	"arena/gates"
+	"arena/results"
 	"paragon"
 )
 
@@ -28,13 +31,6 @@ type sample struct {
 	y []float64
 }
 
-type result struct {
-	score   float64
-	buckets map[string]int
-	exp     []float64
-	pred    []float64
-}
-
 func main() {
 	rand.Seed(fixedSeed)
 	fmt.Println("📊 Loading EEG data...")
@@ -52,7 +48,7 @@ func main() {
 	fmt.Println("\n🧠 Training Standard Model")
 	netStd := buildModel()
 	netStd.Train(X, Y, epochs, learningRate, false)
-	resStd := evaluate(netStd, valX, valY)
+	resStd := eval.Evaluate(netStd, valX, valY)
 
 	// ────────── Static Replay ──────────
 	fmt.Println("\n🧠 Training Static Replay Model")
@@ -64,7 +60,7 @@ func main() {
 		layer.ReplayPhase = "before"
 	}
 	netStatic.Train(X, Y, epochs, learningRate, false)
-	resStatic := evaluate(netStatic, valX, valY)
+	resStatic := eval.Evaluate(netStatic, valX, valY)
 
 	// ────────── Dynamic Replay ──────────
 	fmt.Println("\n🧠 Training Dynamic Replay Model")
@@ -73,78 +69,42 @@ func main() {
 		layer := &netDyn.Layers[l]
 		layer.ReplayEnabled = true
 		layer.ReplayBudget = 20
-		layer.ReplayGateFunc = entropyGate(layer)
-		layer.ReplayGateToReps = func(e float64) int {
-			return 1 + int(math.Pow(e, 1.1)*float64(layer.ReplayBudget-1))
-		}
+		layer.ReplayGateFunc = gates.Entropy(layer)
+		layer.ReplayGateToReps = gates.Power(layer.ReplayBudget, 1.1)
 	}
 	netDyn.Train(X, Y, epochs, learningRate, false)
-	resDyn := evaluate(netDyn, valX, valY)
+	resDyn := eval.Evaluate(netDyn, valX, valY)
 
 	// ────────── ADHD Comparison ──────────
 	fmt.Println("\n============== ADHD COMPARISON ==============")
 	fmt.Printf("Metric                     | Standard | Static  | Dynamic\n")
 	fmt.Printf("---------------------------+----------+---------+---------\n")
 	fmt.Printf("ADHD Score                 | %8.2f | %7.2f | %7.2f\n",
-		resStd.score, resStatic.score, resDyn.score)
+		resStd.Score(), resStatic.Score(), resDyn.Score())
 
-	keys := []string{"0-10%", "10-20%", "20-30%", "30-40%", "40-50%", "50-100%", "100%+"}
 	fmt.Println("\nDeviation buckets (# samples):")
-	for _, k := range keys {
+	for _, k := range results.BucketKeys {
 		fmt.Printf(" %-7s | %4d | %4d | %4d\n",
-			k, resStd.buckets[k], resStatic.buckets[k], resDyn.buckets[k])
+			k, resStd.ADHD.Buckets[k], resStatic.ADHD.Buckets[k], resDyn.ADHD.Buckets[k])
 	}
 
+	eval.WriteTable(os.Stdout, "CLASSIFICATION METRICS", []string{"Standard", "Static", "Dynamic"},
+		[]eval.Result{resStd, resStatic, resDyn})
+
 	// ────────── Diagnostics ──────────
 	fmt.Println("\n------ FULL DIAGNOSTICS: STANDARD ----------")
-	netStd.EvaluateFull(resStd.exp, resStd.pred)
+	netStd.EvaluateFull(resStd.Expected, resStd.Predicted)
 	netStd.PrintFullDiagnostics()
 
 	fmt.Println("\n------ FULL DIAGNOSTICS: STATIC REPLAY ----------")
-	netStatic.EvaluateFull(resStatic.exp, resStatic.pred)
+	netStatic.EvaluateFull(resStatic.Expected, resStatic.Predicted)
 	netStatic.PrintFullDiagnostics()
 
 	fmt.Println("\n------ FULL DIAGNOSTICS: DYNAMIC REPLAY ----------")
-	netDyn.EvaluateFull(resDyn.exp, resDyn.pred)
+	netDyn.EvaluateFull(resDyn.Expected, resDyn.Predicted)
 	netDyn.PrintFullDiagnostics()
 }
 
-// ───── Evaluation ─────
-
-func evaluate(net *paragon.Network, X, Y [][][]float64) result {
-	exp, pred := []float64{}, []float64{}
-	for i := range X {
-		net.Forward(X[i])
-		net.ApplySoftmax()
-		p := float64(paragon.ArgMax(net.ExtractOutput()))
-		t := float64(paragon.ArgMax(Y[i][0]))
-		pred = append(pred, p)
-		exp = append(exp, t)
-	}
-	net.EvaluateModel(exp, pred)
-	buckets := make(map[string]int)
-	for k, v := range net.Performance.Buckets {
-		buckets[k] = v.Count
-	}
-	return result{net.Performance.Score, buckets, exp, pred}
-}
-
-func entropyGate(layer *paragon.Grid) func([][]float64) float64 {
-	return func(_ [][]float64) float64 {
-		if len(layer.CachedOutputs) == 0 {
-			return 0.0
-		}
-		out := paragon.Softmax(layer.CachedOutputs)
-		h := 0.0
-		for _, p := range out {
-			if p > 0 {
-				h -= p * math.Log2(p)
-			}
-		}
-		return h / math.Log2(float64(len(out)))
-	}
-}
-
 // ───── Model Setup ─────
 
 func buildModel() *paragon.Network {
@@ -2,6 +2,11 @@ module main
 
 go 1.24.0
 
-require paragon v0.0.0
+require (
+	arena v0.0.0
+	paragon v0.0.0
+)
 
 replace paragon => ../../
+
+replace arena => ../arena
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"arena/experiment"
+	"arena/plot"
+	"arena/results"
+	"arena/study"
+	"arena/tasks"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Structured results go to resultsData; results.txt gets the tables and
+// figures/ the accuracy heatmap.
+const (
+	resultsData = "results.jsonl"
+	figureDir   = "figures"
+	alpha       = 0.05
+)
+
+var (
+	rec         *results.Recorder
+	resultsFile *os.File
+)
+
+// modes is the configuration matrix every dataset goes through; the first
+// is what the others are judged against.
+var modes = []tasks.Mode{
+	tasks.Baseline(),
+	tasks.Static(1),
+	tasks.Static(3),
+	tasks.Dynamic("entropy", 0.5, 2),
+	tasks.Dynamic("margin", 0.5, 2),
+}
+
+func main() {
+	experiment.Before(openResults)
+	experiment.Register("crossDataset", "the replay matrix on every registered dataset: run crossDataset [dataset...]", crossDataset)
+	experiment.Main("replayStudy")
+}
+
+// openResults opens the results sink and results.txt
+func openResults(ctx *experiment.Context) error {
+	var err error
+	rec, resultsFile, err = ctx.OpenResults(resultsData, "results.txt")
+	return err
+}
+
+// selectDatasets returns the registered datasets named in args, or all
+func selectDatasets(args []string) ([]study.Dataset, error) {
+	if len(args) == 0 {
+		return study.Datasets(), nil
+	}
+	var ds []study.Dataset
+	for _, name := range args {
+		d, ok := study.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown dataset %q", name)
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
+}
+
+func crossDataset(ctx *experiment.Context) error {
+	rec.SetStep(ctx.Step)
+	ds, err := selectDatasets(ctx.Args)
+	if err != nil {
+		return err
+	}
+	for _, d := range ds {
+		fmt.Printf("🗂️ %s: %s\n", d.Name, d.Doc)
+	}
+	scores, skipped, err := study.Run(ds, modes, study.Protocol{
+		Options: tasks.Options{Seeds: ctx.Seeds.Sub(ctx.Step), Recorder: rec},
+	})
+	if err != nil {
+		return err
+	}
+	if len(scores) == 0 {
+		return fmt.Errorf("no dataset could be loaded (%d skipped)", len(skipped))
+	}
+
+	out := io.MultiWriter(os.Stdout, resultsFile)
+	for _, s := range skipped {
+		fmt.Fprintf(out, "⚠️ Skipped %s: %v\n", s.Dataset, s.Err)
+	}
+	if err := tasks.WriteTable(out, "CROSS-DATASET REPLAY STUDY: ACCURACY %", "accuracy", scores); err != nil {
+		return err
+	}
+	if err := study.WriteVerdict(out, "CROSS-DATASET REPLAY STUDY: VS "+modes[0].Name, "accuracy", scores, alpha); err != nil {
+		return err
+	}
+
+	c := plot.Grid(rec.StepRecords(), "accuracy", plot.Param("task"), plot.Param("mode"))
+	c.Title = "CROSS-DATASET REPLAY STUDY: TEST ACCURACY"
+	path := filepath.Join(figureDir, ctx.Step+"-accuracy.png")
+	if err := plot.Save(path, c); err != nil {
+		return err
+	}
+	fmt.Printf("📈 Saved %s\n", path)
+	return nil
+}
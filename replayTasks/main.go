@@ -0,0 +1,77 @@
+package main
+
+import (
+	"arena/experiment"
+	"arena/gates"
+	"arena/results"
+	"arena/tasks"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Structured results go to resultsData; results.txt gets the tables.
+const resultsData = "results.jsonl"
+
+var (
+	rec         *results.Recorder
+	resultsFile *os.File
+)
+
+func main() {
+	experiment.Before(openResults)
+	experiment.Register("battery", "baseline, static and entropy-gated replay on every synthetic task: run battery [task...]", battery)
+	experiment.Optional("gates", "dynamic replay with every named gate on every synthetic task: run gates [task...]", allGates)
+	experiment.Main("replayTasks")
+}
+
+// openResults opens the results sink and results.txt
+func openResults(ctx *experiment.Context) error {
+	var err error
+	rec, resultsFile, err = ctx.OpenResults(resultsData, "results.txt")
+	return err
+}
+
+// selectTasks returns the default tasks named in args, or all of them
+func selectTasks(args []string) ([]tasks.Task, error) {
+	if len(args) == 0 {
+		return tasks.Defaults(), nil
+	}
+	var ts []tasks.Task
+	for _, name := range args {
+		t, ok := tasks.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown task %q (have %v)", name, tasks.Names())
+		}
+		ts = append(ts, t)
+	}
+	return ts, nil
+}
+
+func battery(ctx *experiment.Context) error {
+	return runBattery(ctx, "SYNTHETIC TASK BATTERY", tasks.DefaultModes())
+}
+
+func allGates(ctx *experiment.Context) error {
+	modes := []tasks.Mode{tasks.Baseline()}
+	for _, g := range gates.Names() {
+		modes = append(modes, tasks.Dynamic(g, 0.5, 2))
+	}
+	return runBattery(ctx, "SYNTHETIC TASKS: EVERY GATE", modes)
+}
+
+func runBattery(ctx *experiment.Context, title string, modes []tasks.Mode) error {
+	rec.SetStep(ctx.Step)
+	ts, err := selectTasks(ctx.Args)
+	if err != nil {
+		return err
+	}
+	for _, t := range ts {
+		fmt.Printf("🧩 %s: %s\n", t.Name(), t.Doc())
+	}
+	scores, err := tasks.Run(ts, modes, tasks.Options{Seeds: ctx.Seeds.Sub(ctx.Step), Recorder: rec})
+	if err != nil {
+		return err
+	}
+	return tasks.WriteTable(io.MultiWriter(os.Stdout, resultsFile), title+": ACCURACY %", "accuracy", scores)
+}
@@ -1,16 +1,18 @@
 package main
 
 import (
+	"arena/rng"
 	"fmt"
 	"math"
 	"math/rand"
 	"paragon"
 	"strings"
-	"time"
 )
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("smalldiffpoc1", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	// Sample dataset
 	sentences := []string{
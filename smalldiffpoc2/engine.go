@@ -1,16 +1,18 @@
 package main
 
 import (
+	"arena/rng"
 	"fmt"
 	"math"
 	"math/rand"
 	"paragon" // Assuming this is a custom neural network library
 	"strings"
-	"time"
 )
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("smalldiffpoc2", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	// Expanded dataset with 100 famous quotes for better diversity
 	sentences := []string{
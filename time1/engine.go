@@ -2,13 +2,12 @@ package main
 
 import (
 	"fmt"
-	"io"
 	"math"
 	"math/rand"
-	"net/http"
 	"os"
-	"time"
 
+	"arena/datasets/cache"
+	"arena/rng"
 	"paragon"
 
 	"github.com/gocarina/gocsv"
@@ -37,36 +36,14 @@ func discretizePriceChange(prev, curr float64) int {
 
 // fetchStockData downloads daily stock data and returns it as a slice of StockData
 func fetchStockData(symbol, apiKey string) ([]StockData, error) {
-	url := fmt.Sprintf("https://www.alphavantage.co/query?function=TIME_SERIES_DAILY&symbol=%s&apikey=%s&datatype=csv", symbol, apiKey)
-	resp, err := http.Get(url)
+	path, err := cache.Default().Fetch(cache.AlphaVantageDaily(symbol, apiKey))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch data: %v", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
-	}
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
-	}
-
-	// Save to temporary file for CSV parsing
-	tmpFile, err := os.CreateTemp("", "stockdata-*.csv")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create temp file: %v", err)
-	}
-	defer os.Remove(tmpFile.Name())
-	if _, err := tmpFile.Write(data); err != nil {
-		return nil, fmt.Errorf("failed to write to temp file: %v", err)
-	}
-	tmpFile.Close()
-
-	file, err := os.Open(tmpFile.Name())
+	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open temp file: %v", err)
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
 	}
 	defer file.Close()
 
@@ -100,7 +77,9 @@ func prepareTrainingData(stockData []StockData, seqLength int) [][]int {
 }
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("time1", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	// Configuration
 	symbol := "AAPL"                   // Apple stock
@@ -2,13 +2,13 @@ package main
 
 import (
 	"fmt"
-	"io"
 	"math"
 	"math/rand"
-	"net/http"
 	"os"
 	"time"
 
+	"arena/datasets/cache"
+	"arena/rng"
 	"paragon"
 
 	"github.com/gocarina/gocsv"
@@ -35,27 +35,6 @@ func discretizePriceChange(prev, curr float64) int {
 	return 1
 }
 
-func downloadStockData(url, filename string) error {
-	resp, err := http.Get(url)
-	if err != nil {
-		return fmt.Errorf("failed to download %s: %v", url, err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
-
-	out, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %v", filename, err)
-	}
-	defer out.Close()
-
-	_, err = io.Copy(out, resp.Body)
-	return err
-}
-
 func loadStockData(filename string) ([]StockData, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -99,21 +78,18 @@ func prepareTrainingData(stockData []StockData, seqLength int) [][]int {
 }
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("time3", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
-	filename := "vix-daily.csv"
-	dataURL := "https://raw.githubusercontent.com/datasets/finance-vix/main/data/vix-daily.csv"
 	seqLength := 20
 
 	os.Remove("AAPL.csv")
 
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		fmt.Println("Downloading stock data...")
-		if err := downloadStockData(dataURL, filename); err != nil {
-			fmt.Printf("Error downloading data: %v\nFalling back to local file or exiting.\n", err)
-			return
-		}
-		fmt.Println("Download complete.")
+	filename, err := cache.Default().Fetch(cache.VIXDaily)
+	if err != nil {
+		fmt.Printf("Error fetching data: %v\n", err)
+		return
 	}
 
 	fmt.Println("Loading stock data...")
@@ -2,13 +2,12 @@ package main
 
 import (
 	"fmt"
-	"io"
 	"math"
 	"math/rand"
-	"net/http"
 	"os"
-	"time"
 
+	"arena/datasets/cache"
+	"arena/rng"
 	"paragon"
 
 	"github.com/gocarina/gocsv"
@@ -37,28 +36,6 @@ func discretizePriceChange(prev, curr float64) int {
 	return 1 // Flat
 }
 
-// downloadStockData downloads a CSV from a URL
-func downloadStockData(url, filename string) error {
-	resp, err := http.Get(url)
-	if err != nil {
-		return fmt.Errorf("failed to download %s: %v", url, err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
-
-	out, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %v", filename, err)
-	}
-	defer out.Close()
-
-	_, err = io.Copy(out, resp.Body)
-	return err
-}
-
 // loadStockData reads the CSV file
 func loadStockData(filename string) ([]StockData, error) {
 	file, err := os.Open(filename)
@@ -122,23 +99,20 @@ func prepareTrainingData(stockData []StockData, seqLength int) (trainData, testD
 }
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	seeds := rng.FromEnv()
+	defer seeds.Save("time4", "")
+	rand.Seed(seeds.Seed(rng.Global))
 
 	// Configuration
-	filename := "vix-daily.csv"
-	dataURL := "https://raw.githubusercontent.com/datasets/finance-vix/main/data/vix-daily.csv"
 	seqLength := 30
 
 	// Remove any stale AAPL.csv
 	os.Remove("AAPL.csv")
 
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		fmt.Println("Downloading stock data...")
-		if err := downloadStockData(dataURL, filename); err != nil {
-			fmt.Printf("Error downloading data: %v\nFalling back to local file or exiting.\n", err)
-			return
-		}
-		fmt.Println("Download complete.")
+	filename, err := cache.Default().Fetch(cache.VIXDaily)
+	if err != nil {
+		fmt.Printf("Error fetching data: %v\n", err)
+		return
 	}
 
 	fmt.Println("Loading stock data...")
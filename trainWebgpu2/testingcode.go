@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"arena/datasets/mnist"
+	"arena/eval"
 	"paragon"
 
 	"github.com/openfluke/pilot"
@@ -40,13 +42,13 @@ func main() {
 	// Load MNIST data
 	fmt.Println("⚙ Stage: MNIST Dataset Prep")
 	startData := time.Now()
-	mnist := experiments.NewMNISTDatasetStage("./data/mnist")
-	exp := pilot.NewExperiment("MNIST", mnist)
+	stage := experiments.NewMNISTDatasetStage("./data/mnist")
+	exp := pilot.NewExperiment("MNIST", stage)
 	if err := exp.RunAll(); err != nil {
 		fmt.Println("❌ Experiment failed:", err)
 		os.Exit(1)
 	}
-	allInputs, allTargets, err := loadMNISTData("./data/mnist")
+	allInputs, allTargets, err := mnist.LoadAll("./data/mnist", mnist.OneHot)
 	if err != nil {
 		fmt.Println("❌ Failed to load MNIST:", err)
 		return
@@ -237,28 +239,11 @@ func evaluateNetwork[T paragon.Numeric](nn *paragon.Network[T], trainInputs, tra
 	startEval := time.Now()
 
 	// Evaluate training set
-	expectedTrain := make([]float64, len(trainInputs))
-	actualTrain := make([]float64, len(trainInputs))
-	for i := range trainInputs {
-		nn.Forward(trainInputs[i])
-		out := nn.ExtractOutput()
-		expectedTrain[i] = float64(paragon.ArgMax(trainTargets[i][0]))
-		actualTrain[i] = float64(paragon.ArgMax(out))
-	}
-	nn.EvaluateModel(expectedTrain, actualTrain)
-	trainScore := nn.Performance.Score
+	trainScore := eval.Evaluate(nn, trainInputs, trainTargets).Score()
 
 	// Evaluate test set
-	expectedTest := make([]float64, len(testInputs))
-	actualTest := make([]float64, len(testInputs))
-	for i := range testInputs {
-		nn.Forward(testInputs[i])
-		out := nn.ExtractOutput()
-		expectedTest[i] = float64(paragon.ArgMax(testTargets[i][0]))
-		actualTest[i] = float64(paragon.ArgMax(out))
-	}
-	nn.EvaluateModel(expectedTest, actualTest)
-	testScore := nn.Performance.Score
+	test := eval.Evaluate(nn, testInputs, testTargets, 3)
+	testScore := test.Score()
 
 	evalTime := time.Since(startEval)
 
@@ -266,6 +251,8 @@ func evaluateNetwork[T paragon.Numeric](nn *paragon.Network[T], trainInputs, tra
 	fmt.Printf("\n📈 ADHD Performance (%s Network):\n", typeName)
 	fmt.Printf("- Train Score: %.4f%%\n", trainScore)
 	fmt.Printf("- Test Score: %.4f%%\n", testScore)
+	fmt.Printf("- Test Accuracy: %.2f%% (top-3 %.2f%%), macro-F1 %.4f, log-loss %.4f\n",
+		test.Accuracy, test.TopK[3], test.MacroF1, test.LogLoss)
 	fmt.Printf("- Test Set Breakdown:\n")
 	for name, bucket := range nn.Performance.Buckets {
 		if bucket.Count > 0 {
@@ -288,23 +275,18 @@ func evaluateNetwork[T paragon.Numeric](nn *paragon.Network[T], trainInputs, tra
 func evaluateNetworkCPUOnly[T paragon.Numeric](nn *paragon.Network[T], testInputs, testTargets [][][]float64, typeName string) NetworkResult {
 	startEval := time.Now()
 
-	// Only evaluate test set for converted networks (CPU only)
-	expectedTest := make([]float64, len(testInputs))
-	actualTest := make([]float64, len(testInputs))
-	for i := range testInputs {
-		nn.Forward(testInputs[i]) // This will use CPU since WebGPUNative is false
-		out := nn.ExtractOutput()
-		expectedTest[i] = float64(paragon.ArgMax(testTargets[i][0]))
-		actualTest[i] = float64(paragon.ArgMax(out))
-	}
-	nn.EvaluateModel(expectedTest, actualTest)
-	testScore := nn.Performance.Score
+	// Only evaluate test set for converted networks (CPU only, since
+	// WebGPUNative is false)
+	test := eval.Evaluate(nn, testInputs, testTargets, 3)
+	testScore := test.Score()
 
 	evalTime := time.Since(startEval)
 
 	// Print detailed ADHD assessment
 	fmt.Printf("\n📈 ADHD Performance (%s Network - CPU Only):\n", typeName)
 	fmt.Printf("- Test Score: %.4f%%\n", testScore)
+	fmt.Printf("- Test Accuracy: %.2f%% (top-3 %.2f%%), macro-F1 %.4f, log-loss %.4f\n",
+		test.Accuracy, test.TopK[3], test.MacroF1, test.LogLoss)
 	fmt.Printf("- Test Set Breakdown:\n")
 	for name, bucket := range nn.Performance.Buckets {
 		if bucket.Count > 0 {
@@ -6,11 +6,11 @@ import (
 	"sync"
 	"time"
 
+	"arena/datasets/mnist"
 	"paragon"
 )
 
 const (
-	baseURL   = "https://storage.googleapis.com/cvdf-datasets/mnist/"
 	mnistDir  = "mnist_data"
 	numEpochs = 5
 	learnRate = 0.01
@@ -39,11 +39,11 @@ var (
 
 func main() {
 	// --- Load MNIST ---
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	trainInputs, trainTargets, _ := loadMNISTData(mnistDir, true)
-	testInputs, testTargets, _ := loadMNISTData(mnistDir, false)
+	trainInputs, trainTargets, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testInputs, testTargets, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	trainSetInputs, trainSetTargets, _, _ := paragon.SplitDataset(trainInputs, trainTargets, 0.8)
 
 	// Launch and save all trained models
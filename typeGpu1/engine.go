@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"arena/datasets/mnist"
 	"paragon"
 )
 
@@ -39,16 +40,16 @@ var (
 
 func main() {
 	// --- Load MNIST ---
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	//trainInputs, trainTargets, _ := loadMNISTData(mnistDir, true)
-	testInputs, testTargets, _ := loadMNISTData(mnistDir, false)
+	//trainInputs, trainTargets, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testInputs, testTargets, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	//trainSetInputs, trainSetTargets, _, _ := paragon.SplitDataset(trainInputs, trainTargets, 0.8)
 
 	//trainSetInputs, trainSetTargets, _, _ := paragon.SplitDataset(trainInputs, trainTargets, 0.8)
 
-	trainInputs, trainTargets, _ := loadMNISTData(mnistDir, true)
+	trainInputs, trainTargets, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
 
 	runAll(trainInputs, trainTargets, testInputs, testTargets)
 	wg.Wait()
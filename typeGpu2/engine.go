@@ -8,6 +8,8 @@ import (
 	"sync"
 	"time"
 
+	"arena/datasets/mnist"
+	"arena/eval"
 	"paragon"
 )
 
@@ -283,14 +285,14 @@ func main() {
 	return
 	//return
 	// --- Load MNIST ---
-	if err := ensureMNISTDownloads(mnistDir); err != nil {
+	if err := mnist.Ensure(mnistDir); err != nil {
 		log.Fatalf("MNIST download error: %v", err)
 	}
-	//trainInputs, trainTargets, _ := loadMNISTData(mnistDir, true)
-	testInputs, testTargets, _ := loadMNISTData(mnistDir, false)
+	//trainInputs, trainTargets, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
+	testInputs, testTargets, _ := mnist.Load(mnistDir, mnist.Test, mnist.OneHot)
 	//trainSetInputs, trainSetTargets, _, _ := paragon.SplitDataset(trainInputs, trainTargets, 0.8)
 
-	//	trainInputs, trainTargets, _ := loadMNISTData(mnistDir, true)
+	//	trainInputs, trainTargets, _ := mnist.Load(mnistDir, mnist.Train, mnist.OneHot)
 
 	//	runAll(trainInputs, trainTargets, testInputs, testTargets)
 	gpuOn = true
@@ -409,18 +411,8 @@ func trainAndEvaluate[T paragon.Numeric](
 	nn.Train(trainInputs, trainTargets, numEpochs, learnRate, true, clipUpper, clipLower)
 
 	// Evaluate trained model (inference timing)
-	var expected, predicted []float64
 	startEval := time.Now()
-	for i := range testInputs {
-		nn.Forward(testInputs[i])
-		nn.ApplySoftmax()
-		out := nn.ExtractOutput()
-		pred := paragon.ArgMax(out)
-		label := paragon.ArgMax(testTargets[i][0])
-		expected = append(expected, float64(label))
-		predicted = append(predicted, float64(pred))
-	}
-	nn.EvaluateModel(expected, predicted)
+	eval.Evaluate(nn, testInputs, testTargets)
 	origScore := nn.Performance.Score
 	evalElapsed := time.Since(startEval)
 
@@ -508,18 +500,8 @@ func evalLoadedHelper[T paragon.Numeric](
 
 		defer nn.CleanupOptimizedGPU()
 	}
-	var expected, predicted []float64
 	start := time.Now()
-	for i := range testInputs {
-		nn.Forward(testInputs[i])
-		nn.ApplySoftmax()
-		out := nn.ExtractOutput()
-		pred := paragon.ArgMax(out)
-		label := paragon.ArgMax(testTargets[i][0])
-		expected = append(expected, float64(label))
-		predicted = append(predicted, float64(pred))
-	}
-	nn.EvaluateModel(expected, predicted)
+	eval.Evaluate(nn, testInputs, testTargets)
 	elapsed := time.Since(start)
 	fmt.Println(elapsed)
 	return nn.Performance.Score, elapsed